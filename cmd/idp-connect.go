@@ -2,25 +2,38 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
+	"github.com/solo-io/gloo-portal-idp-connect/internal/auth0"
 	"github.com/solo-io/gloo-portal-idp-connect/internal/cognito"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/dcr"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/entra"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/hydra"
 	"github.com/solo-io/gloo-portal-idp-connect/internal/keycloak"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/memory"
 	"github.com/solo-io/gloo-portal-idp-connect/internal/okta"
 	"github.com/solo-io/gloo-portal-idp-connect/internal/version"
 )
 
 func main() {
-	ctx := context.Background()
+	// Cancel the context on SIGTERM, such as during a Kubernetes rollout, so that the server drains in-flight requests.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-	if err := rootCommand(ctx).Execute(); err != nil {
-		log.Fatal(err)
+	err := rootCommand().ExecuteContext(ctx)
+	stop()
+
+	if err != nil {
+		slog.Error("IdP Connect failed", "error", err)
+		os.Exit(1)
 	}
 }
 
-func rootCommand(ctx context.Context) *cobra.Command {
+func rootCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Short:   "IDP Connect sample implementations",
 		Version: version.Version,
@@ -29,8 +42,13 @@ func rootCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		auth0.Command(),
 		cognito.Command(),
+		dcr.Command(),
+		entra.Command(),
+		hydra.Command(),
 		keycloak.Command(),
+		memory.Command(),
 		okta.Command(),
 	)
 
@@ -10,20 +10,100 @@ app: {{ .Values.fullname }}
 gloo-portal-idp-connect args command
 */}}
 {{- define "gloo-portal-idp-connect.cmd.args" -}}
-{{- if eq .Values.connector "cognito"}}
+{{- if eq .Values.connector "auth0"}}
+  - auth0
+  - --port=8080
+  - --domain={{ .Values.auth0.domain }}
+  - --client-id={{ .Values.auth0.mgmtClientId }}
+  - --audience={{ .Values.auth0.audience }}
+{{- else if eq .Values.connector "cognito"}}
   - cognito
   - --port=8080
   - --user-pool-id={{ .Values.cognito.userPoolId }}
   - --resource-server={{ .Values.cognito.resourceServer }}
+{{- else if eq .Values.connector "dcr"}}
+  - dcr
+  - --port=8080
+  - --issuer={{ .Values.dcr.issuer }}
+  - --registrations-file=/var/lib/idp-connect/registrations.json
+{{- else if eq .Values.connector "entra"}}
+  - entra
+  - --port=8080
+  - --tenant-id={{ .Values.entra.tenantId }}
+  - --client-id={{ .Values.entra.mgmtClientId }}
+{{- else if eq .Values.connector "hydra"}}
+  - hydra
+  - --port=8080
+  - --admin-url={{ .Values.hydra.adminUrl }}
 {{- else if eq .Values.connector "keycloak"}}
   - keycloak
   - --port=8080
   - --issuer={{ .Values.keycloak.realm }}
   - --client-id={{ .Values.keycloak.mgmtClientId }}
   - --client-secret={{ .Values.keycloak.mgmtClientSecret }}
+{{- else if eq .Values.connector "memory"}}
+  - memory
+  - --port=8080
+  - --token-port={{ .Values.memory.tokenPort }}
+  {{- if .Values.memory.issuer }}
+  - --issuer={{ .Values.memory.issuer }}
+  {{- end }}
+  {{- if .Values.memory.audience }}
+  - --audience={{ .Values.memory.audience }}
+  {{- end }}
 {{- else if eq .Values.connector "okta"}}
   - okta
   - --port=8080
   - --okta-domain={{ .Values.okta.domain }}
+  - --authorization-server={{ .Values.okta.authorizationServer }}
+{{- end }}
+  - --shutdown-grace-period={{ .Values.shutdownGracePeriod }}
+{{- if .Values.forceDelete }}
+  - --force-delete
+{{- end }}
+  - --log-format={{ .Values.logFormat }}
+  - --log-level={{ .Values.logLevel }}
+{{- if .Values.otlpEndpoint }}
+  - --otlp-endpoint={{ .Values.otlpEndpoint }}
+{{- end }}
+{{- if .Values.upstreamTimeout }}
+  - --upstream-timeout={{ .Values.upstreamTimeout }}
+{{- end }}
+{{- with .Values.upstreamRetry }}
+{{- if .maxAttempts }}
+  - --upstream-max-attempts={{ .maxAttempts }}
+{{- end }}
+{{- if .baseDelay }}
+  - --upstream-retry-base-delay={{ .baseDelay }}
+{{- end }}
+{{- if .maxDelay }}
+  - --upstream-retry-max-delay={{ .maxDelay }}
+{{- end }}
+{{- end }}
+{{- with .Values.circuitBreaker }}
+{{- if ne (toString .threshold) "" }}
+  - --circuit-breaker-threshold={{ .threshold }}
+{{- end }}
+{{- if .openDuration }}
+  - --circuit-breaker-open-duration={{ .openDuration }}
+{{- end }}
+{{- end }}
+{{- if .Values.tls.secretName }}
+  - --tls-cert-file=/etc/idp-connect/tls/tls.crt
+  - --tls-key-file=/etc/idp-connect/tls/tls.key
+{{- if .Values.tls.requireClientCert }}
+  - --client-ca-file=/etc/idp-connect/tls/ca.crt
+{{- end }}
+{{- end }}
+{{- with .Values.tokenVerification }}
+{{- if .jwksUrl }}
+  - --jwks-url={{ .jwksUrl }}
+{{- end }}
+{{- if .issuer }}
+  - --jwt-issuer={{ .issuer }}
+{{- end }}
+{{- if .audience }}
+  - --jwt-audience={{ .audience }}
+{{- end }}
 {{- end }}
 {{- end }}
@@ -0,0 +1,28 @@
+package auth0
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/auth0/server"
+)
+
+func Command() *cobra.Command {
+	serverOpts := &server.Options{}
+
+	cmd := &cobra.Command{
+		Short: "Start the Auth0 IDP connector",
+		Use:   "auth0",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return serverOpts.ConfigureLogging()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.ListenAndServe(cmd.Context(), serverOpts)
+		},
+		// option to silence usage when an error occurs.
+		SilenceUsage: true,
+	}
+
+	serverOpts.AddToFlags(cmd.Flags())
+
+	return cmd
+}
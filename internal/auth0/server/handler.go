@@ -0,0 +1,688 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	resty "github.com/go-resty/resty/v2"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const (
+	// managementAPIPath is the path of the Management API on the tenant, which is also the audience of its tokens.
+	managementAPIPath = "/api/v2/"
+
+	// machineToMachineAppType is the Auth0 application type of the clients created by the connector.
+	machineToMachineAppType = "non_interactive"
+
+	// searchPageLimit is the number of applications per page when looking one up by name, which is the most the
+	// Management API returns.
+	searchPageLimit = 100
+
+	// clientFields are the fields of applications that are read from the Management API.
+	clientFields = "client_id,name,app_type,client_metadata"
+)
+
+type StrictServerHandler struct {
+	restClient    resty.Client
+	managementAPI string
+	mgmtClientId  string
+	audience      string
+	tokens        *connector.TokenSource
+	forceDelete   bool
+}
+
+type Auth0Client struct {
+	ClientId     string `json:"client_id"`
+	Name         string `json:"name"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	AppType      string `json:"app_type,omitempty"`
+
+	// ClientMetadata of the application, which includes the ownership metadata of applications that IdP Connect
+	// created.
+	ClientMetadata map[string]string `json:"client_metadata,omitempty"`
+}
+
+type Auth0ClientGrant struct {
+	Id       string   `json:"id,omitempty"`
+	ClientId string   `json:"client_id,omitempty"`
+	Audience string   `json:"audience,omitempty"`
+	Scope    []string `json:"scope"`
+}
+
+type Auth0ResourceServer struct {
+	Id         string       `json:"id,omitempty"`
+	Identifier string       `json:"identifier,omitempty"`
+	Scopes     []Auth0Scope `json:"scopes"`
+}
+
+type Auth0Scope struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+type Auth0Error struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+	ErrorCode  string `json:"errorCode,omitempty"`
+}
+
+func NewStrictServerHandler(opts *Options, restyClient *resty.Client) *StrictServerHandler {
+	tenantURL := opts.tenantURL()
+
+	// Tokens are requested with the client's transport, before requests are authenticated with them.
+	base := restyClient.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	management := &managementTokens{
+		client:       resty.NewWithClient(&http.Client{Transport: base}),
+		endpoint:     tenantURL + "/oauth/token",
+		audience:     tenantURL + managementAPIPath,
+		clientId:     opts.MgmtClientId,
+		clientSecret: opts.MgmtClientSecret,
+	}
+	tokens := connector.NewTokenSource(management.fetch)
+	restyClient.SetTransport(connector.NewTokenTransport(tokens, base))
+
+	restyClient.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
+		r.SetError(&Auth0Error{})
+		return nil
+	})
+
+	return &StrictServerHandler{
+		restClient:    *restyClient,
+		managementAPI: tenantURL + managementAPIPath,
+		mgmtClientId:  opts.MgmtClientId,
+		audience:      opts.Audience,
+		tokens:        tokens,
+		forceDelete:   opts.ForceDelete,
+	}
+}
+
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "auth0"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that the Management API can be used to read the API whose permissions represent API products.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	_, resp, err := s.resourceServer(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		portalErr := unwrapError(resp, nil)
+		return errors.New(portalErr.Message + ": " + portalErr.Reason)
+	}
+
+	return nil
+}
+
+// CreateOAuthApplication creates a machine-to-machine application in Auth0.
+func (s *StrictServerHandler) CreateOAuthApplication(
+	ctx context.Context,
+	request portalv1.CreateOAuthApplicationRequestObject,
+) (portalv1.CreateOAuthApplicationResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	existing, listClients, err := s.findClientByName(ctx, request.Body.Id)
+	if err != nil || listClients.IsError() {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapError(listClients, err)), nil
+	}
+	if existing != nil {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, existing.ClientId)), nil
+	}
+
+	var createdClient Auth0Client
+	resp, err := s.upstream(ctx, "CreateClient").
+		SetBody(map[string]interface{}{
+			"name":                       request.Body.Id,
+			"app_type":                   machineToMachineAppType,
+			"grant_types":                []string{"client_credentials"},
+			"token_endpoint_auth_method": "client_secret_basic",
+			"client_metadata":            connector.NewOwnership(request.Body.Id).Metadata(),
+		}).
+		SetResult(&createdClient).
+		Post(s.managementAPI + "clients")
+
+	if err != nil || resp.IsError() {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.CreateOAuthApplication201JSONResponse{
+		ClientId:     createdClient.ClientId,
+		ClientName:   &createdClient.Name,
+		ClientSecret: createdClient.ClientSecret,
+	}, nil
+}
+
+// DeleteOAuthApplication deletes an application in Auth0 by the ID Portal gave it, or by client ID. Its client grants
+// are deleted with it. Applications without the ownership client metadata are only deleted when deletes are forced.
+func (s *StrictServerHandler) DeleteOAuthApplication(
+	ctx context.Context,
+	request portalv1.DeleteOAuthApplicationRequestObject,
+) (portalv1.DeleteOAuthApplicationResponseObject, error) {
+	if len(request.Id) == 0 {
+		return portalv1.DeleteOAuthApplication404JSONResponse(connector.NewPortal404Error("client ID is required")), nil
+	}
+
+	// Look the application up first to resolve its client ID, and so that an unknown application is reported as not
+	// found.
+	client, getClient, err := s.findClient(ctx, request.Id)
+	if err != nil || getClient.IsError() {
+		switch portalErr := unwrapError(getClient, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !s.forceDelete && !connector.IsOwned(client.ClientMetadata) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
+	}
+
+	resp, err := s.upstream(ctx, "DeleteClient").
+		SetPathParam("id", client.ClientId).
+		Delete(s.managementAPI + "clients/{id}")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications lists the machine-to-machine applications in the tenant that the connector created, along with
+// the API products they can access. The cursor is the index of the next page of applications.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	page := 0
+	if request.Params.Cursor != nil {
+		var err error
+		if page, err = strconv.Atoi(*request.Params.Cursor); err != nil || page < 0 {
+			return portalv1.ListOAuthApplications400JSONResponse(connector.NewPortal400Error("invalid cursor [" + *request.Params.Cursor + "]")), nil
+		}
+	}
+	limit := connector.PageLimit(request.Params.Limit)
+
+	var clients []Auth0Client
+	resp, err := s.upstream(ctx, "ListClients").
+		SetQueryParams(map[string]string{
+			"app_type":       machineToMachineAppType,
+			"fields":         clientFields,
+			"include_fields": "true",
+			"page":           strconv.Itoa(page),
+			"per_page":       strconv.Itoa(limit),
+		}).
+		SetResult(&clients).
+		Get(s.managementAPI + "clients")
+
+	if err != nil || resp.IsError() {
+		return portalv1.ListOAuthApplications500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	result := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+	}
+	if len(clients) == limit {
+		next := strconv.Itoa(page + 1)
+		result.NextCursor = &next
+	}
+
+	for _, client := range clients {
+		// The management application is not owned by IdP Connect either.
+		if !connector.IsOwned(client.ClientMetadata) {
+			continue
+		}
+
+		details, getGrants, err := s.applicationDetails(ctx, client)
+		if err != nil || getGrants.IsError() {
+			return portalv1.ListOAuthApplications500JSONResponse(unwrapError(getGrants, err)), nil
+		}
+
+		result.Applications = append(result.Applications, details)
+	}
+
+	return result, nil
+}
+
+// GetOAuthApplication gets an application in Auth0 by the ID Portal gave it, or by client ID.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	client, getClient, err := s.findClient(ctx, request.Id)
+	if err != nil || getClient.IsError() {
+		switch portalErr := unwrapError(getClient, err); portalErr.Code {
+		case 404:
+			return portalv1.GetOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.GetOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	details, getGrants, err := s.applicationDetails(ctx, *client)
+	if err != nil || getGrants.IsError() {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapError(getGrants, err)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(details), nil
+}
+
+// RotateOAuthApplicationSecret generates a new secret for an application in Auth0. The previous secret stops working
+// immediately.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	found, getClient, err := s.findClient(ctx, request.Id)
+	if err != nil || getClient.IsError() {
+		switch portalErr := unwrapError(getClient, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !connector.IsOwned(found.ClientMetadata) {
+		return portalv1.RotateOAuthApplicationSecret403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	var client Auth0Client
+	resp, err := s.upstream(ctx, "RotateClientSecret").
+		SetPathParam("id", found.ClientId).
+		SetResult(&client).
+		Post(s.managementAPI + "clients/{id}/rotate-secret")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     client.ClientId,
+		ClientName:   &client.Name,
+		ClientSecret: client.ClientSecret,
+	}, nil
+}
+
+// GrantAPIProductAccess adds the API products to the scope of the application's client grant for the API, creating
+// the client grant if the application has none.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, getClient, err := s.findClient(ctx, request.Id)
+	if err != nil || getClient.IsError() {
+		switch portalErr := unwrapError(getClient, err); portalErr.Code {
+		case 404:
+			return portalv1.GrantAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.GrantAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !connector.IsOwned(client.ClientMetadata) {
+		return portalv1.GrantAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	resourceServer, getResourceServer, err := s.resourceServer(ctx)
+	if err != nil || getResourceServer.IsError() {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(getResourceServer, err)), nil
+	}
+
+	for _, apiProduct := range request.Body.ApiProducts {
+		if findScope(resourceServer.Scopes, apiProduct) < 0 {
+			return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error("no API product matches name [" + apiProduct + "]")), nil
+		}
+	}
+
+	grant, getGrants, err := s.findClientGrant(ctx, client.ClientId)
+	if err != nil || getGrants.IsError() {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(getGrants, err)), nil
+	}
+
+	if grant == nil {
+		resp, err := s.upstream(ctx, "CreateClientGrant").
+			SetBody(Auth0ClientGrant{
+				ClientId: client.ClientId,
+				Audience: s.audience,
+				Scope:    slices.Compact(slices.Sorted(slices.Values(request.Body.ApiProducts))),
+			}).
+			Post(s.managementAPI + "client-grants")
+
+		if err != nil || resp.IsError() {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(resp, err)), nil
+		}
+
+		return portalv1.GrantAPIProductAccess204Response{}, nil
+	}
+
+	scope := grant.Scope
+	for _, apiProduct := range request.Body.ApiProducts {
+		if !slices.Contains(scope, apiProduct) {
+			scope = append(scope, apiProduct)
+		}
+	}
+
+	if len(scope) != len(grant.Scope) {
+		if resp, err := s.updateClientGrant(ctx, grant.Id, scope); err != nil || resp.IsError() {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(resp, err)), nil
+		}
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the API products from the scope of the application's client grant for the API.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, getClient, err := s.findClient(ctx, request.Id)
+	if err != nil || getClient.IsError() {
+		switch portalErr := unwrapError(getClient, err); portalErr.Code {
+		case 404:
+			return portalv1.RevokeAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RevokeAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !connector.IsOwned(client.ClientMetadata) {
+		return portalv1.RevokeAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	grant, getGrants, err := s.findClientGrant(ctx, client.ClientId)
+	if err != nil || getGrants.IsError() {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapError(getGrants, err)), nil
+	}
+
+	// Without a client grant, the application cannot access any API product.
+	if grant == nil {
+		return portalv1.RevokeAPIProductAccess204Response{}, nil
+	}
+
+	scope := slices.DeleteFunc(slices.Clone(grant.Scope), func(s string) bool {
+		return slices.Contains(request.Params.ApiProducts, s)
+	})
+
+	if len(scope) != len(grant.Scope) {
+		if resp, err := s.updateClientGrant(ctx, grant.Id, scope); err != nil || resp.IsError() {
+			return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapError(resp, err)), nil
+		}
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct adds the API product as a permission of the API. The permissions of an API can only be replaced as
+// a whole, so concurrent changes to them can be lost.
+func (s *StrictServerHandler) CreateAPIProduct(
+	ctx context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product id is required")), nil
+	}
+
+	resourceServer, getResourceServer, err := s.resourceServer(ctx)
+	if err != nil || getResourceServer.IsError() {
+		return portalv1.CreateAPIProduct500JSONResponse(unwrapError(getResourceServer, err)), nil
+	}
+
+	if findScope(resourceServer.Scopes, request.Body.Id) >= 0 {
+		return portalv1.CreateAPIProduct409JSONResponse(connector.NewPortal409Error("API product [" + request.Body.Id + "] already exists")), nil
+	}
+
+	scope := Auth0Scope{Value: request.Body.Id}
+	if request.Body.Description != nil {
+		scope.Description = *request.Body.Description
+	}
+
+	resp, err := s.updateScopes(ctx, resourceServer.Id, append(resourceServer.Scopes, scope))
+	if err != nil || resp.IsError() {
+		return portalv1.CreateAPIProduct500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct removes the permission representing the API product from the API.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	ctx context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	resourceServer, getResourceServer, err := s.resourceServer(ctx)
+	if err != nil || getResourceServer.IsError() {
+		return portalv1.DeleteAPIProduct500JSONResponse(unwrapError(getResourceServer, err)), nil
+	}
+
+	i := findScope(resourceServer.Scopes, request.Id)
+	if i < 0 {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error("no API product matches name [" + request.Id + "]")), nil
+	}
+
+	resp, err := s.updateScopes(ctx, resourceServer.Id, slices.Delete(resourceServer.Scopes, i, i+1))
+	if err != nil || resp.IsError() {
+		return portalv1.DeleteAPIProduct500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// findClient finds an application by the ID Portal gave it, which is its name, or else by client ID. The application of
+// the connector itself is never found, so that its Management API credentials cannot be changed.
+func (s *StrictServerHandler) findClient(ctx context.Context, id string) (*Auth0Client, *resty.Response, error) {
+	client, resp, err := s.findClientByName(ctx, id)
+	if err != nil || resp.IsError() || client != nil {
+		return client, resp, err
+	}
+
+	if id == s.mgmtClientId {
+		return nil, resp, &clientNotFoundError{id: id}
+	}
+
+	return s.getClient(ctx, id)
+}
+
+// clientNotFoundError is returned for an application that is not found without asking Auth0.
+type clientNotFoundError struct {
+	id string
+}
+
+func (e *clientNotFoundError) Error() string {
+	return "no application matches id [" + e.id + "]"
+}
+
+// findClientByName returns the machine-to-machine application with the name, or nil if there is none. The Management
+// API cannot filter applications by name, so they are paged through to find it.
+func (s *StrictServerHandler) findClientByName(ctx context.Context, name string) (*Auth0Client, *resty.Response, error) {
+	for page := 0; ; page++ {
+		var clients []Auth0Client
+		resp, err := s.upstream(ctx, "ListClients").
+			SetQueryParams(map[string]string{
+				"app_type":       machineToMachineAppType,
+				"fields":         clientFields,
+				"include_fields": "true",
+				"page":           strconv.Itoa(page),
+				"per_page":       strconv.Itoa(searchPageLimit),
+			}).
+			SetResult(&clients).
+			Get(s.managementAPI + "clients")
+
+		if err != nil || resp.IsError() {
+			return nil, resp, err
+		}
+
+		for i := range clients {
+			if clients[i].Name == name && clients[i].ClientId != s.mgmtClientId {
+				return &clients[i], resp, nil
+			}
+		}
+
+		if len(clients) < searchPageLimit {
+			return nil, resp, nil
+		}
+	}
+}
+
+// getClient gets an application by client ID.
+func (s *StrictServerHandler) getClient(ctx context.Context, clientId string) (*Auth0Client, *resty.Response, error) {
+	var client Auth0Client
+	resp, err := s.upstream(ctx, "GetClient").
+		SetPathParam("id", clientId).
+		SetQueryParams(map[string]string{
+			"fields":         clientFields,
+			"include_fields": "true",
+		}).
+		SetResult(&client).
+		Get(s.managementAPI + "clients/{id}")
+
+	return &client, resp, err
+}
+
+// findClientGrant returns the client grant of the application for the API, or nil if it has none.
+func (s *StrictServerHandler) findClientGrant(ctx context.Context, clientId string) (*Auth0ClientGrant, *resty.Response, error) {
+	var grants []Auth0ClientGrant
+	resp, err := s.upstream(ctx, "ListClientGrants").
+		SetQueryParams(map[string]string{
+			"client_id": clientId,
+			"audience":  s.audience,
+		}).
+		SetResult(&grants).
+		Get(s.managementAPI + "client-grants")
+
+	if err != nil || resp.IsError() || len(grants) == 0 {
+		return nil, resp, err
+	}
+
+	return &grants[0], resp, nil
+}
+
+func (s *StrictServerHandler) updateClientGrant(ctx context.Context, grantId string, scope []string) (*resty.Response, error) {
+	return s.upstream(ctx, "UpdateClientGrant").
+		SetPathParam("id", grantId).
+		SetBody(Auth0ClientGrant{Scope: scope}).
+		Patch(s.managementAPI + "client-grants/{id}")
+}
+
+// resourceServer gets the API whose permissions represent API products. The Management API accepts its identifier in
+// place of its ID.
+func (s *StrictServerHandler) resourceServer(ctx context.Context) (*Auth0ResourceServer, *resty.Response, error) {
+	var resourceServer Auth0ResourceServer
+	resp, err := s.upstream(ctx, "GetResourceServer").
+		SetPathParam("id", s.audience).
+		SetResult(&resourceServer).
+		Get(s.managementAPI + "resource-servers/{id}")
+
+	return &resourceServer, resp, err
+}
+
+func (s *StrictServerHandler) updateScopes(ctx context.Context, resourceServerId string, scopes []Auth0Scope) (*resty.Response, error) {
+	return s.upstream(ctx, "UpdateResourceServer").
+		SetPathParam("id", resourceServerId).
+		SetBody(Auth0ResourceServer{Scopes: scopes}).
+		Patch(s.managementAPI + "resource-servers/{id}")
+}
+
+// applicationDetails returns the application without its secret, along with the API products that it has been
+// granted access to.
+func (s *StrictServerHandler) applicationDetails(ctx context.Context, client Auth0Client) (portalv1.OAuthApplicationDetails, *resty.Response, error) {
+	details := portalv1.OAuthApplicationDetails{
+		ClientId:   client.ClientId,
+		ClientName: &client.Name,
+		Scopes:     []string{},
+	}
+
+	grant, resp, err := s.findClientGrant(ctx, client.ClientId)
+	if err != nil || resp.IsError() {
+		return details, resp, err
+	}
+
+	if grant != nil {
+		details.Scopes = append(details.Scopes, grant.Scope...)
+	}
+
+	return details, resp, nil
+}
+
+// upstream returns a request to the Management API that is labelled with the given operation in the upstream metrics.
+func (s *StrictServerHandler) upstream(ctx context.Context, operation string) *resty.Request {
+	return s.restClient.R().SetContext(connector.WithUpstreamOperation(ctx, operation))
+}
+
+func findScope(scopes []Auth0Scope, value string) int {
+	return slices.IndexFunc(scopes, func(scope Auth0Scope) bool {
+		return scope.Value == value
+	})
+}
+
+func unwrapError(resp *resty.Response, err error) portalv1.Error {
+	if err == nil {
+		error, _ := resp.Error().(*Auth0Error)
+		if error == nil || error.Error == "" {
+			return connector.NewPortalError(resp.StatusCode(), resp.Status(), string(resp.Body()))
+		}
+		return portalv1.Error{
+			Code:    resp.StatusCode(),
+			Message: error.Error,
+			Reason:  error.Message,
+		}
+	}
+
+	var notFound *clientNotFoundError
+	if errors.As(err, &notFound) {
+		return connector.NewPortal404Error(notFound.Error())
+	}
+
+	var respErr *resty.ResponseError
+	if ok := errors.As(err, &respErr); ok {
+		return portalv1.Error{
+			Code:    respErr.Response.StatusCode(),
+			Message: respErr.Response.Status(),
+			Reason:  respErr.Error(),
+		}
+	}
+
+	return connector.NewPortal500Error(err.Error())
+}
@@ -0,0 +1,635 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/auth0/server"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector/connectortest"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const (
+	domain           = "my-tenant.us.auth0.com"
+	managementAPI    = "/api/v2/"
+	mgmtClientId     = "mgmt-client-id"
+	mgmtClientSecret = "mgmt-client-secret"
+	audience         = "https://api.example.com"
+	resourceServerId = "resource-server-id"
+)
+
+// fakeTenant implements the parts of the Auth0 Management API used by the connector.
+type fakeTenant struct {
+	*connectortest.FakeIdP[server.Auth0Client]
+
+	tokens []string
+	valid  map[string]bool
+	grants map[string]server.Auth0ClientGrant
+	scopes []server.Auth0Scope
+}
+
+func newFakeTenant() *fakeTenant {
+	f := &fakeTenant{
+		FakeIdP: connectortest.NewFakeIdP[server.Auth0Client](),
+		valid:   map[string]bool{},
+		grants:  map[string]server.Auth0ClientGrant{},
+		scopes:  []server.Auth0Scope{},
+	}
+	f.Clients[mgmtClientId] = server.Auth0Client{ClientId: mgmtClientId, Name: "IDP Connect", AppType: "non_interactive"}
+
+	return f
+}
+
+// revokeTokens makes the tenant reject the tokens issued so far, before they expire.
+func (f *fakeTenant) revokeTokens() {
+	f.Lock()
+	defer f.Unlock()
+
+	f.valid = map[string]bool{}
+}
+
+func (f *fakeTenant) serve(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/oauth/token" {
+		return f.token(req)
+	}
+
+	if !f.valid[strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")] {
+		return auth0Error(401, "Unauthorized", "Invalid token")
+	}
+
+	path, ok := strings.CutPrefix(req.URL.EscapedPath(), managementAPI)
+	Expect(ok).To(BeTrue(), req.URL.String())
+	segments := strings.Split(path, "/")
+
+	switch {
+	case segments[0] == "clients" && len(segments) == 1:
+		return f.serveClients(req)
+	case segments[0] == "clients":
+		return f.serveClient(req, segments[1:])
+	case segments[0] == "client-grants":
+		return f.serveClientGrants(req, segments[1:])
+	case segments[0] == "resource-servers" && len(segments) == 2:
+		id, err := url.PathUnescape(segments[1])
+		Expect(err).NotTo(HaveOccurred())
+		return f.serveResourceServer(req, id)
+	}
+
+	return auth0Error(404, "Not Found", "Not found")
+}
+
+func (f *fakeTenant) token(req *http.Request) (*http.Response, error) {
+	Expect(req.ParseForm()).To(Succeed())
+	Expect(req.PostForm.Get("grant_type")).To(Equal("client_credentials"))
+	Expect(req.PostForm.Get("audience")).To(Equal("https://" + domain + managementAPI))
+
+	if req.PostForm.Get("client_id") != mgmtClientId || req.PostForm.Get("client_secret") != mgmtClientSecret {
+		return httpmock.NewJsonResponse(401, server.Auth0TokenError{Error: "access_denied", Description: "Unauthorized"})
+	}
+
+	token := f.Next("token")
+	f.tokens = append(f.tokens, token)
+	f.valid[token] = true
+
+	return httpmock.NewJsonResponse(200, server.Auth0Token{AccessToken: token, ExpiresIn: 86400})
+}
+
+func (f *fakeTenant) serveClients(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost:
+		var client server.Auth0Client
+		Expect(json.NewDecoder(req.Body).Decode(&client)).To(Succeed())
+		Expect(client.AppType).To(Equal("non_interactive"))
+
+		client.ClientId = f.Next("client")
+		client.ClientSecret = f.Next("secret")
+		f.Clients[client.ClientId] = client
+
+		return httpmock.NewJsonResponse(201, client)
+	case http.MethodGet:
+		Expect(req.URL.Query().Get("app_type")).To(Equal("non_interactive"))
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(req.URL.Query().Get("per_page"))
+
+		ids := slices.Sorted(maps.Keys(f.Clients))
+		clients := []server.Auth0Client{}
+		for _, id := range ids[min(page*perPage, len(ids)):min((page+1)*perPage, len(ids))] {
+			client := f.Clients[id]
+			client.ClientSecret = ""
+			clients = append(clients, client)
+		}
+
+		return httpmock.NewJsonResponse(200, clients)
+	}
+
+	return auth0Error(405, "Method Not Allowed", req.Method)
+}
+
+func (f *fakeTenant) serveClient(req *http.Request, segments []string) (*http.Response, error) {
+	client, ok := f.Clients[segments[0]]
+	if !ok {
+		return auth0Error(404, "Not Found", "The client does not exist")
+	}
+
+	switch {
+	case len(segments) == 2 && segments[1] == "rotate-secret" && req.Method == http.MethodPost:
+		client.ClientSecret = f.Next("secret")
+		f.Clients[client.ClientId] = client
+		return httpmock.NewJsonResponse(200, client)
+	case len(segments) == 1 && req.Method == http.MethodGet:
+		client.ClientSecret = ""
+		return httpmock.NewJsonResponse(200, client)
+	case len(segments) == 1 && req.Method == http.MethodDelete:
+		delete(f.Clients, client.ClientId)
+		for id, grant := range f.grants {
+			if grant.ClientId == client.ClientId {
+				delete(f.grants, id)
+			}
+		}
+		return httpmock.NewStringResponse(204, ""), nil
+	}
+
+	return auth0Error(405, "Method Not Allowed", req.Method)
+}
+
+func (f *fakeTenant) serveClientGrants(req *http.Request, segments []string) (*http.Response, error) {
+	switch {
+	case len(segments) == 0 && req.Method == http.MethodGet:
+		grants := []server.Auth0ClientGrant{}
+		for _, grant := range f.grants {
+			if grant.ClientId == req.URL.Query().Get("client_id") && grant.Audience == req.URL.Query().Get("audience") {
+				grants = append(grants, grant)
+			}
+		}
+		return httpmock.NewJsonResponse(200, grants)
+	case len(segments) == 0 && req.Method == http.MethodPost:
+		var grant server.Auth0ClientGrant
+		Expect(json.NewDecoder(req.Body).Decode(&grant)).To(Succeed())
+		if _, ok := f.Clients[grant.ClientId]; !ok {
+			return auth0Error(404, "Not Found", "The client does not exist")
+		}
+		for _, existing := range f.grants {
+			if existing.ClientId == grant.ClientId && existing.Audience == grant.Audience {
+				return auth0Error(409, "Conflict", "A client grant for this client and audience already exists")
+			}
+		}
+		grant.Id = f.Next("grant")
+		f.grants[grant.Id] = grant
+		return httpmock.NewJsonResponse(201, grant)
+	case len(segments) == 1 && req.Method == http.MethodPatch:
+		grant, ok := f.grants[segments[0]]
+		if !ok {
+			return auth0Error(404, "Not Found", "The client grant does not exist")
+		}
+		var update server.Auth0ClientGrant
+		Expect(json.NewDecoder(req.Body).Decode(&update)).To(Succeed())
+		grant.Scope = update.Scope
+		f.grants[grant.Id] = grant
+		return httpmock.NewJsonResponse(200, grant)
+	}
+
+	return auth0Error(405, "Method Not Allowed", req.Method)
+}
+
+func (f *fakeTenant) serveResourceServer(req *http.Request, id string) (*http.Response, error) {
+	if id != resourceServerId && id != audience {
+		return auth0Error(404, "Not Found", "The resource server does not exist")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPatch:
+		var update server.Auth0ResourceServer
+		Expect(json.NewDecoder(req.Body).Decode(&update)).To(Succeed())
+		f.scopes = update.Scopes
+	default:
+		return auth0Error(405, "Method Not Allowed", req.Method)
+	}
+
+	return httpmock.NewJsonResponse(200, server.Auth0ResourceServer{
+		Id:         resourceServerId,
+		Identifier: audience,
+		Scopes:     f.scopes,
+	})
+}
+
+func auth0Error(code int, error, message string) (*http.Response, error) {
+	return httpmock.NewJsonResponse(code, server.Auth0Error{StatusCode: code, Error: error, Message: message})
+}
+
+var _ = Describe("Server", func() {
+	var (
+		s      *server.StrictServerHandler
+		tenant *fakeTenant
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tenant = newFakeTenant()
+
+		restyClient := resty.New()
+		httpmock.ActivateNonDefault(restyClient.GetClient())
+		httpmock.RegisterNoResponder(tenant.Responder(tenant.serve))
+
+		s = server.NewStrictServerHandler(&server.Options{
+			Domain:           domain,
+			MgmtClientId:     mgmtClientId,
+			MgmtClientSecret: mgmtClientSecret,
+			Audience:         audience,
+		}, restyClient)
+	})
+
+	create := func(id string) portalv1.CreateOAuthApplication201JSONResponse {
+		resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+			Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+
+		return resp.(portalv1.CreateOAuthApplication201JSONResponse)
+	}
+
+	createAPIProduct := func(id string) {
+		resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+			Body: &portalv1.CreateAPIProductJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+	}
+
+	scopes := func(clientId string) []string {
+		resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: clientId})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+
+		return resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes
+	}
+
+	When("creating an application", func() {
+		It("returns the client ID and secret of a new machine-to-machine application", func() {
+			app := create("my-app")
+
+			Expect(app.ClientId).To(Equal("client-2"))
+			Expect(*app.ClientName).To(Equal("my-app"))
+			Expect(app.ClientSecret).To(Equal("secret-3"))
+			Expect(tenant.Clients).To(HaveKey("client-2"))
+		})
+
+		It("marks the application as created by IdP Connect", func() {
+			app := create("my-app")
+
+			metadata := tenant.Clients[app.ClientId].ClientMetadata
+			Expect(metadata).To(HaveKeyWithValue("managed_by", "gloo-portal-idp-connect"))
+			Expect(metadata).To(HaveKeyWithValue("portal_id", "my-app"))
+			Expect(metadata).To(HaveKey("created_at"))
+		})
+
+		It("returns 409 for an id that an application already exists for", func() {
+			app := create("my-app")
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "my-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(app.ClientId))
+			Expect(tenant.Clients).To(HaveLen(2))
+		})
+
+		It("returns 400 without an id", func() {
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication400JSONResponse{}))
+		})
+
+		It("returns 500 if the management token cannot be obtained", func() {
+			s = server.NewStrictServerHandler(&server.Options{
+				Domain:           domain,
+				MgmtClientId:     mgmtClientId,
+				MgmtClientSecret: "wrong",
+				Audience:         audience,
+			}, resty.NewWithClient(&http.Client{Transport: httpmock.DefaultTransport}))
+			httpmock.RegisterNoResponder(tenant.serve)
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "my-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication500JSONResponse).Reason).To(ContainSubstring("access_denied"))
+		})
+	})
+
+	When("using the management token", func() {
+		It("reuses the token until it is rejected", func() {
+			create("first")
+			create("second")
+			Expect(tenant.tokens).To(HaveLen(1))
+
+			tenant.revokeTokens()
+			create("third")
+			Expect(tenant.tokens).To(HaveLen(2))
+		})
+	})
+
+	When("deleting an application", func() {
+		It("deletes the application by the id it was created with", func() {
+			app := create("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(tenant.Clients).NotTo(HaveKey(app.ClientId))
+		})
+
+		It("deletes the application by client ID", func() {
+			app := create("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(tenant.Clients).NotTo(HaveKey(app.ClientId))
+		})
+
+		It("finds the application on a later page of the tenant", func() {
+			for i := range 150 {
+				id := fmt.Sprintf("other-%03d", i)
+				tenant.Clients[id] = server.Auth0Client{ClientId: id, Name: id, AppType: "non_interactive"}
+			}
+			app := create("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(tenant.Clients).NotTo(HaveKey(app.ClientId))
+		})
+
+		It("returns 403 for an application that IdP Connect did not create", func() {
+			tenant.Clients["other"] = server.Auth0Client{ClientId: "other", Name: "my-app", AppType: "non_interactive"}
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+			Expect(tenant.Clients).To(HaveKey("other"))
+		})
+
+		It("deletes an application that IdP Connect did not create when forced to", func() {
+			opts := &server.Options{
+				Domain:           domain,
+				MgmtClientId:     mgmtClientId,
+				MgmtClientSecret: mgmtClientSecret,
+				Audience:         audience,
+			}
+			opts.ForceDelete = true
+			s = server.NewStrictServerHandler(opts, resty.NewWithClient(&http.Client{Transport: httpmock.DefaultTransport}))
+			tenant.Clients["other"] = server.Auth0Client{ClientId: "other", Name: "my-app", AppType: "non_interactive"}
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(tenant.Clients).NotTo(HaveKey("other"))
+		})
+
+		It("returns 404 for an unknown application", func() {
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
+		})
+	})
+
+	When("listing applications", func() {
+		It("pages through the applications that it created", func() {
+			for _, id := range []string{"a", "b", "c"} {
+				create(id)
+			}
+			tenant.Clients["other"] = server.Auth0Client{ClientId: "other", Name: "other", AppType: "non_interactive"}
+
+			// The management application and the other application take up places in the pages of the tenant, but are
+			// not listed.
+			limit := 2
+			var names []string
+			var cursor *string
+			for pages := 0; ; pages++ {
+				Expect(pages).To(BeNumerically("<", 3))
+
+				resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+					Params: portalv1.ListOAuthApplicationsParams{Limit: &limit, Cursor: cursor},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+				Expect(len(page.Applications)).To(BeNumerically("<=", limit))
+
+				for _, app := range page.Applications {
+					Expect(app.ClientId).NotTo(Equal(mgmtClientId))
+					names = append(names, *app.ClientName)
+				}
+				if cursor = page.NextCursor; cursor == nil {
+					break
+				}
+			}
+			Expect(names).To(ConsistOf("a", "b", "c"))
+		})
+
+		It("returns 400 for an invalid cursor", func() {
+			cursor := "next"
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{Cursor: &cursor},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications400JSONResponse{}))
+		})
+	})
+
+	When("getting an application", func() {
+		It("returns the application by the id it was created with", func() {
+			app := create("my-app")
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			Expect(resp.(portalv1.GetOAuthApplication200JSONResponse).ClientId).To(Equal(app.ClientId))
+		})
+
+		It("returns 404 for an unknown application", func() {
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+	})
+
+	When("rotating the secret of an application", func() {
+		It("returns the new secret", func() {
+			app := create("my-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(app.ClientId))
+			Expect(rotated.ClientSecret).NotTo(Equal(app.ClientSecret))
+			Expect(rotated.ClientSecret).To(Equal(tenant.Clients[app.ClientId].ClientSecret))
+		})
+
+		It("returns 404 for an unknown application", func() {
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret404JSONResponse{}))
+		})
+
+		It("returns 404 for the management application, without rotating its secret", func() {
+			secret := tenant.Clients[mgmtClientId].ClientSecret
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: mgmtClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret404JSONResponse{}))
+			Expect(tenant.Clients[mgmtClientId].ClientSecret).To(Equal(secret))
+
+			get, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: mgmtClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+
+		It("returns 403 for an application that IdP Connect did not create", func() {
+			tenant.Clients["other"] = server.Auth0Client{ClientId: "other", Name: "other-app", AppType: "non_interactive", ClientSecret: "s3cr3t"}
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "other-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret403JSONResponse{}))
+			Expect(tenant.Clients["other"].ClientSecret).To(Equal("s3cr3t"))
+		})
+	})
+
+	When("granting and revoking access to API products", func() {
+		var app portalv1.CreateOAuthApplication201JSONResponse
+
+		BeforeEach(func() {
+			app = create("my-app")
+			createAPIProduct("petstore")
+			createAPIProduct("tracks")
+		})
+
+		It("creates and updates the client grant for the API", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   "my-app",
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(scopes(app.ClientId)).To(ConsistOf("petstore"))
+
+			resp, err = s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   app.ClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"petstore", "tracks"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(scopes(app.ClientId)).To(ConsistOf("petstore", "tracks"))
+			Expect(tenant.grants).To(HaveLen(1))
+
+			revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     "my-app",
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+			Expect(scopes(app.ClientId)).To(ConsistOf("tracks"))
+		})
+
+		It("returns 404 for an unknown API product", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   app.ClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"unknown"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+			Expect(tenant.grants).To(BeEmpty())
+		})
+
+		It("returns 404 for an unknown application", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   "unknown",
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns 403 for an application that IdP Connect did not create", func() {
+			tenant.Clients["other"] = server.Auth0Client{ClientId: "other", Name: "other-app", AppType: "non_interactive"}
+
+			grant, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   "other-app",
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grant).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess403JSONResponse{}))
+			Expect(tenant.grants).To(BeEmpty())
+
+			revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     "other-app",
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess403JSONResponse{}))
+		})
+
+		It("revokes nothing from an application without a client grant", func() {
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     app.ClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+		})
+	})
+
+	When("managing API products", func() {
+		It("adds and removes permissions of the API", func() {
+			description := "Pet store"
+			resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+				Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "petstore", Description: &description},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+			Expect(tenant.scopes).To(ConsistOf(server.Auth0Scope{Value: "petstore", Description: description}))
+
+			resp, err = s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+				Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "petstore"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+
+			deleteResp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "petstore"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteResp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			Expect(tenant.scopes).To(BeEmpty())
+		})
+
+		It("returns 404 when deleting an unknown API product", func() {
+			resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct404JSONResponse{}))
+		})
+	})
+
+	When("checking readiness", func() {
+		It("is ready when the API can be read", func() {
+			Expect(s.Ready(ctx)).To(Succeed())
+		})
+	})
+})
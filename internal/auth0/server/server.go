@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/rotisserie/eris"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+type Options struct {
+	connector.Options
+	Domain           string
+	MgmtClientId     string
+	MgmtClientSecret string
+	Audience         string
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	o.Options.AddToFlags(flag)
+	flag.StringVar(&o.Domain, "domain", "", "Auth0 tenant domain (e.g. my-tenant.us.auth0.com)")
+	flag.StringVar(&o.MgmtClientId, "client-id", "", "ID of the Auth0 application that is authorised to use the Management API")
+	flag.StringVar(&o.MgmtClientSecret, "client-secret", "", "Secret of the Auth0 application that is authorised to use the Management API")
+	flag.StringVar(&o.Audience, "audience", "", "Identifier of the Auth0 API whose permissions represent API products")
+}
+
+func (o *Options) Validate() error {
+	if o.Domain == "" {
+		return eris.New("Auth0 domain is required")
+	}
+	if o.MgmtClientId == "" {
+		return eris.New("Client ID is required")
+	}
+	if o.Audience == "" {
+		return eris.New("Audience is required")
+	}
+
+	// Try to get the client secret from the environment if not provided via flag
+	if o.MgmtClientSecret == "" {
+		if envSecret := os.Getenv("AUTH0_CLIENT_SECRET"); envSecret != "" {
+			o.MgmtClientSecret = envSecret
+		} else {
+			return eris.New("Client secret is required (via --client-secret flag or AUTH0_CLIENT_SECRET environment variable)")
+		}
+	}
+
+	return nil
+}
+
+// tenantURL returns the base URL of the Auth0 tenant, which may be given with or without a scheme.
+func (o *Options) tenantURL() string {
+	domain := strings.TrimSuffix(o.Domain, "/")
+	if strings.HasPrefix(domain, "http://") || strings.HasPrefix(domain, "https://") {
+		return domain
+	}
+
+	return "https://" + domain
+}
+
+func ListenAndServe(ctx context.Context, opts *Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	client := resty.New().SetTransport(opts.UpstreamTransport("auth0", nil))
+
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, client))
+}
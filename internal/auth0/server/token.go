@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	resty "github.com/go-resty/resty/v2"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+type Auth0Token struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in,omitempty"`
+}
+
+type Auth0TokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// managementTokens requests Management API tokens with the client credentials grant.
+type managementTokens struct {
+	client       *resty.Client
+	endpoint     string
+	audience     string
+	clientId     string
+	clientSecret string
+}
+
+// fetch requests a new Management API token.
+func (m *managementTokens) fetch(ctx context.Context) (*connector.Token, error) {
+	var token *Auth0Token
+	tokenResponse, err := m.client.R().
+		SetContext(connector.WithUpstreamOperation(ctx, "GetToken")).
+		SetFormData(map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     m.clientId,
+			"client_secret": m.clientSecret,
+			"audience":      m.audience,
+		}).
+		SetResult(&token).
+		SetError(&Auth0TokenError{}).
+		Post(m.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenResponse.IsError() {
+		error := tokenResponse.Error().(*Auth0TokenError)
+		return nil, fmt.Errorf("could not obtain token for client %s: [%s] %s", m.clientId, error.Error, error.Description)
+	}
+
+	return &connector.Token{AccessToken: token.AccessToken, ExpiresIn: token.ExpiresIn}, nil
+}
@@ -3,15 +3,31 @@ package server
 import (
 	"context"
 	"errors"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 	"github.com/aws/smithy-go/transport/http"
 
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
 )
 
+const (
+	// maxListResults is the most clients Cognito lists per page.
+	maxListResults = 60
+
+	// clientNamePrefix is prepended to the Portal id in the name of the clients created by the connector, as Cognito
+	// clients have no other metadata to mark them as owned by IdP Connect. Cognito records their creation time itself.
+	clientNamePrefix = connector.ManagedBy + "."
+)
+
+// clientIdPattern matches the IDs that Cognito accepts as client IDs.
+var clientIdPattern = regexp.MustCompile(`^[\w+]+$`)
+
 //go:generate mockgen -destination=mock/cognito_client.go . CognitoClient
 
 type CognitoClient interface {
@@ -27,6 +43,18 @@ type CognitoClient interface {
 		optFns ...func(*cognito.Options),
 	) (*cognito.CreateUserPoolClientOutput, error)
 
+	ListUserPoolClients(
+		ctx context.Context,
+		params *cognito.ListUserPoolClientsInput,
+		optFns ...func(*cognito.Options),
+	) (*cognito.ListUserPoolClientsOutput, error)
+
+	DescribeUserPoolClient(
+		ctx context.Context,
+		params *cognito.DescribeUserPoolClientInput,
+		optFns ...func(*cognito.Options),
+	) (*cognito.DescribeUserPoolClientOutput, error)
+
 	UpdateUserPoolClient(
 		ctx context.Context,
 		params *cognito.UpdateUserPoolClientInput,
@@ -50,6 +78,12 @@ type CognitoClient interface {
 		params *cognito.UpdateResourceServerInput,
 		optFns ...func(*cognito.Options),
 	) (*cognito.UpdateResourceServerOutput, error)
+
+	DescribeUserPool(
+		ctx context.Context,
+		params *cognito.DescribeUserPoolInput,
+		optFns ...func(*cognito.Options),
+	) (*cognito.DescribeUserPoolOutput, error)
 }
 
 type StrictServerHandler struct {
@@ -57,6 +91,7 @@ type StrictServerHandler struct {
 
 	cognitoClient  CognitoClient
 	resourceServer string
+	forceDelete    bool
 }
 
 func NewStrictServerHandler(opts *Options, cognitoClient CognitoClient) *StrictServerHandler {
@@ -64,18 +99,47 @@ func NewStrictServerHandler(opts *Options, cognitoClient CognitoClient) *StrictS
 		userPool:       opts.CognitoUserPool,
 		cognitoClient:  cognitoClient,
 		resourceServer: opts.ResourceServer,
+		forceDelete:    opts.ForceDelete,
 	}
 }
 
-// DeleteOAuthApplication deletes an application by ID.
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "cognito"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that the user pool can be described with the configured AWS credentials.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	_, err := s.cognitoClient.DescribeUserPool(ctx, &cognito.DescribeUserPoolInput{
+		UserPoolId: &s.userPool,
+	})
+	return err
+}
+
+// DeleteOAuthApplication deletes the client of an application.
 func (s *StrictServerHandler) DeleteOAuthApplication(
 	ctx context.Context,
 	request portalv1.DeleteOAuthApplicationRequestObject,
 ) (portalv1.DeleteOAuthApplicationResponseObject, error) {
-	_, err := s.cognitoClient.DeleteUserPoolClient(ctx, &cognito.DeleteUserPoolClientInput{
-		UserPoolId: &s.userPool,
-		ClientId:   aws.String(request.Id),
-	})
+	client, err := s.findClient(ctx, request.Id)
+	if err == nil && !s.forceDelete && !isOwned(client.ClientName) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
+	}
+	if err == nil {
+		_, err = s.cognitoClient.DeleteUserPoolClient(ctx, &cognito.DeleteUserPoolClientInput{
+			UserPoolId: &s.userPool,
+			ClientId:   client.ClientId,
+		})
+	}
 
 	if err != nil {
 		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
@@ -95,12 +159,20 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 	request portalv1.CreateOAuthApplicationRequestObject,
 ) (portalv1.CreateOAuthApplicationResponseObject, error) {
 	if request.Body == nil || len(request.Body.Id) == 0 {
-		return portalv1.CreateOAuthApplication400JSONResponse(newPortal400Error("unique id is required")), nil
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	existing, err := s.findClientByName(ctx, request.Body.Id)
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapCognitoError(err)), nil
+	}
+	if existing != nil {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, aws.ToString(existing.ClientId))), nil
 	}
 
 	out, err := s.cognitoClient.CreateUserPoolClient(ctx, &cognito.CreateUserPoolClientInput{
 		UserPoolId:     &s.userPool,
-		ClientName:     aws.String(request.Body.Id),
+		ClientName:     aws.String(clientNamePrefix + request.Body.Id),
 		GenerateSecret: true,
 	})
 
@@ -115,6 +187,451 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 	}, nil
 }
 
+// ListOAuthApplications lists the clients in the Cognito user pool that the connector created, along with their allowed
+// OAuth scopes.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	out, err := s.cognitoClient.ListUserPoolClients(ctx, &cognito.ListUserPoolClientsInput{
+		UserPoolId: &s.userPool,
+		MaxResults: aws.Int32(int32(connector.PageLimit(request.Params.Limit))),
+		NextToken:  request.Params.Cursor,
+	})
+
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 400:
+			return portalv1.ListOAuthApplications400JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.ListOAuthApplications500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	// The client descriptions do not include the scopes, so each client has to be described separately.
+	page := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+		NextCursor:   out.NextToken,
+	}
+	for _, description := range out.UserPoolClients {
+		if !isOwned(description.ClientName) {
+			continue
+		}
+
+		client, err := s.describeClient(ctx, aws.ToString(description.ClientId))
+		if err != nil {
+			return portalv1.ListOAuthApplications500JSONResponse(unwrapCognitoError(err)), nil
+		}
+
+		page.Applications = append(page.Applications, applicationDetails(client))
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication gets the client of an application in the Cognito user pool.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	client, err := s.findClient(ctx, request.Id)
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.GetOAuthApplication404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.GetOAuthApplication500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(applicationDetails(client)), nil
+}
+
+// RotateOAuthApplicationSecret replaces the client with a new one that has the same settings and a new secret. Cognito
+// cannot regenerate the secret of an existing client, so the client ID changes as well.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	client, err := s.findClient(ctx, request.Id)
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	if !isOwned(client.ClientName) {
+		return portalv1.RotateOAuthApplicationSecret403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	out, err := s.cognitoClient.CreateUserPoolClient(ctx, &cognito.CreateUserPoolClientInput{
+		UserPoolId:                               &s.userPool,
+		ClientName:                               client.ClientName,
+		GenerateSecret:                           true,
+		AccessTokenValidity:                      client.AccessTokenValidity,
+		AllowedOAuthFlows:                        client.AllowedOAuthFlows,
+		AllowedOAuthFlowsUserPoolClient:          aws.ToBool(client.AllowedOAuthFlowsUserPoolClient),
+		AllowedOAuthScopes:                       client.AllowedOAuthScopes,
+		AnalyticsConfiguration:                   client.AnalyticsConfiguration,
+		AuthSessionValidity:                      client.AuthSessionValidity,
+		CallbackURLs:                             client.CallbackURLs,
+		DefaultRedirectURI:                       client.DefaultRedirectURI,
+		EnablePropagateAdditionalUserContextData: client.EnablePropagateAdditionalUserContextData,
+		EnableTokenRevocation:                    client.EnableTokenRevocation,
+		ExplicitAuthFlows:                        client.ExplicitAuthFlows,
+		IdTokenValidity:                          client.IdTokenValidity,
+		LogoutURLs:                               client.LogoutURLs,
+		PreventUserExistenceErrors:               client.PreventUserExistenceErrors,
+		ReadAttributes:                           client.ReadAttributes,
+		RefreshTokenValidity:                     client.RefreshTokenValidity,
+		SupportedIdentityProviders:               client.SupportedIdentityProviders,
+		TokenValidityUnits:                       client.TokenValidityUnits,
+		WriteAttributes:                          client.WriteAttributes,
+	})
+
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapCognitoError(err)), nil
+	}
+
+	_, err = s.cognitoClient.DeleteUserPoolClient(ctx, &cognito.DeleteUserPoolClientInput{
+		UserPoolId: &s.userPool,
+		ClientId:   client.ClientId,
+	})
+
+	if err != nil {
+		// Remove the new client, so that the old secret remains the only valid one.
+		_, _ = s.cognitoClient.DeleteUserPoolClient(ctx, &cognito.DeleteUserPoolClientInput{
+			UserPoolId: &s.userPool,
+			ClientId:   out.UserPoolClient.ClientId,
+		})
+
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapCognitoError(err)), nil
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     aws.ToString(out.UserPoolClient.ClientId),
+		ClientSecret: aws.ToString(out.UserPoolClient.ClientSecret),
+		ClientName:   portalId(out.UserPoolClient.ClientName),
+	}, nil
+}
+
+// GrantAPIProductAccess adds the scopes of the given API products to the client's allowed OAuth scopes.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, err := s.findClient(ctx, request.Id)
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.GrantAPIProductAccess404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.GrantAPIProductAccess500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	if !isOwned(client.ClientName) {
+		return portalv1.GrantAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	scopes := client.AllowedOAuthScopes
+	for _, apiProduct := range request.Body.ApiProducts {
+		if scope := s.apiProductScope(apiProduct); !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if err := s.updateClientScopes(ctx, client, scopes); err != nil {
+		// Cognito rejects scopes that are not defined on the resource server.
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.GrantAPIProductAccess404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.GrantAPIProductAccess500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the scopes of the given API products from the client's allowed OAuth scopes.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, err := s.findClient(ctx, request.Id)
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.RevokeAPIProductAccess404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.RevokeAPIProductAccess500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	if !isOwned(client.ClientName) {
+		return portalv1.RevokeAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	var scopes []string
+	for _, scope := range client.AllowedOAuthScopes {
+		if !slices.ContainsFunc(request.Params.ApiProducts, func(apiProduct string) bool {
+			return s.apiProductScope(apiProduct) == scope
+		}) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == len(client.AllowedOAuthScopes) {
+		return portalv1.RevokeAPIProductAccess204Response{}, nil
+	}
+
+	if err := s.updateClientScopes(ctx, client, scopes); err != nil {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapCognitoError(err)), nil
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct creates a scope for the API product on the Cognito resource server, creating the resource server
+// if it does not exist yet.
+func (s *StrictServerHandler) CreateAPIProduct(
+	ctx context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product id is required")), nil
+	}
+
+	description := request.Body.Id
+	if request.Body.Description != nil && len(*request.Body.Description) > 0 {
+		description = *request.Body.Description
+	}
+	scope := types.ResourceServerScopeType{
+		ScopeName:        aws.String(request.Body.Id),
+		ScopeDescription: aws.String(description),
+	}
+
+	out, err := s.cognitoClient.DescribeResourceServer(ctx, &cognito.DescribeResourceServerInput{
+		UserPoolId: &s.userPool,
+		Identifier: &s.resourceServer,
+	})
+	if err != nil {
+		cognitoErr := unwrapCognitoError(err)
+		if cognitoErr.Code != 404 {
+			return portalv1.CreateAPIProduct500JSONResponse(cognitoErr), nil
+		}
+
+		// The resource server is created along with the first API product.
+		_, err = s.cognitoClient.CreateResourceServer(ctx, &cognito.CreateResourceServerInput{
+			UserPoolId: &s.userPool,
+			Identifier: &s.resourceServer,
+			Name:       &s.resourceServer,
+			Scopes:     []types.ResourceServerScopeType{scope},
+		})
+		if err != nil {
+			return portalv1.CreateAPIProduct500JSONResponse(unwrapCognitoError(err)), nil
+		}
+
+		return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+	}
+
+	for _, existing := range out.ResourceServer.Scopes {
+		if aws.ToString(existing.ScopeName) == request.Body.Id {
+			return portalv1.CreateAPIProduct409JSONResponse(connector.NewPortal409Error("API product " + request.Body.Id + " already exists")), nil
+		}
+	}
+
+	_, err = s.cognitoClient.UpdateResourceServer(ctx, &cognito.UpdateResourceServerInput{
+		UserPoolId: &s.userPool,
+		Identifier: &s.resourceServer,
+		Name:       out.ResourceServer.Name,
+		Scopes:     append(out.ResourceServer.Scopes, scope),
+	})
+	if err != nil {
+		return portalv1.CreateAPIProduct500JSONResponse(unwrapCognitoError(err)), nil
+	}
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct removes the scope for the API product from the Cognito resource server.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	ctx context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	out, err := s.cognitoClient.DescribeResourceServer(ctx, &cognito.DescribeResourceServerInput{
+		UserPoolId: &s.userPool,
+		Identifier: &s.resourceServer,
+	})
+	if err != nil {
+		switch cognitoErr := unwrapCognitoError(err); cognitoErr.Code {
+		case 404:
+			return portalv1.DeleteAPIProduct404JSONResponse(cognitoErr), nil
+		default:
+			return portalv1.DeleteAPIProduct500JSONResponse(cognitoErr), nil
+		}
+	}
+
+	var scopes []types.ResourceServerScopeType
+	for _, existing := range out.ResourceServer.Scopes {
+		if aws.ToString(existing.ScopeName) != request.Id {
+			scopes = append(scopes, existing)
+		}
+	}
+
+	if len(scopes) == len(out.ResourceServer.Scopes) {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error("API product " + request.Id + " not found")), nil
+	}
+
+	_, err = s.cognitoClient.UpdateResourceServer(ctx, &cognito.UpdateResourceServerInput{
+		UserPoolId: &s.userPool,
+		Identifier: &s.resourceServer,
+		Name:       out.ResourceServer.Name,
+		Scopes:     scopes,
+	})
+	if err != nil {
+		return portalv1.DeleteAPIProduct500JSONResponse(unwrapCognitoError(err)), nil
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// apiProductScope returns the custom scope that grants access to an API product, in the form
+// <resource-server>/<api-product>.
+func (s *StrictServerHandler) apiProductScope(apiProduct string) string {
+	return s.resourceServer + "/" + apiProduct
+}
+
+func (s *StrictServerHandler) describeClient(ctx context.Context, clientId string) (*types.UserPoolClientType, error) {
+	out, err := s.cognitoClient.DescribeUserPoolClient(ctx, &cognito.DescribeUserPoolClientInput{
+		UserPoolId: &s.userPool,
+		ClientId:   aws.String(clientId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.UserPoolClient, nil
+}
+
+// findClient returns the client of the application with the given ID. Applications are looked up by client name, which
+// is the ID Portal gave the application, and then by client ID. Cognito cannot filter clients by name, so every page of
+// clients may need to be listed.
+func (s *StrictServerHandler) findClient(ctx context.Context, id string) (*types.UserPoolClientType, error) {
+	description, err := s.findClientByName(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if description != nil {
+		return s.describeClient(ctx, aws.ToString(description.ClientId))
+	}
+
+	if clientIdPattern.MatchString(id) {
+		return s.describeClient(ctx, id)
+	}
+
+	return nil, &types.ResourceNotFoundException{Message: aws.String("no client matches name [" + id + "]")}
+}
+
+// findClientByName pages through the clients of the user pool for the first one with the name, with or without the
+// prefix of the clients created by the connector, and returns nil if there is none.
+func (s *StrictServerHandler) findClientByName(ctx context.Context, name string) (*types.UserPoolClientDescription, error) {
+	var nextToken *string
+	for {
+		out, err := s.cognitoClient.ListUserPoolClients(ctx, &cognito.ListUserPoolClientsInput{
+			UserPoolId: &s.userPool,
+			MaxResults: aws.Int32(maxListResults),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range out.UserPoolClients {
+			if clientName := aws.ToString(out.UserPoolClients[i].ClientName); clientName == clientNamePrefix+name || clientName == name {
+				return &out.UserPoolClients[i], nil
+			}
+		}
+
+		if nextToken = out.NextToken; nextToken == nil {
+			return nil, nil
+		}
+	}
+}
+
+// updateClientScopes sets the allowed OAuth scopes of the client. Cognito resets any setting that is not included in
+// an update to its default, so all other settings are copied from the existing client.
+func (s *StrictServerHandler) updateClientScopes(ctx context.Context, client *types.UserPoolClientType, scopes []string) error {
+	input := &cognito.UpdateUserPoolClientInput{
+		UserPoolId:                               &s.userPool,
+		ClientId:                                 client.ClientId,
+		ClientName:                               client.ClientName,
+		AccessTokenValidity:                      client.AccessTokenValidity,
+		AnalyticsConfiguration:                   client.AnalyticsConfiguration,
+		AuthSessionValidity:                      client.AuthSessionValidity,
+		CallbackURLs:                             client.CallbackURLs,
+		DefaultRedirectURI:                       client.DefaultRedirectURI,
+		EnablePropagateAdditionalUserContextData: client.EnablePropagateAdditionalUserContextData,
+		EnableTokenRevocation:                    client.EnableTokenRevocation,
+		ExplicitAuthFlows:                        client.ExplicitAuthFlows,
+		IdTokenValidity:                          client.IdTokenValidity,
+		LogoutURLs:                               client.LogoutURLs,
+		PreventUserExistenceErrors:               client.PreventUserExistenceErrors,
+		ReadAttributes:                           client.ReadAttributes,
+		RefreshTokenValidity:                     client.RefreshTokenValidity,
+		SupportedIdentityProviders:               client.SupportedIdentityProviders,
+		TokenValidityUnits:                       client.TokenValidityUnits,
+		WriteAttributes:                          client.WriteAttributes,
+	}
+
+	// The client credentials flow can only be enabled while the client has at least one custom scope.
+	if len(scopes) > 0 {
+		input.AllowedOAuthFlows = []types.OAuthFlowType{types.OAuthFlowTypeClientCredentials}
+		input.AllowedOAuthFlowsUserPoolClient = true
+		input.AllowedOAuthScopes = scopes
+	}
+
+	_, err := s.cognitoClient.UpdateUserPoolClient(ctx, input)
+	return err
+}
+
+// applicationDetails returns the client without its secret.
+func applicationDetails(client *types.UserPoolClientType) portalv1.OAuthApplicationDetails {
+	scopes := client.AllowedOAuthScopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return portalv1.OAuthApplicationDetails{
+		ClientId:   aws.ToString(client.ClientId),
+		ClientName: portalId(client.ClientName),
+		Scopes:     scopes,
+	}
+}
+
+// isOwned reports whether the name of a client marks it as created by IdP Connect.
+func isOwned(clientName *string) bool {
+	return strings.HasPrefix(aws.ToString(clientName), clientNamePrefix)
+}
+
+// portalId returns the name of a client without the prefix of the clients created by the connector, which is the ID
+// Portal gave the application.
+func portalId(clientName *string) *string {
+	return aws.String(strings.TrimPrefix(aws.ToString(clientName), clientNamePrefix))
+}
+
 func unwrapCognitoError(err error) portalv1.Error {
 	var notFoundErr *types.ResourceNotFoundException
 	if ok := errors.As(err, &notFoundErr); ok {
@@ -125,6 +642,25 @@ func unwrapCognitoError(err error) portalv1.Error {
 		}
 	}
 
+	var scopeErr *types.ScopeDoesNotExistException
+	if ok := errors.As(err, &scopeErr); ok {
+		return portalv1.Error{
+			Code:    404,
+			Message: "Scope Not Found",
+			Reason:  scopeErr.Error(),
+		}
+	}
+
+	// Cognito responds with 400 when it rate limits calls, so this is told apart by the exception.
+	var throttleErr *types.TooManyRequestsException
+	if ok := errors.As(err, &throttleErr); ok {
+		return portalv1.Error{
+			Code:    429,
+			Message: "Too Many Requests",
+			Reason:  throttleErr.Error(),
+		}
+	}
+
 	var respErr *http.ResponseError
 	if ok := errors.As(err, &respErr); ok {
 		return portalv1.Error{
@@ -134,5 +670,5 @@ func unwrapCognitoError(err error) portalv1.Error {
 		}
 	}
 
-	return newPortal500Error(err.Error())
+	return connector.NewPortal500Error(err.Error())
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
@@ -44,6 +45,20 @@ var _ = Describe("Server", func() {
 		}, mockCognitoClient)
 	})
 
+	// expectListClients expects the clients of the user pool to be listed to find one by name, and returns the given
+	// clients on a single page.
+	expectListClients := func(clients ...types.UserPoolClientDescription) {
+		mockCognitoClient.EXPECT().ListUserPoolClients(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(
+				ctx context.Context,
+				input *cognito.ListUserPoolClientsInput,
+				optFns ...interface{},
+			) (*cognito.ListUserPoolClientsOutput, error) {
+				Expect(*input.MaxResults).To(BeEquivalentTo(60))
+				return &cognito.ListUserPoolClientsOutput{UserPoolClients: clients}, nil
+			})
+	}
+
 	Context("Client", func() {
 		When("no client exists", func() {
 			BeforeEach(func() {
@@ -57,6 +72,7 @@ var _ = Describe("Server", func() {
 						input *cognito.CreateUserPoolClientInput,
 						optFns ...interface{},
 					) (*cognito.CreateUserPoolClientOutput, error) {
+						Expect(*input.ClientName).To(Equal("gloo-portal-idp-connect." + applicationClientId))
 
 						return &cognito.CreateUserPoolClientOutput{
 							UserPoolClient: &types.UserPoolClientType{
@@ -82,6 +98,8 @@ var _ = Describe("Server", func() {
 				)
 			})
 			It("can create a client", func() {
+				expectListClients()
+
 				resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
 					Body: &portalv1.CreateOAuthApplicationJSONRequestBody{
 						Id: applicationClientId,
@@ -118,6 +136,8 @@ var _ = Describe("Server", func() {
 			})
 
 			It("returns not found code on deletion", func() {
+				expectListClients()
+
 				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
 					Id: "test-client",
 					Params: portalv1.DeleteOAuthApplicationParams{
@@ -161,9 +181,22 @@ var _ = Describe("Server", func() {
 					})
 			})
 
-			It("can delete the client", func() {
+			// expectFindClient expects the client to be found by name, with the given name in the user pool.
+			expectFindClient := func(name string) {
+				expectListClients(types.UserPoolClientDescription{ClientId: aws.String(clientId), ClientName: aws.String(name)})
+				mockCognitoClient.EXPECT().DescribeUserPoolClient(ctx, gomock.Any(), gomock.Any()).Return(
+					&cognito.DescribeUserPoolClientOutput{
+						UserPoolClient: &types.UserPoolClientType{ClientId: aws.String(clientId), ClientName: aws.String(name)},
+					},
+					nil,
+				)
+			}
+
+			It("can delete the client by name", func() {
+				expectFindClient("gloo-portal-idp-connect." + clientName)
+
 				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
-					Id: clientId,
+					Id: clientName,
 					Params: portalv1.DeleteOAuthApplicationParams{
 						Token: &testToken,
 					},
@@ -172,6 +205,528 @@ var _ = Describe("Server", func() {
 				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
 			})
 
+			It("refuses to delete a client that it did not create", func() {
+				expectFindClient(clientName)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: clientName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+			})
+
+			It("refuses to rotate the secret of a client that it did not create", func() {
+				expectFindClient(clientName)
+
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: clientName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret403JSONResponse{}))
+			})
+
+			It("deletes a client that it did not create when forced to", func() {
+				forced := &server.Options{
+					CognitoUserPool: userPoolID,
+					ResourceServer:  resourceServer,
+				}
+				forced.ForceDelete = true
+				s = server.NewStrictServerHandler(forced, mockCognitoClient)
+				expectFindClient(clientName)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: clientName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+
+			It("returns conflict code when creating a client with the same name", func() {
+				expectListClients(types.UserPoolClientDescription{ClientId: aws.String(clientId), ClientName: aws.String(clientName)})
+
+				resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+					Body: &portalv1.CreateOAuthApplicationJSONRequestBody{
+						Id: clientName,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+				Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(clientId))
+			})
+		})
+	})
+
+	Context("Client details", func() {
+		var clientId = "2r7vpfuuhbimiqq9bmfde1e3t3"
+
+		BeforeEach(func() {
+			mockCognitoClient.EXPECT().DescribeUserPoolClient(ctx, gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.DescribeUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.DescribeUserPoolClientOutput, error) {
+					if *input.ClientId != clientId {
+						return nil, &types.ResourceNotFoundException{Message: aws.String("client does not exist")}
+					}
+					return &cognito.DescribeUserPoolClientOutput{
+						UserPoolClient: &types.UserPoolClientType{
+							ClientId:           aws.String(clientId),
+							ClientName:         aws.String("gloo-portal-idp-connect." + applicationClientId),
+							ClientSecret:       aws.String("6au6kel0b"),
+							AllowedOAuthScopes: []string{resourceServer + "/tracks-rest-api"},
+						},
+					}, nil
+				})
+		})
+
+		It("lists the clients that it created with their scopes", func() {
+			mockCognitoClient.EXPECT().ListUserPoolClients(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.ListUserPoolClientsInput,
+					optFns ...interface{},
+				) (*cognito.ListUserPoolClientsOutput, error) {
+					Expect(*input.MaxResults).To(BeEquivalentTo(10))
+					Expect(*input.NextToken).To(Equal("page-2"))
+					return &cognito.ListUserPoolClientsOutput{
+						UserPoolClients: []types.UserPoolClientDescription{
+							{ClientId: aws.String("other"), ClientName: aws.String("other-app")},
+							{ClientId: aws.String(clientId), ClientName: aws.String("gloo-portal-idp-connect." + applicationClientId)},
+						},
+						NextToken: aws.String("page-3"),
+					}, nil
+				})
+
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{
+					Limit:  aws.Int(10),
+					Cursor: aws.String("page-2"),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications200JSONResponse{}))
+			page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+			Expect(page.Applications).To(ConsistOf(portalv1.OAuthApplicationDetails{
+				ClientId:   clientId,
+				ClientName: aws.String(applicationClientId),
+				Scopes:     []string{resourceServer + "/tracks-rest-api"},
+			}))
+			Expect(*page.NextCursor).To(Equal("page-3"))
+		})
+
+		It("returns too many requests code when Cognito is still rate limiting calls", func() {
+			mockCognitoClient.EXPECT().ListUserPoolClients(ctx, gomock.Any(), gomock.Any()).Return(
+				nil, &types.TooManyRequestsException{Message: aws.String("rate exceeded")})
+
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications500JSONResponse{}))
+			Expect(resp.(portalv1.ListOAuthApplications500JSONResponse).Code).To(Equal(429))
+		})
+
+		It("gets the client by name without its secret", func() {
+			expectListClients(types.UserPoolClientDescription{ClientId: aws.String(clientId), ClientName: aws.String(applicationClientId)})
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			Expect(resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes).To(ConsistOf(resourceServer + "/tracks-rest-api"))
+		})
+
+		It("gets the client by client ID when no client has it as its name", func() {
+			expectListClients(types.UserPoolClientDescription{ClientId: aws.String("other"), ClientName: aws.String("other-app")})
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: clientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			Expect(resp.(portalv1.GetOAuthApplication200JSONResponse).ClientId).To(Equal(clientId))
+		})
+
+		It("finds the client by name on a later page", func() {
+			gomock.InOrder(
+				mockCognitoClient.EXPECT().ListUserPoolClients(ctx, gomock.Any(), gomock.Any()).Return(
+					&cognito.ListUserPoolClientsOutput{
+						UserPoolClients: []types.UserPoolClientDescription{{ClientId: aws.String("other"), ClientName: aws.String("other-app")}},
+						NextToken:       aws.String("page-2"),
+					},
+					nil,
+				),
+				mockCognitoClient.EXPECT().ListUserPoolClients(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context,
+						input *cognito.ListUserPoolClientsInput,
+						optFns ...interface{},
+					) (*cognito.ListUserPoolClientsOutput, error) {
+						Expect(*input.NextToken).To(Equal("page-2"))
+						return &cognito.ListUserPoolClientsOutput{
+							UserPoolClients: []types.UserPoolClientDescription{{ClientId: aws.String(clientId), ClientName: aws.String(applicationClientId)}},
+						}, nil
+					}),
+			)
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+		})
+
+		It("replaces the client to rotate its secret", func() {
+			newClientId := "5k2c8tgr4vbd1l0e6n9q3m7h2a"
+			expectListClients(types.UserPoolClientDescription{ClientId: aws.String(clientId), ClientName: aws.String(applicationClientId)})
+
+			mockCognitoClient.EXPECT().CreateUserPoolClient(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.CreateUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.CreateUserPoolClientOutput, error) {
+					Expect(*input.ClientName).To(Equal("gloo-portal-idp-connect." + applicationClientId))
+					Expect(input.GenerateSecret).To(BeTrue())
+					Expect(input.AllowedOAuthScopes).To(ConsistOf(resourceServer + "/tracks-rest-api"))
+					return &cognito.CreateUserPoolClientOutput{
+						UserPoolClient: &types.UserPoolClientType{
+							ClientId:     aws.String(newClientId),
+							ClientName:   input.ClientName,
+							ClientSecret: aws.String("r0t4t3d"),
+						},
+					}, nil
+				})
+			mockCognitoClient.EXPECT().DeleteUserPoolClient(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.DeleteUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.DeleteUserPoolClientOutput, error) {
+					Expect(*input.ClientId).To(Equal(clientId))
+					return &cognito.DeleteUserPoolClientOutput{}, nil
+				})
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(newClientId))
+			Expect(rotated.ClientSecret).To(Equal("r0t4t3d"))
+		})
+
+		It("returns not found code when rotating the secret of a missing client", func() {
+			expectListClients()
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+				Id: "non-existing-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret404JSONResponse{}))
+		})
+
+		It("returns not found code when the client does not exist", func() {
+			expectListClients()
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: "non-existing-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+	})
+
+	Context("API Product access", func() {
+		var (
+			clientId = "2r7vpfuuhbimiqq9bmfde1e3t3"
+			client   *types.UserPoolClientType
+		)
+
+		BeforeEach(func() {
+			client = &types.UserPoolClientType{
+				ClientId:             aws.String(clientId),
+				ClientName:           aws.String("gloo-portal-idp-connect." + applicationClientId),
+				RefreshTokenValidity: 30,
+				AllowedOAuthScopes:   []string{resourceServer + "/tracks-rest-api"},
+			}
+
+			mockCognitoClient.EXPECT().DescribeUserPoolClient(ctx, gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.DescribeUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.DescribeUserPoolClientOutput, error) {
+					if *input.ClientId != clientId {
+						return nil, &types.ResourceNotFoundException{Message: aws.String("client does not exist")}
+					}
+					return &cognito.DescribeUserPoolClientOutput{UserPoolClient: client}, nil
+				})
+		})
+
+		expectFindClient := func() {
+			expectListClients(types.UserPoolClientDescription{ClientId: aws.String(clientId), ClientName: aws.String(applicationClientId)})
+		}
+
+		It("adds the API product scopes to the client", func() {
+			expectFindClient()
+			mockCognitoClient.EXPECT().UpdateUserPoolClient(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.UpdateUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.UpdateUserPoolClientOutput, error) {
+					Expect(input.AllowedOAuthScopes).To(ConsistOf(resourceServer+"/tracks-rest-api", resourceServer+"/catstronauts-api"))
+					Expect(input.AllowedOAuthFlows).To(ConsistOf(types.OAuthFlowTypeClientCredentials))
+					Expect(input.AllowedOAuthFlowsUserPoolClient).To(BeTrue())
+					// Existing settings are preserved
+					Expect(*input.ClientName).To(Equal("gloo-portal-idp-connect." + applicationClientId))
+					Expect(input.RefreshTokenValidity).To(Equal(int32(30)))
+					return &cognito.UpdateUserPoolClientOutput{}, nil
+				})
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{"tracks-rest-api", "catstronauts-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+		})
+
+		It("returns not found code when the API product does not exist", func() {
+			expectFindClient()
+			mockCognitoClient.EXPECT().UpdateUserPoolClient(ctx, gomock.Any(), gomock.Any()).Return(
+				nil,
+				&types.ScopeDoesNotExistException{Message: aws.String("Invalid scope requested: access/unknown-api")},
+			)
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{"unknown-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns not found code when the client does not exist", func() {
+			expectListClients()
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: "unknown-client",
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{"tracks-rest-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("refuses to change the API products of a client that it did not create", func() {
+			client.ClientName = aws.String(applicationClientId)
+			expectFindClient()
+			expectFindClient()
+
+			grant, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{"catstronauts-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grant).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess403JSONResponse{}))
+
+			revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{"tracks-rest-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess403JSONResponse{}))
+		})
+
+		It("removes the client credentials flow along with the last scope", func() {
+			expectFindClient()
+			mockCognitoClient.EXPECT().UpdateUserPoolClient(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.UpdateUserPoolClientInput,
+					optFns ...interface{},
+				) (*cognito.UpdateUserPoolClientOutput, error) {
+					Expect(input.AllowedOAuthScopes).To(BeEmpty())
+					Expect(input.AllowedOAuthFlows).To(BeEmpty())
+					Expect(input.AllowedOAuthFlowsUserPoolClient).To(BeFalse())
+					return &cognito.UpdateUserPoolClientOutput{}, nil
+				})
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{"tracks-rest-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+		})
+
+		It("does not update the client when revoking an API product it cannot access", func() {
+			expectFindClient()
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{"catstronauts-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+		})
+	})
+
+	Context("API Product", func() {
+		var (
+			apiProductId = "tracks-rest-api"
+			notFoundErr  = &types.ResourceNotFoundException{Message: aws.String("resource server does not exist")}
+		)
+
+		When("no resource server exists", func() {
+			BeforeEach(func() {
+				mockCognitoClient.EXPECT().DescribeResourceServer(ctx, gomock.Any(), gomock.Any()).AnyTimes().Return(nil, notFoundErr)
+			})
+
+			It("creates the resource server with the API product scope", func() {
+				mockCognitoClient.EXPECT().CreateResourceServer(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context,
+						input *cognito.CreateResourceServerInput,
+						optFns ...interface{},
+					) (*cognito.CreateResourceServerOutput, error) {
+						Expect(*input.Identifier).To(Equal(resourceServer))
+						Expect(input.Scopes).To(HaveLen(1))
+						Expect(*input.Scopes[0].ScopeName).To(Equal(apiProductId))
+						return &cognito.CreateResourceServerOutput{}, nil
+					})
+
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+			})
+
+			It("returns error code on empty API product id", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct400JSONResponse{}))
+			})
+
+			It("returns not found code on deletion", func() {
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct404JSONResponse{}))
+			})
+		})
+
+		When("resource server exists", func() {
+			BeforeEach(func() {
+				mockCognitoClient.EXPECT().DescribeResourceServer(ctx, gomock.Any(), gomock.Any()).AnyTimes().Return(
+					&cognito.DescribeResourceServerOutput{
+						ResourceServer: &types.ResourceServerType{
+							Identifier: aws.String(resourceServer),
+							Name:       aws.String(resourceServer),
+							Scopes: []types.ResourceServerScopeType{
+								{
+									ScopeName:        aws.String(apiProductId),
+									ScopeDescription: aws.String(apiProductId),
+								},
+							},
+						},
+					},
+					nil,
+				)
+			})
+
+			It("adds a scope for a new API product", func() {
+				mockCognitoClient.EXPECT().UpdateResourceServer(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context,
+						input *cognito.UpdateResourceServerInput,
+						optFns ...interface{},
+					) (*cognito.UpdateResourceServerOutput, error) {
+						Expect(input.Scopes).To(HaveLen(2))
+						Expect(*input.Scopes[1].ScopeName).To(Equal("catstronauts-api"))
+						return &cognito.UpdateResourceServerOutput{}, nil
+					})
+
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: "catstronauts-api",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+			})
+
+			It("returns conflict code for an existing API product", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+			})
+
+			It("can delete the API product", func() {
+				mockCognitoClient.EXPECT().UpdateResourceServer(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context,
+						input *cognito.UpdateResourceServerInput,
+						optFns ...interface{},
+					) (*cognito.UpdateResourceServerOutput, error) {
+						Expect(input.Scopes).To(BeEmpty())
+						return &cognito.UpdateResourceServerOutput{}, nil
+					})
+
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			})
+		})
+	})
+
+	Context("Readiness", func() {
+		It("is ready when the user pool can be described", func() {
+			mockCognitoClient.EXPECT().DescribeUserPool(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(
+					ctx context.Context,
+					input *cognito.DescribeUserPoolInput,
+					optFns ...interface{},
+				) (*cognito.DescribeUserPoolOutput, error) {
+					Expect(*input.UserPoolId).To(Equal(userPoolID))
+					return &cognito.DescribeUserPoolOutput{}, nil
+				})
+
+			Expect(s.Ready(ctx)).To(Succeed())
+		})
+
+		It("is not ready when the user pool cannot be described", func() {
+			mockCognitoClient.EXPECT().DescribeUserPool(ctx, gomock.Any(), gomock.Any()).Return(
+				nil, &types.ResourceNotFoundException{})
+
+			Expect(s.Ready(ctx)).NotTo(Succeed())
 		})
 	})
 })
@@ -121,6 +121,66 @@ func (mr *MockCognitoClientMockRecorder) DescribeResourceServer(ctx, params any,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeResourceServer", reflect.TypeOf((*MockCognitoClient)(nil).DescribeResourceServer), varargs...)
 }
 
+// DescribeUserPool mocks base method.
+func (m *MockCognitoClient) DescribeUserPool(ctx context.Context, params *cognitoidentityprovider.DescribeUserPoolInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.DescribeUserPoolOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeUserPool", varargs...)
+	ret0, _ := ret[0].(*cognitoidentityprovider.DescribeUserPoolOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeUserPool indicates an expected call of DescribeUserPool.
+func (mr *MockCognitoClientMockRecorder) DescribeUserPool(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeUserPool", reflect.TypeOf((*MockCognitoClient)(nil).DescribeUserPool), varargs...)
+}
+
+// DescribeUserPoolClient mocks base method.
+func (m *MockCognitoClient) DescribeUserPoolClient(ctx context.Context, params *cognitoidentityprovider.DescribeUserPoolClientInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.DescribeUserPoolClientOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeUserPoolClient", varargs...)
+	ret0, _ := ret[0].(*cognitoidentityprovider.DescribeUserPoolClientOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeUserPoolClient indicates an expected call of DescribeUserPoolClient.
+func (mr *MockCognitoClientMockRecorder) DescribeUserPoolClient(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeUserPoolClient", reflect.TypeOf((*MockCognitoClient)(nil).DescribeUserPoolClient), varargs...)
+}
+
+// ListUserPoolClients mocks base method.
+func (m *MockCognitoClient) ListUserPoolClients(ctx context.Context, params *cognitoidentityprovider.ListUserPoolClientsInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.ListUserPoolClientsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListUserPoolClients", varargs...)
+	ret0, _ := ret[0].(*cognitoidentityprovider.ListUserPoolClientsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPoolClients indicates an expected call of ListUserPoolClients.
+func (mr *MockCognitoClientMockRecorder) ListUserPoolClients(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPoolClients", reflect.TypeOf((*MockCognitoClient)(nil).ListUserPoolClients), varargs...)
+}
+
 // UpdateResourceServer mocks base method.
 func (m *MockCognitoClient) UpdateResourceServer(ctx context.Context, params *cognitoidentityprovider.UpdateResourceServerInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.UpdateResourceServerOutput, error) {
 	m.ctrl.T.Helper()
@@ -2,30 +2,31 @@ package server
 
 import (
 	"context"
-	"log"
-	"net"
+	"errors"
 	"net/http"
+	"time"
 
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
-	"github.com/getkin/kin-openapi/openapi3filter"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
-	middleware "github.com/oapi-codegen/echo-middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/rotisserie/eris"
 	"github.com/spf13/pflag"
 
-	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 )
 
 type Options struct {
-	Port            string
+	connector.Options
 	CognitoUserPool string
 	ResourceServer  string
 }
 
 func (o *Options) AddToFlags(flag *pflag.FlagSet) {
-	flag.StringVar(&o.Port, "port", "8080", "Port for HTTP server")
+	o.Options.AddToFlags(flag)
 	flag.StringVar(&o.CognitoUserPool, "user-pool-id", "", "User pool ID")
 	flag.StringVar(&o.ResourceServer, "resource-server", "", "Resource server to configure API Product scopes")
 }
@@ -45,15 +46,6 @@ func ListenAndServe(ctx context.Context, opts *Options) error {
 		return err
 	}
 
-	swagger, err := portalv1.GetSwagger()
-	if err != nil {
-		return eris.Wrap(err, "could not load swagger spec")
-	}
-
-	// Clear out the servers array in the swagger spec, that skips validating
-	// that server names match. We don't know how this thing will be run.
-	swagger.Servers = nil
-
 	// Unless performance is a concern, always use LoadDefaultConfig because it will search the environment
 	// for valid configuration; this allows users maximum flexibility and provides break-glass provider
 	// configuration options
@@ -62,33 +54,65 @@ func ListenAndServe(ctx context.Context, opts *Options) error {
 		return eris.Wrap(err, "failed to locate aws configuration using full provider chain")
 	}
 
-	cognitoClient := cognito.NewFromConfig(cfg)
-	// Create an instance of our handler which satisfies the generated interface
-	congitoHandler := NewStrictServerHandler(opts, cognitoClient)
-	portalHandler := portalv1.NewStrictHandler(congitoHandler, nil)
+	cognitoClient := cognito.NewFromConfig(cfg, func(o *cognito.Options) {
+		o.HTTPClient = &http.Client{
+			Transport: connector.NewTracingTransport("cognito", connector.NewUpstreamTransport("cognito",
+				connector.NewTimeoutTransport(opts.UpstreamTimeout, awshttp.NewBuildableClient().GetTransport()),
+			)),
+		}
+		o.APIOptions = append(o.APIOptions, labelUpstreamOperation, breakCircuit(opts.CircuitBreaker("cognito")))
+		policy := opts.RetryPolicy()
+		o.Retryer = retry.NewStandard(func(so *retry.StandardOptions) {
+			so.MaxAttempts = max(policy.MaxAttempts, 1)
+			so.MaxBackoff = policy.MaxDelay
+			so.Backoff = retryBackoff(policy)
+		})
+	})
+
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, cognitoClient))
+}
 
-	e := echo.New()
+// retryBackoff backs off between retries of Cognito calls with the retry policy, honoring the delay that a response
+// asks for.
+type retryBackoff connector.RetryPolicy
 
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	e.Use(echomiddleware.Logger())
+func (b retryBackoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		if delay, ok := connector.RetryAfter(respErr.HTTPResponse().Response); ok {
+			return min(delay, b.MaxDelay), nil
+		}
+	}
 
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	e.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
-		Options: openapi3filter.Options{
-			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
-		}},
-	))
+	return connector.RetryPolicy(b).Backoff(attempt), nil
+}
 
-	// We now register our petStore above as the handler for the interface
-	portalv1.RegisterHandlers(e, portalHandler)
+// labelUpstreamOperation labels each Cognito call with its API operation name for the upstream metrics.
+func labelUpstreamOperation(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("UpstreamOperation", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		return next.HandleInitialize(connector.WithUpstreamOperation(ctx, awsmiddleware.GetOperationName(ctx)), in)
+	}), middleware.After)
+}
 
-	s := &http.Server{
-		Handler: e,
-		Addr:    net.JoinHostPort("0.0.0.0", opts.Port),
+// breakCircuit makes each Cognito call through the circuit breaker, around the retries of the SDK rather than below
+// them, so that a call counts once whatever the number of attempts, as it does in the other connectors.
+func breakCircuit(breaker *connector.CircuitBreaker) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("CircuitBreaker", func(
+			ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+		) (out middleware.FinalizeOutput, metadata middleware.Metadata, err error) {
+			err = breaker.Call(ctx, func(ctx context.Context) (int, error) {
+				out, metadata, err = next.HandleFinalize(ctx, in)
+
+				var respErr *smithyhttp.ResponseError
+				if errors.As(err, &respErr) {
+					return respErr.HTTPStatusCode(), err
+				}
+				return 0, err
+			})
+			return out, metadata, err
+		}), (&retry.Attempt{}).ID(), middleware.Before)
 	}
-
-	log.Printf("Starting server on port %v\n", opts.Port)
-	return s.ListenAndServe()
 }
@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lestrrat-go/httprc/v3"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/rotisserie/eris"
+)
+
+// tokenHeader is the request header that carries the token of the user invoking the request.
+const tokenHeader = "token"
+
+// clockSkew is the leeway allowed when checking the expiry and not-before time of tokens.
+const clockSkew = time.Minute
+
+// tokenVerifier checks that the token sent with each API request is a JWT signed by one of its keys, and that it was
+// issued by and for the configured parties.
+type tokenVerifier struct {
+	keys     jwk.Set
+	issuer   string
+	audience string
+}
+
+// newTokenVerifier returns a verifier for the keys at the JWKS URL or in the key file, or nil if neither is configured
+// and so tokens are not verified. Keys at the JWKS URL are fetched before returning, and refreshed in the background
+// until the context is cancelled.
+func newTokenVerifier(ctx context.Context, opts *Options) (*tokenVerifier, error) {
+	var keys jwk.Set
+	switch {
+	case opts.JWKSURL != "" && opts.JWTKeyFile != "":
+		return nil, eris.New("only one of the JWKS URL and the JWT key file can be set")
+	case opts.JWKSURL != "":
+		cache, err := jwk.NewCache(ctx, httprc.NewClient())
+		if err != nil {
+			return nil, eris.Wrap(err, "could not create JWKS cache")
+		}
+		if err := cache.Register(ctx, opts.JWKSURL); err != nil {
+			return nil, eris.Wrapf(err, "could not fetch JWKS from %s", opts.JWKSURL)
+		}
+		if keys, err = cache.CachedSet(opts.JWKSURL); err != nil {
+			return nil, eris.Wrapf(err, "could not fetch JWKS from %s", opts.JWKSURL)
+		}
+	case opts.JWTKeyFile != "":
+		data, err := os.ReadFile(opts.JWTKeyFile)
+		if err != nil {
+			return nil, eris.Wrapf(err, "could not read JWT key file %s", opts.JWTKeyFile)
+		}
+		// The file holds either a JWK or JWKS, or PEM encoded public keys.
+		isPEM := bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN"))
+		if keys, err = jwk.Parse(data, jwk.WithPEM(isPEM)); err != nil {
+			return nil, eris.Wrapf(err, "could not parse JWT key file %s", opts.JWTKeyFile)
+		}
+	default:
+		if opts.JWTIssuer != "" || opts.JWTAudience != "" {
+			return nil, eris.New("a JWKS URL or JWT key file is required to verify tokens")
+		}
+		return nil, nil
+	}
+
+	return &tokenVerifier{
+		keys:     keys,
+		issuer:   opts.JWTIssuer,
+		audience: opts.JWTAudience,
+	}, nil
+}
+
+// middleware rejects API requests with a missing or invalid token with 401, and requests with a valid token that was
+// not issued by and for the configured parties with 403.
+func (v *tokenVerifier) middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if isProbe(c) {
+				return next(c)
+			}
+
+			raw := c.Request().Header.Get(tokenHeader)
+			if raw == "" {
+				return respondWithError(c, NewPortal401Error("Missing token"))
+			}
+
+			token, err := jwt.Parse([]byte(raw),
+				jwt.WithKeySet(v.keys, jws.WithInferAlgorithmFromKey(true)),
+				jwt.WithValidate(true),
+				jwt.WithAcceptableSkew(clockSkew),
+			)
+			if err != nil {
+				return respondWithError(c, NewPortal401Error("Invalid token: "+err.Error()))
+			}
+
+			validateOpts := []jwt.ValidateOption{jwt.WithAcceptableSkew(clockSkew)}
+			if v.issuer != "" {
+				validateOpts = append(validateOpts, jwt.WithIssuer(v.issuer))
+			}
+			if v.audience != "" {
+				validateOpts = append(validateOpts, jwt.WithAudience(v.audience))
+			}
+			if err := jwt.Validate(token, validateOpts...); err != nil {
+				return respondWithError(c, NewPortal403Error("Token not permitted: "+err.Error()))
+			}
+
+			return next(c)
+		}
+	}
+}
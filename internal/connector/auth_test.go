@@ -0,0 +1,208 @@
+package connector_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const (
+	tokenIssuer   = "https://portal.example.com"
+	tokenAudience = "idp-connect"
+)
+
+// newSigningKey returns a new RSA private key, with its public key as a JWKS.
+func newSigningKey() (jwk.Key, []byte) {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+
+	key, err := jwk.Import(raw)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(key.Set(jwk.KeyIDKey, "test")).To(Succeed())
+
+	public, err := key.PublicKey()
+	Expect(err).NotTo(HaveOccurred())
+	set := jwk.NewSet()
+	Expect(set.AddKey(public)).To(Succeed())
+
+	jwks, err := json.Marshal(set)
+	Expect(err).NotTo(HaveOccurred())
+	return key, jwks
+}
+
+// sign returns a token signed with the key, that was issued for the connector unless changed by the modifier.
+func sign(key jwk.Key, modify func(*jwt.Builder) *jwt.Builder) string {
+	builder := jwt.NewBuilder().
+		Issuer(tokenIssuer).
+		Audience([]string{tokenAudience}).
+		Expiration(time.Now().Add(time.Hour))
+	if modify != nil {
+		builder = modify(builder)
+	}
+	token, err := builder.Build()
+	Expect(err).NotTo(HaveOccurred())
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256(), key))
+	Expect(err).NotTo(HaveOccurred())
+	return string(signed)
+}
+
+var _ = Describe("Token verification", func() {
+	var (
+		key  jwk.Key
+		jwks []byte
+		opts *connector.Options
+	)
+
+	serve := func() {
+		provider := &fakeProvider{
+			received: make(chan struct{}),
+			release:  make(chan struct{}),
+		}
+		close(provider.release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		go func() {
+			_ = connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			_, err := http.Get("http://127.0.0.1:" + opts.Port + "/healthz")
+			return err
+		}).Should(Succeed())
+	}
+
+	list := func(token string) (int, portalv1.Error) {
+		req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:"+opts.Port+"/applications", nil)
+		Expect(err).NotTo(HaveOccurred())
+		if token != "" {
+			req.Header.Set("token", token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		var portalErr portalv1.Error
+		if resp.StatusCode != http.StatusOK {
+			Expect(json.NewDecoder(resp.Body).Decode(&portalErr)).To(Succeed())
+		}
+		return resp.StatusCode, portalErr
+	}
+
+	BeforeEach(func() {
+		key, jwks = newSigningKey()
+
+		opts = &connector.Options{
+			Port:                freePort(),
+			ShutdownGracePeriod: 5 * time.Second,
+			JWTIssuer:           tokenIssuer,
+			JWTAudience:         tokenAudience,
+		}
+	})
+
+	Context("with a key file", func() {
+		BeforeEach(func() {
+			opts.JWTKeyFile = filepath.Join(GinkgoT().TempDir(), "jwks.json")
+			Expect(os.WriteFile(opts.JWTKeyFile, jwks, 0o600)).To(Succeed())
+			serve()
+		})
+
+		It("accepts a valid token", func() {
+			code, _ := list(sign(key, nil))
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("rejects a request without a token", func() {
+			code, portalErr := list("")
+			Expect(code).To(Equal(http.StatusUnauthorized))
+			Expect(portalErr.Code).To(Equal(http.StatusUnauthorized))
+			Expect(portalErr.Reason).To(HavePrefix("Missing token (request ID "))
+		})
+
+		It("rejects a token signed by another key", func() {
+			otherKey, _ := newSigningKey()
+
+			code, _ := list(sign(otherKey, nil))
+			Expect(code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("rejects an expired token", func() {
+			code, _ := list(sign(key, func(b *jwt.Builder) *jwt.Builder {
+				return b.Expiration(time.Now().Add(-time.Hour))
+			}))
+			Expect(code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("forbids a token issued for another audience", func() {
+			code, portalErr := list(sign(key, func(b *jwt.Builder) *jwt.Builder {
+				return b.Audience([]string{"another-service"})
+			}))
+			Expect(code).To(Equal(http.StatusForbidden))
+			Expect(portalErr.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("forbids a token from another issuer", func() {
+			code, _ := list(sign(key, func(b *jwt.Builder) *jwt.Builder {
+				return b.Issuer("https://elsewhere.example.com")
+			}))
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("does not require a token for probes", func() {
+			resp, err := http.Get("http://127.0.0.1:" + opts.Port + "/readyz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("with a JWKS URL", func() {
+		BeforeEach(func() {
+			jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(jwks)
+			}))
+			DeferCleanup(jwksServer.Close)
+
+			opts.JWKSURL = jwksServer.URL
+			serve()
+		})
+
+		It("accepts a valid token", func() {
+			code, _ := list(sign(key, nil))
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("rejects a token signed by another key", func() {
+			otherKey, _ := newSigningKey()
+
+			code, _ := list(sign(otherKey, nil))
+			Expect(code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	It("fails to start with both a JWKS URL and a key file", func() {
+		opts.JWKSURL = "https://portal.example.com/jwks.json"
+		opts.JWTKeyFile = "jwks.json"
+
+		err := connector.ListenAndServe(context.Background(), opts, &fakeProvider{})
+		Expect(err).To(HaveOccurred())
+	})
+})
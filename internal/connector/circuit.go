@@ -0,0 +1,256 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through to the IdP.
+	CircuitClosed CircuitState = iota
+	// CircuitHalfOpen lets a single call through to find out whether the IdP has recovered, and fails the others.
+	CircuitHalfOpen
+	// CircuitOpen fails calls without making them.
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker fails calls to the IdP without making them once a number of consecutive calls have failed, so that
+// requests fail fast while the IdP is down rather than each waiting for it to time out. Once the open duration has
+// passed, a single call is let through: the circuit closes if it succeeds, and opens again if it fails.
+type CircuitBreaker struct {
+	connector    string
+	threshold    int
+	openDuration time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	retryAt  time.Time
+}
+
+// NewCircuitBreaker returns a closed circuit breaker around calls to the IdP of the connector, which opens after the
+// threshold of consecutive failed calls. It never opens if the threshold is 0.
+func NewCircuitBreaker(connector string, threshold int, openDuration time.Duration) *CircuitBreaker {
+	upstreamCircuitState.WithLabelValues(connector).Set(float64(CircuitClosed))
+
+	return &CircuitBreaker{
+		connector:    connector,
+		threshold:    threshold,
+		openDuration: openDuration,
+	}
+}
+
+// State returns the state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Check returns the error that calls fail with while the circuit is open, or nil if a call would be let through.
+func (b *CircuitBreaker) Check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen || (b.state == CircuitOpen && time.Now().Before(b.retryAt)) {
+		return b.openError()
+	}
+
+	return nil
+}
+
+// allow returns nil if a call may be made, half-opening the circuit if it has been open for long enough, or else the
+// error to fail the call with.
+func (b *CircuitBreaker) allow() *CircuitOpenError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		return b.openError()
+	case CircuitOpen:
+		if time.Now().Before(b.retryAt) {
+			return b.openError()
+		}
+		b.setState(CircuitHalfOpen)
+	}
+
+	return nil
+}
+
+// record records the outcome of a call that was let through. A call whose outcome says nothing about the IdP, such as
+// one that was cancelled by its caller, is neither a success nor a failure.
+func (b *CircuitBreaker) record(outcome callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch outcome {
+	case callSucceeded:
+		b.failures = 0
+		b.setState(CircuitClosed)
+	case callFailed:
+		b.failures++
+		if b.state == CircuitHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+			b.retryAt = time.Now().Add(b.openDuration)
+			b.setState(CircuitOpen)
+		}
+	default:
+		// Let the next call find out whether the IdP has recovered instead.
+		if b.state == CircuitHalfOpen {
+			b.setState(CircuitOpen)
+		}
+	}
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	b.state = state
+	upstreamCircuitState.WithLabelValues(b.connector).Set(float64(state))
+}
+
+func (b *CircuitBreaker) openError() *CircuitOpenError {
+	return &CircuitOpenError{
+		Connector: b.connector,
+		Failures:  b.failures,
+		RetryAt:   b.retryAt,
+	}
+}
+
+// CircuitOpenError is the error of a call to the IdP that the circuit breaker failed without making it.
+type CircuitOpenError struct {
+	Connector string
+	Failures  int
+	RetryAt   time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("the %s IdP is unavailable: calls to it fail fast after %d consecutive failed calls, until %s",
+		e.Connector, e.Failures, e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+type callOutcome int
+
+const (
+	callIgnored callOutcome = iota
+	callSucceeded
+	callFailed
+)
+
+// outcome returns the outcome of a call that the IdP responded to with the status, or did not respond to if it is 0.
+func outcome(ctx context.Context, status int, err error) callOutcome {
+	switch {
+	case err != nil && status == 0 && ctx.Err() != nil:
+		return callIgnored
+	case (err != nil && status == 0) || status >= 500:
+		return callFailed
+	default:
+		return callSucceeded
+	}
+}
+
+// Call makes a call to the IdP through the circuit breaker, or fails it with a *CircuitOpenError without making it
+// while the circuit is open. The call returns the status that the IdP last responded with, or 0 if it did not respond.
+// It is for clients that retry calls themselves, so that a call counts once whatever the number of attempts, as it does
+// with the transport of Options.UpstreamTransport.
+func (b *CircuitBreaker) Call(ctx context.Context, call func(context.Context) (int, error)) error {
+	if err := b.allow(); err != nil {
+		b.reject(ctx, err)
+		return err
+	}
+
+	status, err := call(ctx)
+	b.record(outcome(ctx, status, err))
+
+	return err
+}
+
+// reject counts a call that the circuit breaker failed, and flags the request that it was made for.
+func (b *CircuitBreaker) reject(ctx context.Context, err *CircuitOpenError) {
+	upstreamCircuitRejectionsTotal.WithLabelValues(b.connector, UpstreamOperation(ctx)).Inc()
+	if rejection, ok := ctx.Value(circuitRejectionKey{}).(*circuitRejection); ok {
+		rejection.set(err)
+	}
+}
+
+// circuitTransport makes calls to the IdP through a circuit breaker.
+type circuitTransport struct {
+	breaker *CircuitBreaker
+	base    http.RoundTripper
+}
+
+// NewCircuitBreakerTransport wraps the transport used by a connector to call its IdP, so that calls fail fast with a
+// *CircuitOpenError while the circuit is open. Calls that fail or respond with a 5xx status count as failed.
+func NewCircuitBreakerTransport(breaker *CircuitBreaker, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &circuitTransport{
+		breaker: breaker,
+		base:    base,
+	}
+}
+
+func (t *circuitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		t.breaker.reject(req.Context(), err)
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.breaker.record(outcome(req.Context(), status, err))
+
+	return resp, err
+}
+
+type circuitRejectionKey struct{}
+
+// circuitRejection holds the error of the last call that the circuit breaker failed while serving a request, so that
+// the request can be responded to with it whichever error the connector makes of it.
+type circuitRejection struct {
+	mu  sync.Mutex
+	err *CircuitOpenError
+}
+
+func withCircuitRejection(ctx context.Context) (context.Context, *circuitRejection) {
+	rejection := &circuitRejection{}
+	return context.WithValue(ctx, circuitRejectionKey{}, rejection), rejection
+}
+
+func (r *circuitRejection) set(err *CircuitOpenError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+}
+
+func (r *circuitRejection) get() *CircuitOpenError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
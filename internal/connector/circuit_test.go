@@ -0,0 +1,234 @@
+package connector_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// upstreamProvider gets every application from the IdP with a GetClient call, through the transport of the connector.
+type upstreamProvider struct {
+	fakeProvider
+
+	client *http.Client
+	url    string
+}
+
+func (p *upstreamProvider) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	req, err := http.NewRequestWithContext(connector.WithUpstreamOperation(ctx, "GetClient"), http.MethodGet, p.url+"/"+request.Id, nil)
+	Expect(err).NotTo(HaveOccurred())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return portalv1.GetOAuthApplication500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return portalv1.GetOAuthApplication500JSONResponse(connector.NewPortal500Error(resp.Status)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse{ClientId: request.Id}, nil
+}
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		calls  atomic.Int32
+		status atomic.Int32
+		idp    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		calls.Store(0)
+		status.Store(http.StatusServiceUnavailable)
+		idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(int(status.Load()))
+		}))
+		DeferCleanup(idp.Close)
+	})
+
+	Context("NewCircuitBreakerTransport", func() {
+		var (
+			breaker *connector.CircuitBreaker
+			client  *http.Client
+		)
+
+		get := func() error {
+			resp, err := client.Get(idp.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		}
+
+		BeforeEach(func() {
+			breaker = connector.NewCircuitBreaker("test", 2, 100*time.Millisecond)
+			client = &http.Client{Transport: connector.NewCircuitBreakerTransport(breaker, nil)}
+		})
+
+		It("opens after consecutive failed calls and fails calls without making them", func() {
+			Expect(get()).To(Succeed())
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+			Expect(get()).To(Succeed())
+			Expect(breaker.State()).To(Equal(connector.CircuitOpen))
+
+			err := get()
+			var openErr *connector.CircuitOpenError
+			Expect(errors.As(err, &openErr)).To(BeTrue())
+			Expect(openErr.Failures).To(Equal(2))
+			Expect(breaker.Check()).To(HaveOccurred())
+			Expect(calls.Load()).To(Equal(int32(2)))
+		})
+
+		It("does not count calls that succeed between failed calls", func() {
+			Expect(get()).To(Succeed())
+			status.Store(http.StatusNotFound)
+			Expect(get()).To(Succeed())
+			status.Store(http.StatusServiceUnavailable)
+			Expect(get()).To(Succeed())
+
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+		})
+
+		It("closes once a call succeeds after the open duration", func() {
+			Expect(get()).To(Succeed())
+			Expect(get()).To(Succeed())
+			Expect(get()).To(HaveOccurred())
+
+			status.Store(http.StatusOK)
+			Eventually(breaker.Check).Should(Succeed())
+			Expect(get()).To(Succeed())
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+			Expect(calls.Load()).To(Equal(int32(3)))
+		})
+
+		It("opens again if the call after the open duration fails", func() {
+			Expect(get()).To(Succeed())
+			Expect(get()).To(Succeed())
+
+			Eventually(breaker.Check).Should(Succeed())
+			Expect(get()).To(Succeed())
+			Expect(breaker.State()).To(Equal(connector.CircuitOpen))
+			Expect(get()).To(HaveOccurred())
+			Expect(calls.Load()).To(Equal(int32(3)))
+		})
+
+		It("never opens without a threshold", func() {
+			breaker = connector.NewCircuitBreaker("test", 0, time.Minute)
+			client = &http.Client{Transport: connector.NewCircuitBreakerTransport(breaker, nil)}
+
+			for range 10 {
+				Expect(get()).To(Succeed())
+			}
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+		})
+	})
+
+	Context("Call", func() {
+		var breaker *connector.CircuitBreaker
+
+		BeforeEach(func() {
+			breaker = connector.NewCircuitBreaker("test", 2, time.Minute)
+		})
+
+		// retried makes a call that fails with the status after the attempts, as a client that retries calls would.
+		retried := func(attempts int, status int) error {
+			return breaker.Call(context.Background(), func(context.Context) (int, error) {
+				for range attempts {
+					calls.Add(1)
+				}
+				return status, errors.New(http.StatusText(status))
+			})
+		}
+
+		It("counts a call once whatever the number of attempts", func() {
+			Expect(retried(3, http.StatusServiceUnavailable)).To(HaveOccurred())
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+			Expect(retried(3, http.StatusServiceUnavailable)).To(HaveOccurred())
+			Expect(breaker.State()).To(Equal(connector.CircuitOpen))
+
+			var openErr *connector.CircuitOpenError
+			Expect(errors.As(retried(3, http.StatusServiceUnavailable), &openErr)).To(BeTrue())
+			Expect(calls.Load()).To(Equal(int32(6)))
+		})
+
+		It("does not count calls that the IdP responded to with a client error", func() {
+			for range 3 {
+				Expect(retried(1, http.StatusTooManyRequests)).To(HaveOccurred())
+			}
+			Expect(breaker.State()).To(Equal(connector.CircuitClosed))
+		})
+	})
+
+	Context("ListenAndServe", func() {
+		var opts *connector.Options
+
+		get := func(path string) *http.Response {
+			resp, err := http.Get("http://127.0.0.1:" + opts.Port + path)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+			return resp
+		}
+
+		BeforeEach(func() {
+			opts = &connector.Options{
+				Port:                       freePort(),
+				MetricsPort:                freePort(),
+				ShutdownGracePeriod:        5 * time.Second,
+				CircuitBreakerThreshold:    1,
+				CircuitBreakerOpenDuration: time.Minute,
+			}
+			provider := &upstreamProvider{
+				client: &http.Client{Transport: opts.UpstreamTransport("circuit", nil)},
+				url:    idp.URL,
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+
+			go func() {
+				_ = connector.ListenAndServe(ctx, opts, provider)
+			}()
+
+			Eventually(func() error {
+				_, err := http.Get("http://127.0.0.1:" + opts.Port + "/healthz")
+				return err
+			}).Should(Succeed())
+		})
+
+		It("fails fast with service unavailable while the circuit is open, and stays ready", func() {
+			Expect(get("/applications/app").StatusCode).To(Equal(http.StatusInternalServerError))
+
+			resp := get("/applications/app")
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			var body portalv1.Error
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(body.Reason).To(ContainSubstring("the circuit IdP is unavailable"))
+			Expect(calls.Load()).To(Equal(int32(1)))
+
+			resp = get("/readyz")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			readiness, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(readiness)).To(HavePrefix("circuit breaker is open"))
+
+			metrics := scrape(opts.MetricsPort)
+			Expect(value(metrics, `idp_connect_upstream_circuit_state{connector="circuit"}`)).To(Equal(2.0))
+			Expect(value(metrics, `idp_connect_upstream_circuit_rejections_total{connector="circuit",operation="GetClient"}`)).To(Equal(1.0))
+		})
+	})
+})
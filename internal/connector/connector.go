@@ -0,0 +1,113 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// Provider is implemented by each IdP connector. It serves the IdP Connect API against its IdP, and is notified as the
+// server starts and stops.
+type Provider interface {
+	portalv1.StrictServerInterface
+
+	// Name returns the name of the IdP, such as "cognito".
+	Name() string
+
+	// Start is called before the server accepts any requests. An error prevents the server from starting.
+	Start(ctx context.Context) error
+
+	// Stop is called once the server has stopped accepting requests.
+	Stop(ctx context.Context) error
+
+	// Ready returns an error if the IdP cannot currently be used, such as when its credentials are rejected. It is
+	// called on each readiness probe.
+	Ready(ctx context.Context) error
+}
+
+// Options are the server options shared by all connectors. Each connector embeds them in its own options.
+type Options struct {
+	Port                string
+	MetricsPort         string
+	ShutdownGracePeriod time.Duration
+	ReadinessTimeout    time.Duration
+	JWTIssuer           string
+	JWTAudience         string
+	JWKSURL             string
+	JWTKeyFile          string
+	TLSCertFile         string
+	TLSKeyFile          string
+	ClientCAFile        string
+	ForceDelete         bool
+	LogFormat           string
+	LogLevel            string
+	OTLPEndpoint        string
+
+	UpstreamTimeout        time.Duration
+	UpstreamMaxAttempts    int
+	UpstreamRetryBaseDelay time.Duration
+	UpstreamRetryMaxDelay  time.Duration
+
+	CircuitBreakerThreshold    int
+	CircuitBreakerOpenDuration time.Duration
+
+	circuitBreaker *CircuitBreaker
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	flag.StringVar(&o.Port, "port", "8080", "Port for HTTP server")
+	flag.StringVar(&o.MetricsPort, "metrics-port", "9091", "Port for the Prometheus metrics server, or empty to disable it")
+	flag.DurationVar(&o.ReadinessTimeout, "readiness-timeout", 5*time.Second, "Time to wait for the IdP to respond to a readiness check")
+	flag.DurationVar(&o.ShutdownGracePeriod, "shutdown-grace-period", 20*time.Second, "Time to wait for in-flight requests to complete when shutting down")
+	flag.StringVar(&o.TLSCertFile, "tls-cert-file", "", "File with the certificate to serve the API over HTTPS, reloaded when it changes")
+	flag.StringVar(&o.TLSKeyFile, "tls-key-file", "", "File with the private key of the TLS certificate, reloaded when it changes")
+	flag.StringVar(&o.ClientCAFile, "client-ca-file", "", "File with the CA certificates that API clients must present a certificate from, reloaded when it changes")
+	flag.StringVar(&o.JWKSURL, "jwks-url", "", "URL of the JWKS used to verify the token header of API requests")
+	flag.StringVar(&o.JWTKeyFile, "jwt-key-file", "", "File with the JWK, JWKS or PEM public keys used to verify the token header of API requests")
+	flag.StringVar(&o.JWTIssuer, "jwt-issuer", "", "Issuer that tokens must be issued by, if tokens are verified")
+	flag.StringVar(&o.JWTAudience, "jwt-audience", "", "Audience that tokens must be issued for, if tokens are verified")
+	flag.BoolVar(&o.ForceDelete, "force-delete", false, "Delete clients that IdP Connect did not create, instead of refusing to. Their secrets and API products are never changed")
+	flag.StringVar(&o.LogFormat, "log-format", "text", "Format of log lines, json or text")
+	flag.StringVar(&o.LogLevel, "log-level", "info", "Lowest level of log lines that are written, debug, info, warn or error")
+	flag.StringVar(&o.OTLPEndpoint, "otlp-endpoint", "", "URL of the OTLP HTTP endpoint that traces are exported to, such as http://otel-collector:4318, or empty to use the OTEL_EXPORTER_OTLP environment variables")
+	flag.DurationVar(&o.UpstreamTimeout, "upstream-timeout", 10*time.Second, "Time that each call to the IdP may take before it is cancelled, or 0 for no timeout")
+	flag.IntVar(&o.UpstreamMaxAttempts, "upstream-max-attempts", 3, "Number of times a call to the IdP is made when it is rate limited or unavailable, including the first")
+	flag.DurationVar(&o.UpstreamRetryBaseDelay, "upstream-retry-base-delay", 250*time.Millisecond, "Delay before retrying a call to the IdP for the first time, doubled for each retry after it")
+	flag.DurationVar(&o.UpstreamRetryMaxDelay, "upstream-retry-max-delay", 10*time.Second, "Longest delay before retrying a call to the IdP, including when the IdP asks to wait")
+	flag.IntVar(&o.CircuitBreakerThreshold, "circuit-breaker-threshold", 5, "Number of consecutive failed calls to the IdP after which calls fail fast, or 0 to never fail fast")
+	flag.DurationVar(&o.CircuitBreakerOpenDuration, "circuit-breaker-open-duration", 30*time.Second, "Time that calls to the IdP fail fast for before a call is let through to find out whether it has recovered")
+}
+
+// RetryPolicy returns the policy that calls to the IdP are retried with.
+func (o *Options) RetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: o.UpstreamMaxAttempts,
+		BaseDelay:   o.UpstreamRetryBaseDelay,
+		MaxDelay:    o.UpstreamRetryMaxDelay,
+	}
+}
+
+// CircuitBreaker returns the circuit breaker around calls to the IdP of the connector, creating it the first time.
+func (o *Options) CircuitBreaker(connector string) *CircuitBreaker {
+	if o.circuitBreaker == nil {
+		o.circuitBreaker = NewCircuitBreaker(connector, o.CircuitBreakerThreshold, o.CircuitBreakerOpenDuration)
+	}
+
+	return o.circuitBreaker
+}
+
+// UpstreamTransport wraps the transport used by a connector to call its IdP, so that calls go through the circuit
+// breaker, are retried with the retry policy, are traced, have their metrics recorded and time out after the upstream
+// timeout.
+func (o *Options) UpstreamTransport(connector string, base http.RoundTripper) http.RoundTripper {
+	return NewCircuitBreakerTransport(
+		o.CircuitBreaker(connector),
+		NewRetryTransport(o.RetryPolicy(), NewTracingTransport(connector,
+			NewUpstreamTransport(connector, NewTimeoutTransport(o.UpstreamTimeout, base)),
+		)),
+	)
+}
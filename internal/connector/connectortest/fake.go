@@ -0,0 +1,43 @@
+// Package connectortest helps test connectors against fake IdPs.
+package connectortest
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// FakeIdP holds the clients of a fake IdP, whose API is served to a connector with httpmock responders. Responders
+// made with Responder hold its lock, so that they can change the clients of concurrent requests.
+type FakeIdP[C any] struct {
+	sync.Mutex
+
+	// Clients of the IdP by client ID.
+	Clients map[string]C
+
+	issued int
+}
+
+// NewFakeIdP returns a fake IdP without clients.
+func NewFakeIdP[C any]() *FakeIdP[C] {
+	return &FakeIdP[C]{Clients: map[string]C{}}
+}
+
+// Next returns a new value with the prefix, such as "client-1" or "secret-2", which is numbered after every value
+// issued before it. It must be called with the lock held.
+func (f *FakeIdP[C]) Next(prefix string) string {
+	f.issued++
+	return fmt.Sprintf("%s-%d", prefix, f.issued)
+}
+
+// Responder returns a responder that serves requests with serve while holding the lock.
+func (f *FakeIdP[C]) Responder(serve httpmock.Responder) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		f.Lock()
+		defer f.Unlock()
+
+		return serve(req)
+	}
+}
@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	livenessPath  = "/healthz"
+	readinessPath = "/readyz"
+)
+
+// isProbe reports whether the request is a liveness or readiness probe, which are not part of the API.
+func isProbe(c echo.Context) bool {
+	path := c.Request().URL.Path
+	return path == livenessPath || path == readinessPath
+}
+
+// registerProbes adds the liveness and readiness endpoints. The server is live as long as it responds, and ready when
+// the provider can use its IdP. It stays ready while the circuit breaker, if any, fails calls to the IdP, as restarting
+// or removing every pod of the connector would not make the IdP available sooner; the probe only reports the state.
+func registerProbes(e *echo.Echo, provider Provider, timeout time.Duration, breaker *CircuitBreaker) {
+	e.GET(livenessPath, func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	e.GET(readinessPath, func(c echo.Context) error {
+		if breaker != nil {
+			if err := breaker.Check(); err != nil {
+				return c.String(http.StatusOK, "circuit breaker is "+breaker.State().String()+": "+err.Error())
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+
+		if err := provider.Ready(ctx); err != nil {
+			slog.Warn("Connector is not ready", "connector", provider.Name(), "error", err)
+			return c.String(http.StatusServiceUnavailable, err.Error())
+		}
+
+		return c.String(http.StatusOK, "ok")
+	})
+}
@@ -0,0 +1,73 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+var _ = Describe("Probes", func() {
+	var (
+		provider *fakeProvider
+		opts     *connector.Options
+	)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get("http://127.0.0.1:" + opts.Port + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return resp.StatusCode, string(body)
+	}
+
+	BeforeEach(func() {
+		provider = &fakeProvider{
+			received: make(chan struct{}),
+			release:  make(chan struct{}),
+		}
+		opts = &connector.Options{
+			Port:                freePort(),
+			ReadinessTimeout:    time.Second,
+			ShutdownGracePeriod: 5 * time.Second,
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		go func() {
+			_ = connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			_, err := http.Get("http://127.0.0.1:" + opts.Port + "/healthz")
+			return err
+		}).Should(Succeed())
+	})
+
+	It("is live and ready when the provider is ready", func() {
+		code, _ := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+	})
+
+	It("is live but not ready when the provider cannot use its IdP", func() {
+		provider.notReady = errors.New("invalid credentials")
+
+		code, _ := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+
+		code, body := get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(body).To(ContainSubstring("invalid credentials"))
+	})
+})
@@ -0,0 +1,216 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/trace"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// Keys of the echo context values that the request log line is given by the API handlers.
+const (
+	operationKey = "operation"
+	reasonKey    = "reason"
+)
+
+// ConfigureLogging makes the logger with the log format and level the default logger, which the log package also
+// writes to. It is called before the connector starts, so that every log line is written with it.
+func (o *Options) ConfigureLogging() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(o.LogLevel)); err != nil {
+		return eris.Wrapf(err, "invalid log level %s", o.LogLevel)
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(o.LogFormat) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, handlerOpts)
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, handlerOpts)
+	default:
+		return eris.Errorf("invalid log format %s, it must be json or text", o.LogFormat)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+type requestIdKey struct{}
+
+// WithRequestID returns a context for serving the API request with the ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestID returns the ID of the API request that the context is serving, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// Logger returns the default logger, with the ID of the API request that the context is serving and of its trace if
+// there are ones.
+func Logger(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		logger = logger.With("request_id", id)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		logger = logger.With("trace_id", span.TraceID().String())
+	}
+
+	return logger
+}
+
+// requestID returns middleware that gives each API request the ID in its X-Request-Id header, or a new one if it has
+// none. The ID is set on the response and on the request context.
+func requestID() echo.MiddlewareFunc {
+	return echomiddleware.RequestIDWithConfig(echomiddleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			c.SetRequest(c.Request().WithContext(WithRequestID(c.Request().Context(), id)))
+		},
+	})
+}
+
+// requestLogger returns middleware that logs each request once it has been served, along with the reason of its error
+// response if it has one. Probes are only logged at debug level.
+func requestLogger() echo.MiddlewareFunc {
+	return echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+		HandleError:  true,
+		LogMethod:    true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogRequestID: true,
+		LogError:     true,
+		LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case isProbe(c):
+				level = slog.LevelDebug
+			case v.Status >= 500:
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("request_id", v.RequestID),
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if span := trace.SpanContextFromContext(c.Request().Context()); span.HasTraceID() {
+				attrs = append(attrs, slog.String("trace_id", span.TraceID().String()))
+			}
+			if operation, ok := c.Get(operationKey).(string); ok {
+				attrs = append(attrs, slog.String("operation", operation))
+			}
+			if reason, ok := c.Get(reasonKey).(string); ok {
+				attrs = append(attrs, slog.String("reason", reason))
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			slog.LogAttrs(c.Request().Context(), level, "Served request", attrs...)
+			return nil
+		},
+	})
+}
+
+var errorType = reflect.TypeFor[portalv1.Error]()
+
+// withRequestID is strict middleware that adds the ID of the request to the reason of error responses, so that Portal
+// can report errors with it and they can be found in the logs. The reason is also logged with the request.
+func withRequestID(f portalv1.StrictHandlerFunc, operationID string) portalv1.StrictHandlerFunc {
+	return func(ctx echo.Context, request interface{}) (interface{}, error) {
+		ctx.Set(operationKey, operationID)
+
+		response, err := f(ctx, request)
+		if err != nil {
+			return response, err
+		}
+
+		// Every error response of the API is an Error.
+		v := reflect.ValueOf(response)
+		if !v.IsValid() || !v.Type().ConvertibleTo(errorType) {
+			return response, nil
+		}
+
+		portalErr := errorWithRequestID(ctx, v.Convert(errorType).Interface().(portalv1.Error))
+		return reflect.ValueOf(portalErr).Convert(v.Type()).Interface(), nil
+	}
+}
+
+// errorWithRequestID adds the ID of the request to the reason of an error response, and logs the reason with the
+// request. It is used for every error response of the API, including those of middleware that rejects requests before
+// they reach the handler.
+func errorWithRequestID(c echo.Context, portalErr portalv1.Error) portalv1.Error {
+	c.Set(reasonKey, portalErr.Reason)
+	if id := RequestID(c.Request().Context()); id != "" {
+		portalErr.Reason = fmt.Sprintf("%s (request ID %s)", portalErr.Reason, id)
+	}
+	return portalErr
+}
+
+// respondWithError responds to a request that middleware rejected with the error.
+func respondWithError(c echo.Context, portalErr portalv1.Error) error {
+	return c.JSON(portalErr.Code, errorWithRequestID(c, portalErr))
+}
+
+// maxLoggedBody is the most of the body of an error response from the IdP that is logged.
+const maxLoggedBody = 4096
+
+// logUpstreamError logs a call to the IdP that failed or responded with an error, along with the body of the error
+// response, which connectors do not always pass on. Responses for clients that are not found or already exist are
+// expected while looking clients up, so are only logged at debug level.
+func logUpstreamError(req *http.Request, resp *http.Response, err error, connector, operation string) {
+	ctx := req.Context()
+	logger := Logger(ctx)
+
+	level := slog.LevelWarn
+	if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict) {
+		level = slog.LevelDebug
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("connector", connector),
+		slog.String("operation", operation),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+
+		// Put back what was read of the body, for the connector to read it.
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBody))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		attrs = append(attrs, slog.String("body", string(body)))
+	}
+
+	logger.LogAttrs(ctx, level, "IdP call failed", attrs...)
+}
@@ -0,0 +1,159 @@
+package connector_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// logBuffer collects the log lines written by servers that may still be serving other specs.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// line returns the first log line with the message.
+func (b *logBuffer) line(msg string) map[string]interface{} {
+	for _, l := range strings.Split(b.String(), "\n") {
+		var line map[string]interface{}
+		if json.Unmarshal([]byte(l), &line) == nil && line["msg"] == msg {
+			return line
+		}
+	}
+
+	return nil
+}
+
+var _ = Describe("Logging", func() {
+	var logs *logBuffer
+
+	BeforeEach(func() {
+		logs = &logBuffer{}
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		DeferCleanup(slog.SetDefault, previous)
+	})
+
+	It("rejects an unknown log format or level", func() {
+		Expect((&connector.Options{LogFormat: "xml", LogLevel: "info"}).ConfigureLogging()).To(MatchError(ContainSubstring("invalid log format")))
+		Expect((&connector.Options{LogFormat: "json", LogLevel: "verbose"}).ConfigureLogging()).To(MatchError(ContainSubstring("invalid log level")))
+	})
+
+	Context("ListenAndServe", func() {
+		var opts *connector.Options
+
+		get := func(path, requestId string) *http.Response {
+			req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:"+opts.Port+path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			if requestId != "" {
+				req.Header.Set("X-Request-Id", requestId)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+			return resp
+		}
+
+		BeforeEach(func() {
+			opts = &connector.Options{
+				Port:                freePort(),
+				ShutdownGracePeriod: 5 * time.Second,
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+
+			go func() {
+				_ = connector.ListenAndServe(ctx, opts, &upstreamErrorProvider{})
+			}()
+
+			Eventually(func() error {
+				_, err := http.Get("http://127.0.0.1:" + opts.Port + "/healthz")
+				return err
+			}).Should(Succeed())
+		})
+
+		It("gives the reason of error responses and the request log the request ID", func() {
+			resp := get("/applications/500", "portal-request-1")
+			Expect(resp.Header.Get("X-Request-Id")).To(Equal("portal-request-1"))
+
+			var body portalv1.Error
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Reason).To(Equal("the IdP failed (request ID portal-request-1)"))
+
+			Eventually(logs.String).Should(ContainSubstring(`"request_id":"portal-request-1"`))
+			Expect(logs.String()).To(ContainSubstring(`"operation":"GetOAuthApplication"`))
+			Expect(logs.String()).To(ContainSubstring(`"reason":"the IdP failed"`))
+		})
+
+		It("generates a request ID for requests without one", func() {
+			resp := get("/applications/500", "")
+			Expect(resp.Header.Get("X-Request-Id")).NotTo(BeEmpty())
+
+			var body portalv1.Error
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Reason).To(HaveSuffix("(request ID " + resp.Header.Get("X-Request-Id") + ")"))
+		})
+
+		It("gives the reason of requests that fail validation the request ID", func() {
+			resp := get("/applications?limit=0", "portal-request-3")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			var body portalv1.Error
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Code).To(Equal(http.StatusBadRequest))
+			Expect(body.Reason).To(HaveSuffix("(request ID portal-request-3)"))
+		})
+	})
+
+	It("logs the details of errors from the IdP without consuming them", func() {
+		idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"insufficient_scope"}`))
+		}))
+		DeferCleanup(idp.Close)
+
+		client := &http.Client{Transport: connector.NewUpstreamTransport("test", nil)}
+		ctx := connector.WithUpstreamOperation(connector.WithRequestID(context.Background(), "portal-request-2"), "CreateClient")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, idp.URL+"/clients", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"error":"insufficient_scope"}`))
+
+		line := logs.line("IdP call failed")
+		Expect(line).To(HaveKeyWithValue("level", "WARN"))
+		Expect(line).To(HaveKeyWithValue("request_id", "portal-request-2"))
+		Expect(line).To(HaveKeyWithValue("operation", "CreateClient"))
+		Expect(line).To(HaveKeyWithValue("status", 403.0))
+		Expect(line).To(HaveKeyWithValue("body", `{"error":"insufficient_scope"}`))
+	})
+})
@@ -0,0 +1,152 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// unknownOperation labels requests that do not match an API operation, and upstream calls that were not given one.
+const unknownOperation = "unknown"
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "idp_connect_requests_total",
+		Help: "Number of IdP Connect API requests by operation and status code.",
+	}, []string{"operation", "code"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "idp_connect_request_duration_seconds",
+		Help:    "Latency of IdP Connect API requests by operation and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "code"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "idp_connect_upstream_request_duration_seconds",
+		Help:    "Latency of calls to the IdP by connector and upstream operation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"connector", "operation"})
+
+	upstreamErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "idp_connect_upstream_errors_total",
+		Help: "Number of calls to the IdP that failed or returned an error status, by connector and upstream operation.",
+	}, []string{"connector", "operation", "code"})
+
+	upstreamCircuitState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "idp_connect_upstream_circuit_state",
+		Help: "State of the circuit breaker around calls to the IdP by connector: 0 closed, 1 half-open, 2 open.",
+	}, []string{"connector"})
+
+	upstreamCircuitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "idp_connect_upstream_circuit_rejections_total",
+		Help: "Number of calls to the IdP that the circuit breaker failed without making them, by connector and upstream operation.",
+	}, []string{"connector", "operation"})
+)
+
+// requestMetrics returns middleware that records the count and latency of each API request, labelled by the operation
+// ID of the matched route in the swagger spec.
+func requestMetrics(swagger *openapi3.T) echo.MiddlewareFunc {
+	operations := operationIDs(swagger)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if isProbe(c) {
+				return next(c)
+			}
+
+			start := time.Now()
+
+			// Handle the error here, so that the response status is known.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			operation, ok := operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				operation = unknownOperation
+			}
+			code := strconv.Itoa(c.Response().Status)
+
+			requestsTotal.WithLabelValues(operation, code).Inc()
+			requestDuration.WithLabelValues(operation, code).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
+
+// operationIDs returns the operation IDs in the swagger spec by the method and echo route of their operation.
+func operationIDs(swagger *openapi3.T) map[string]string {
+	operations := map[string]string{}
+	for path, item := range swagger.Paths.Map() {
+		// Echo routes use :param rather than {param}.
+		route := strings.NewReplacer("{", ":", "}", "").Replace(path)
+		for method, op := range item.Operations() {
+			operations[method+" "+route] = op.OperationID
+		}
+	}
+
+	return operations
+}
+
+type upstreamOperationKey struct{}
+
+// WithUpstreamOperation returns a context that labels the calls made to the IdP with it by the given operation name.
+func WithUpstreamOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, upstreamOperationKey{}, operation)
+}
+
+// UpstreamOperation returns the upstream operation name set on the context, if any.
+func UpstreamOperation(ctx context.Context) string {
+	if operation, ok := ctx.Value(upstreamOperationKey{}).(string); ok {
+		return operation
+	}
+
+	return unknownOperation
+}
+
+// upstreamTransport records metrics for each call that a connector makes to its IdP, and logs the calls that fail.
+type upstreamTransport struct {
+	connector string
+	base      http.RoundTripper
+}
+
+// NewUpstreamTransport wraps the transport used by a connector to call its IdP, so that the latency and errors of
+// each call are recorded, and the details of errors are logged. Calls are labelled by the operation set with
+// WithUpstreamOperation on the request context.
+func NewUpstreamTransport(connector string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &upstreamTransport{
+		connector: connector,
+		base:      base,
+	}
+}
+
+func (t *upstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := UpstreamOperation(req.Context())
+	start := time.Now()
+
+	resp, err := t.base.RoundTrip(req)
+
+	upstreamRequestDuration.WithLabelValues(t.connector, operation).Observe(time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		upstreamErrorsTotal.WithLabelValues(t.connector, operation, "error").Inc()
+		logUpstreamError(req, nil, err, t.connector, operation)
+	case resp.StatusCode >= 400:
+		upstreamErrorsTotal.WithLabelValues(t.connector, operation, strconv.Itoa(resp.StatusCode)).Inc()
+		logUpstreamError(req, resp, nil, t.connector, operation)
+	}
+
+	return resp, err
+}
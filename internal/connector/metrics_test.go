@@ -0,0 +1,121 @@
+package connector_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+// scrape returns the metrics served on the given port in the Prometheus text format.
+func scrape(port string) string {
+	resp, err := http.Get("http://127.0.0.1:" + port + "/metrics")
+	Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).NotTo(HaveOccurred())
+	return string(body)
+}
+
+// value returns the value of the series in the scraped metrics, or 0 if it has not been recorded yet.
+func value(metrics, series string) float64 {
+	for _, line := range strings.Split(metrics, "\n") {
+		if v, ok := strings.CutPrefix(line, series+" "); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			Expect(err).NotTo(HaveOccurred())
+			return f
+		}
+	}
+
+	return 0
+}
+
+var _ = Describe("Metrics", func() {
+	var opts *connector.Options
+
+	BeforeEach(func() {
+		provider := &fakeProvider{
+			received: make(chan struct{}),
+			release:  make(chan struct{}),
+		}
+		close(provider.release)
+
+		opts = &connector.Options{
+			Port:                freePort(),
+			MetricsPort:         freePort(),
+			ShutdownGracePeriod: 5 * time.Second,
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		go func() {
+			_ = connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			_, err := http.Get("http://127.0.0.1:" + opts.MetricsPort + "/metrics")
+			return err
+		}).Should(Succeed())
+	})
+
+	It("records requests by operation and status code", func() {
+		series := []string{
+			`idp_connect_requests_total{code="200",operation="ListOAuthApplications"}`,
+			`idp_connect_requests_total{code="400",operation="ListOAuthApplications"}`,
+			`idp_connect_requests_total{code="404",operation="unknown"}`,
+			`idp_connect_request_duration_seconds_count{code="200",operation="ListOAuthApplications"}`,
+		}
+		before := scrape(opts.MetricsPort)
+
+		resp, err := http.Get("http://127.0.0.1:" + opts.Port + "/applications")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp, err = http.Get("http://127.0.0.1:" + opts.Port + "/applications?limit=0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp, err = http.Get("http://127.0.0.1:" + opts.Port + "/unknown")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+		after := scrape(opts.MetricsPort)
+		for _, s := range series {
+			Expect(value(after, s)-value(before, s)).To(Equal(1.0), s)
+		}
+	})
+
+	It("records upstream calls by connector and operation", func() {
+		idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(idp.Close)
+
+		client := &http.Client{Transport: connector.NewUpstreamTransport("test", nil)}
+		get := func(operation, path string) {
+			ctx := connector.WithUpstreamOperation(context.Background(), operation)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, idp.URL+path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		}
+		get("GetClient", "/found")
+		get("GetClient", "/missing")
+
+		metrics := scrape(opts.MetricsPort)
+		Expect(metrics).To(ContainSubstring(`idp_connect_upstream_request_duration_seconds_count{connector="test",operation="GetClient"} 2`))
+		Expect(metrics).To(ContainSubstring(`idp_connect_upstream_errors_total{code="404",connector="test",operation="GetClient"} 1`))
+	})
+})
@@ -0,0 +1,60 @@
+package connector
+
+import (
+	"time"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// ManagedBy marks a client in an IdP as created by IdP Connect.
+const ManagedBy = "gloo-portal-idp-connect"
+
+// Keys of the ownership metadata, for IdPs that keep metadata of a client as key/value pairs.
+const (
+	ManagedByKey = "managed_by"
+	PortalIdKey  = "portal_id"
+	CreatedAtKey = "created_at"
+)
+
+// Ownership is the metadata that connectors stamp on the clients they create, so that they can be told apart from
+// clients that IdP Connect does not own.
+type Ownership struct {
+	// PortalId is the id that Portal created the application with.
+	PortalId string
+
+	// CreatedAt is when IdP Connect created the client.
+	CreatedAt time.Time
+}
+
+// NewOwnership returns the ownership of a client that is created now for the Portal application.
+func NewOwnership(portalId string) Ownership {
+	return Ownership{
+		PortalId:  portalId,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Metadata returns the ownership as key/value pairs.
+func (o Ownership) Metadata() map[string]string {
+	return map[string]string{
+		ManagedByKey: ManagedBy,
+		PortalIdKey:  o.PortalId,
+		CreatedAtKey: o.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// IsOwned reports whether the metadata of a client marks it as created by IdP Connect.
+func IsOwned(metadata map[string]string) bool {
+	return metadata[ManagedByKey] == ManagedBy
+}
+
+// NewNotOwnedError is the error of deleting a client that IdP Connect did not create, without --force-delete.
+func NewNotOwnedError(id string) portalv1.Error {
+	return NewPortal403Error("the client of application [" + id + "] was not created by IdP Connect, so it is only deleted with --force-delete")
+}
+
+// NewNotOwnedChangeError is the error of rotating the secret of, or granting or revoking API products for, a client
+// that IdP Connect did not create. Unlike deletes, these are refused even with --force-delete.
+func NewNotOwnedChangeError(id string) portalv1.Error {
+	return NewPortal403Error("the client of application [" + id + "] was not created by IdP Connect, so it is not changed")
+}
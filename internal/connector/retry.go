@@ -0,0 +1,266 @@
+package connector
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// RetryPolicy is how calls to the IdP are retried when it is rate limiting them or is briefly unavailable.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a call is made, including the first. Calls are not retried if it is 1 or less.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, which doubles with each retry after it.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay before a retry. Calls are not retried if the IdP asks to wait longer than this.
+	MaxDelay time.Duration
+}
+
+// Backoff returns the delay before the given retry, counting from 1. It is exponential with full jitter, so that
+// connectors that were rate limited at the same time do not retry at the same time.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.MaxDelay
+	if retry < 32 {
+		if exp := p.BaseDelay << (retry - 1); exp > 0 && exp < delay {
+			delay = exp
+		}
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay + 1)
+}
+
+// Delay returns how long to wait before the given retry of a call, counting from 1, and false if the call is not to be
+// retried. The delay that the response asks for is honored, rather than backing off.
+func (p RetryPolicy) Delay(retry int, resp *http.Response) (time.Duration, bool) {
+	if retry >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if delay, ok := RetryAfter(resp); ok {
+		return delay, delay <= p.MaxDelay
+	}
+
+	return p.Backoff(retry), true
+}
+
+// RetryAfter returns how long a response asks the caller to wait before retrying, from its Retry-After header or the
+// X-Rate-Limit-Reset header that Okta sets on rate limited responses, and whether it asks at all.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	now := time.Now()
+	// The reset time is by the IdP's clock, so it is compared with the time of the response if there is one.
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		now = date
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return max(time.Duration(seconds)*time.Second, 0), true
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+
+	if reset := resp.Header.Get("X-Rate-Limit-Reset"); reset != "" {
+		if epoch, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			return max(time.Unix(epoch, 0).Sub(now), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+// retryTransport retries calls to the IdP that were rate limited or failed while it was briefly unavailable.
+type retryTransport struct {
+	policy RetryPolicy
+	base   http.RoundTripper
+}
+
+// NewRetryTransport wraps the transport used by a connector to call its IdP, so that calls are retried with the
+// policy. Calls that were rate limited (429) or found the IdP unavailable (503) are always retried, as the IdP did not
+// act on them. Calls that failed in other ways that may be temporary are only retried if they are idempotent. Once
+// the attempts are used up, the last response is returned.
+func NewRetryTransport(policy RetryPolicy, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &retryTransport{
+		policy: policy,
+		base:   base,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 1; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if !retryable(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay, ok := t.policy.Delay(retry, resp)
+		if !ok {
+			return resp, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		req = next
+	}
+}
+
+// retryable reports whether a call may succeed if it is made again.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && idempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// passUpstreamStatus is strict middleware that responds with 429 or 503 when a connector responds with an error because
+// the IdP was still rate limiting calls, or was still unavailable, once they had been retried, or because the circuit
+// breaker failed a call. Connectors report these as 500 errors with the status code of the IdP, so that each does not
+// have to handle them.
+func passUpstreamStatus(f portalv1.StrictHandlerFunc, _ string) portalv1.StrictHandlerFunc {
+	return func(ctx echo.Context, request interface{}) (interface{}, error) {
+		reqCtx, rejection := withCircuitRejection(ctx.Request().Context())
+		ctx.SetRequest(ctx.Request().WithContext(reqCtx))
+
+		response, err := f(ctx, request)
+		if err != nil {
+			return response, err
+		}
+
+		return upstreamStatusResponse(response, rejection.get()), nil
+	}
+}
+
+// upstreamStatusResponse returns the 429 or 503 response of the operation for a 500 response with that error code, or
+// else the response itself. A 500 response is a 503 response if the circuit breaker failed a call.
+func upstreamStatusResponse(response interface{}, open *CircuitOpenError) interface{} {
+	switch r := response.(type) {
+	case portalv1.CreateOAuthApplication500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.CreateOAuthApplication429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.CreateOAuthApplication503JSONResponse(e)
+		}
+	case portalv1.DeleteOAuthApplication500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.DeleteOAuthApplication429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.DeleteOAuthApplication503JSONResponse(e)
+		}
+	case portalv1.ListOAuthApplications500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.ListOAuthApplications429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.ListOAuthApplications503JSONResponse(e)
+		}
+	case portalv1.GetOAuthApplication500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.GetOAuthApplication429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.GetOAuthApplication503JSONResponse(e)
+		}
+	case portalv1.RotateOAuthApplicationSecret500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.RotateOAuthApplicationSecret429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.RotateOAuthApplicationSecret503JSONResponse(e)
+		}
+	case portalv1.GrantAPIProductAccess500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.GrantAPIProductAccess429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.GrantAPIProductAccess503JSONResponse(e)
+		}
+	case portalv1.RevokeAPIProductAccess500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.RevokeAPIProductAccess429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.RevokeAPIProductAccess503JSONResponse(e)
+		}
+	case portalv1.CreateAPIProduct500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.CreateAPIProduct429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.CreateAPIProduct503JSONResponse(e)
+		}
+	case portalv1.DeleteAPIProduct500JSONResponse:
+		switch e := upstreamError(portalv1.Error(r), open); e.Code {
+		case http.StatusTooManyRequests:
+			return portalv1.DeleteAPIProduct429JSONResponse(e)
+		case http.StatusServiceUnavailable:
+			return portalv1.DeleteAPIProduct503JSONResponse(e)
+		}
+	}
+
+	return response
+}
+
+// upstreamError returns the error of a call that the circuit breaker failed, if it did, or else the error itself.
+func upstreamError(err portalv1.Error, open *CircuitOpenError) portalv1.Error {
+	if open == nil {
+		return err
+	}
+
+	return NewPortalError(http.StatusServiceUnavailable, "Service Unavailable", open.Error())
+}
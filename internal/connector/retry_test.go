@@ -0,0 +1,229 @@
+package connector_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// upstreamErrorProvider fails every application get with the error of the IdP that is in the id.
+type upstreamErrorProvider struct {
+	fakeProvider
+}
+
+func (p *upstreamErrorProvider) GetOAuthApplication(
+	_ context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	code, err := strconv.Atoi(request.Id)
+	Expect(err).NotTo(HaveOccurred())
+	return portalv1.GetOAuthApplication500JSONResponse(connector.NewPortalError(code, http.StatusText(code), "the IdP failed")), nil
+}
+
+var _ = Describe("Retries", func() {
+	policy := connector.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+
+	Context("RetryAfter", func() {
+		It("reads the delay in seconds", func() {
+			resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+
+			delay, ok := connector.RetryAfter(resp)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(2 * time.Second))
+		})
+
+		It("reads the time to retry at, by the clock of the IdP", func() {
+			date := time.Now().Add(-time.Hour).UTC()
+			resp := &http.Response{Header: http.Header{
+				"Date":        {date.Format(http.TimeFormat)},
+				"Retry-After": {date.Add(3 * time.Second).Format(http.TimeFormat)},
+			}}
+
+			delay, ok := connector.RetryAfter(resp)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(3 * time.Second))
+		})
+
+		It("reads the time that the Okta rate limit resets", func() {
+			date := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+			resp := &http.Response{Header: http.Header{
+				"Date":               {date.Format(http.TimeFormat)},
+				"X-Rate-Limit-Reset": {strconv.FormatInt(date.Add(5*time.Second).Unix(), 10)},
+			}}
+
+			delay, ok := connector.RetryAfter(resp)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(5 * time.Second))
+		})
+
+		It("does not ask to wait without the headers", func() {
+			_, ok := connector.RetryAfter(&http.Response{Header: http.Header{}})
+			Expect(ok).To(BeFalse())
+
+			_, ok = connector.RetryAfter(nil)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("RetryPolicy", func() {
+		It("backs off exponentially, up to the max delay", func() {
+			backoff := connector.RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+			for range 100 {
+				Expect(backoff.Backoff(1)).To(BeNumerically("<=", time.Second))
+				Expect(backoff.Backoff(2)).To(BeNumerically("<=", 2*time.Second))
+				Expect(backoff.Backoff(100)).To(BeNumerically("<=", 5*time.Second))
+			}
+		})
+
+		It("stops retrying once the attempts are used up", func() {
+			_, ok := policy.Delay(2, nil)
+			Expect(ok).To(BeTrue())
+
+			_, ok = policy.Delay(3, nil)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("does not retry if the IdP asks to wait longer than the max delay", func() {
+			_, ok := policy.Delay(1, &http.Response{Header: http.Header{"Retry-After": {"60"}}})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("NewRetryTransport", func() {
+		var (
+			calls  atomic.Int32
+			bodies chan string
+			status func(call int32) int
+			idp    *httptest.Server
+			client *http.Client
+		)
+
+		BeforeEach(func() {
+			calls.Store(0)
+			bodies = make(chan string, 10)
+			idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies <- string(body)
+				w.WriteHeader(status(calls.Add(1)))
+			}))
+			DeferCleanup(idp.Close)
+			client = &http.Client{Transport: connector.NewRetryTransport(policy, nil)}
+		})
+
+		It("retries a call that was rate limited, with its body", func() {
+			status = func(call int32) int {
+				if call < 3 {
+					return http.StatusTooManyRequests
+				}
+				return http.StatusCreated
+			}
+
+			resp, err := client.Post(idp.URL, "application/json", strings.NewReader(`{"name":"app"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(calls.Load()).To(Equal(int32(3)))
+			for range 3 {
+				Expect(<-bodies).To(Equal(`{"name":"app"}`))
+			}
+		})
+
+		It("returns the last response once the attempts are used up", func() {
+			status = func(_ int32) int {
+				return http.StatusServiceUnavailable
+			}
+
+			resp, err := client.Get(idp.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(calls.Load()).To(Equal(int32(3)))
+		})
+
+		It("only retries a bad gateway if the call is idempotent", func() {
+			status = func(_ int32) int {
+				return http.StatusBadGateway
+			}
+
+			resp, err := client.Post(idp.URL, "application/json", strings.NewReader(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+			Expect(calls.Load()).To(Equal(int32(1)))
+
+			resp, err = client.Get(idp.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+			Expect(calls.Load()).To(Equal(int32(4)))
+		})
+
+		It("does not retry other errors", func() {
+			status = func(_ int32) int {
+				return http.StatusBadRequest
+			}
+
+			resp, err := client.Get(idp.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(calls.Load()).To(Equal(int32(1)))
+		})
+	})
+
+	Context("ListenAndServe", func() {
+		var opts *connector.Options
+
+		BeforeEach(func() {
+			opts = &connector.Options{
+				Port:                freePort(),
+				ShutdownGracePeriod: 5 * time.Second,
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+
+			go func() {
+				_ = connector.ListenAndServe(ctx, opts, &upstreamErrorProvider{})
+			}()
+
+			Eventually(func() error {
+				_, err := http.Get("http://127.0.0.1:" + opts.Port + "/readyz")
+				return err
+			}).Should(Succeed())
+		})
+
+		DescribeTable("passes on that the IdP is rate limiting or unavailable",
+			func(code int) {
+				resp, err := http.Get("http://127.0.0.1:" + opts.Port + "/applications/" + strconv.Itoa(code))
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(code))
+
+				var body portalv1.Error
+				Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+				Expect(body.Code).To(Equal(code))
+				Expect(body.Reason).To(HavePrefix("the IdP failed (request ID "))
+			},
+			Entry("rate limiting", http.StatusTooManyRequests),
+			Entry("unavailable", http.StatusServiceUnavailable),
+		)
+
+		It("responds with other errors of the IdP as internal errors", func() {
+			resp, err := http.Get("http://127.0.0.1:" + opts.Port + "/applications/502")
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	middleware "github.com/oapi-codegen/echo-middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rotisserie/eris"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// ListenAndServe serves the IdP Connect API using the provider, along with its probes and metrics, until a server fails
+// or the context is cancelled. On cancellation, the servers stop accepting requests and wait up to the shutdown grace
+// period for in-flight requests to complete. Requests are not cancelled along with the context, so that multi-step IdP
+// operations are not left half-done.
+func ListenAndServe(ctx context.Context, opts *Options, provider Provider) error {
+	swagger, err := portalv1.GetSwagger()
+	if err != nil {
+		return eris.Wrap(err, "could not load swagger spec")
+	}
+
+	// Clear out the servers array in the swagger spec, that skips validating
+	// that server names match. We don't know how this thing will be run.
+	swagger.Servers = nil
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return eris.Wrap(err, "could not configure TLS")
+	}
+
+	verifier, err := newTokenVerifier(ctx, opts)
+	if err != nil {
+		return eris.Wrap(err, "could not configure token verification")
+	}
+	if verifier == nil {
+		slog.Warn("Tokens are not verified, any caller can manage clients", "connector", provider.Name())
+	}
+
+	shutdownTracing, err := configureTracing(ctx, opts, provider.Name())
+	if err != nil {
+		return eris.Wrap(err, "could not configure tracing")
+	}
+	defer func() {
+		// Spans that cannot be exported within the grace period are dropped, so that an unreachable collector does
+		// not stop the connector from exiting.
+		exportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.ShutdownGracePeriod)
+		defer cancel()
+
+		if err := shutdownTracing(exportCtx); err != nil {
+			slog.Error("Could not export traces", "connector", provider.Name(), "error", err)
+		}
+	}()
+
+	// Pass on that the IdP is rate limiting or unavailable, rather than reporting it as an error of IdP Connect, and
+	// give the reason of every error response the request ID
+	portalHandler := portalv1.NewStrictHandler(provider, []portalv1.StrictMiddlewareFunc{passUpstreamStatus, withRequestID})
+
+	e := echo.New()
+	// Give each request an ID, and log all requests with it
+	e.Use(requestID())
+	e.Use(requestLogger())
+	// Trace all API requests, continuing the traces of Portal
+	e.Use(requestTracing(swagger))
+	// Record metrics for all API requests, including those rejected by validation
+	e.Use(requestMetrics(swagger))
+	// Reject callers without a client certificate or a valid token before looking at their requests
+	if opts.ClientCAFile != "" {
+		e.Use(requireClientCert)
+	}
+	if verifier != nil {
+		e.Use(verifier.middleware())
+	}
+	// Use our validation middleware to check all requests against the
+	// OpenAPI schema. Probes are not part of the API. Requests it rejects are
+	// answered with an Error, like any other error response of the API.
+	e.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
+		Options: openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+		Skipper: isProbe,
+		ErrorHandler: func(c echo.Context, err *echo.HTTPError) error {
+			return respondWithError(c, NewPortalError(err.Code, http.StatusText(err.Code), fmt.Sprint(err.Message)))
+		},
+	}))
+
+	registerProbes(e, provider, opts.ReadinessTimeout, opts.circuitBreaker)
+
+	// We now register the provider as the handler for the interface
+	portalv1.RegisterHandlers(e, portalHandler)
+
+	if err := provider.Start(ctx); err != nil {
+		return eris.Wrapf(err, "could not start %s connector", provider.Name())
+	}
+
+	servers := map[string]*http.Server{
+		"API": {
+			Handler:   e,
+			Addr:      net.JoinHostPort("0.0.0.0", opts.Port),
+			TLSConfig: tlsConfig,
+		},
+	}
+	if opts.MetricsPort != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", promhttp.Handler())
+		servers["metrics"] = &http.Server{
+			Handler: metricsMux,
+			Addr:    net.JoinHostPort("0.0.0.0", opts.MetricsPort),
+		}
+	}
+
+	serveErr := make(chan error, len(servers))
+	for name, s := range servers {
+		go func() {
+			slog.Info("Starting server", "connector", provider.Name(), "server", name, "address", s.Addr)
+			if s.TLSConfig != nil {
+				// The certificate is provided by the TLS config, so that it can be reloaded.
+				serveErr <- s.ListenAndServeTLS("", "")
+			} else {
+				serveErr <- s.ListenAndServe()
+			}
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		for _, s := range servers {
+			_ = s.Close()
+		}
+		if stopErr := provider.Stop(context.WithoutCancel(ctx)); stopErr != nil {
+			slog.Error("Could not stop connector", "connector", provider.Name(), "error", stopErr)
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down servers, waiting for in-flight requests", "connector", provider.Name(), "grace_period", opts.ShutdownGracePeriod)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.ShutdownGracePeriod)
+	defer cancel()
+
+	var shutdownErr error
+	for _, s := range servers {
+		shutdownErr = errors.Join(shutdownErr, s.Shutdown(shutdownCtx))
+	}
+	for range servers {
+		if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+			shutdownErr = errors.Join(shutdownErr, err)
+		}
+	}
+
+	if err := provider.Stop(shutdownCtx); err != nil {
+		shutdownErr = errors.Join(shutdownErr, eris.Wrapf(err, "could not stop %s connector", provider.Name()))
+	}
+
+	if shutdownErr != nil {
+		return eris.Wrap(shutdownErr, "servers did not shut down cleanly")
+	}
+
+	slog.Info("Stopped servers", "connector", provider.Name())
+	return nil
+}
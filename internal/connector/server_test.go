@@ -0,0 +1,134 @@
+package connector_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// fakeProvider serves every application list request once release is closed.
+type fakeProvider struct {
+	portalv1.StrictServerInterface
+
+	received chan struct{}
+	release  chan struct{}
+	stopped  bool
+	notReady error
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Start(_ context.Context) error {
+	return nil
+}
+
+func (p *fakeProvider) Stop(_ context.Context) error {
+	p.stopped = true
+	return nil
+}
+
+func (p *fakeProvider) Ready(_ context.Context) error {
+	return p.notReady
+}
+
+func (p *fakeProvider) ListOAuthApplications(
+	_ context.Context,
+	_ portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	close(p.received)
+	<-p.release
+	return portalv1.ListOAuthApplications200JSONResponse{Applications: []portalv1.OAuthApplicationDetails{}}, nil
+}
+
+// freePort returns a port that nothing is listening on.
+func freePort() string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	defer l.Close()
+
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+var _ = Describe("ListenAndServe", func() {
+	var (
+		provider *fakeProvider
+		opts     *connector.Options
+		ctx      context.Context
+		cancel   context.CancelFunc
+		served   chan error
+	)
+
+	BeforeEach(func() {
+		provider = &fakeProvider{
+			received: make(chan struct{}),
+			release:  make(chan struct{}),
+		}
+		opts = &connector.Options{
+			Port:                freePort(),
+			ShutdownGracePeriod: 5 * time.Second,
+		}
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		served = make(chan error, 1)
+		go func() {
+			served <- connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", opts.Port))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+	})
+
+	It("completes in-flight requests when the context is cancelled", func() {
+		responses := make(chan *http.Response, 1)
+		go func() {
+			defer GinkgoRecover()
+			resp, err := http.Get("http://127.0.0.1:" + opts.Port + "/applications")
+			Expect(err).NotTo(HaveOccurred())
+			responses <- resp
+		}()
+
+		Eventually(provider.received).Should(BeClosed())
+		cancel()
+
+		// The server keeps waiting for the request.
+		Consistently(served, 200*time.Millisecond).ShouldNot(Receive())
+
+		close(provider.release)
+		var resp *http.Response
+		Eventually(responses).Should(Receive(&resp))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		Eventually(served).Should(Receive(BeNil()))
+		Expect(provider.stopped).To(BeTrue())
+	})
+
+	It("gives up on in-flight requests after the grace period", func() {
+		opts.ShutdownGracePeriod = 0
+		DeferCleanup(func() { close(provider.release) })
+
+		go func() {
+			_, _ = http.Get("http://127.0.0.1:" + opts.Port + "/applications")
+		}()
+
+		Eventually(provider.received).Should(BeClosed())
+		cancel()
+
+		Eventually(served).Should(Receive(HaveOccurred()))
+	})
+})
@@ -0,0 +1,57 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// timeoutTransport limits the time that each call to the IdP may take.
+type timeoutTransport struct {
+	timeout time.Duration
+	base    http.RoundTripper
+}
+
+// NewTimeoutTransport wraps the transport used by a connector to call its IdP, so that each call is cancelled if it
+// has not completed within the timeout, including reading its response. Calls are also cancelled along with the
+// context of their request. There is no timeout if it is 0.
+func NewTimeoutTransport(timeout time.Duration, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if timeout <= 0 {
+		return base
+	}
+
+	return &timeoutTransport{
+		timeout: timeout,
+		base:    base,
+	}
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The call is only complete once its response has been read.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels the context of a call once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
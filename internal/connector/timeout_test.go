@@ -0,0 +1,62 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+var _ = Describe("NewTimeoutTransport", func() {
+	var (
+		delay time.Duration
+		idp   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		release := make(chan struct{})
+		idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+				_, _ = w.Write([]byte("ok"))
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		DeferCleanup(idp.Close)
+		DeferCleanup(func() { close(release) })
+	})
+
+	It("cancels a call that takes longer than the timeout", func() {
+		delay = time.Minute
+		client := &http.Client{Transport: connector.NewTimeoutTransport(50*time.Millisecond, nil)}
+
+		start := time.Now()
+		_, err := client.Get(idp.URL)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("lets the response of a call within the timeout be read", func() {
+		delay = 0
+		client := &http.Client{Transport: connector.NewTimeoutTransport(5*time.Second, nil)}
+
+		resp, err := client.Get(idp.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("ok"))
+	})
+
+	It("has no timeout if it is 0", func() {
+		Expect(connector.NewTimeoutTransport(0, http.DefaultTransport)).To(BeIdenticalTo(http.DefaultTransport))
+	})
+})
@@ -0,0 +1,137 @@
+package connector
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// tlsFiles loads the serving certificate and client CA bundle, and reloads them when the files change, such as when a
+// mounted secret is updated.
+type tlsFiles struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu       sync.Mutex
+	modTimes [3]time.Time
+	config   *tls.Config
+}
+
+// newTLSConfig returns the TLS config for the API server, or nil if it serves plain HTTP. When a client CA file is set,
+// clients with a certificate must present one signed by it; API requests without one are then rejected by
+// requireClientCert, so that probes can still be served without a certificate.
+func newTLSConfig(opts *Options) (*tls.Config, error) {
+	switch {
+	case opts.TLSCertFile == "" && opts.TLSKeyFile == "":
+		if opts.ClientCAFile != "" {
+			return nil, eris.New("a TLS certificate and key are required to verify client certificates")
+		}
+		return nil, nil
+	case opts.TLSCertFile == "" || opts.TLSKeyFile == "":
+		return nil, eris.New("both a TLS certificate and key are required")
+	}
+
+	files := &tlsFiles{
+		certFile:     opts.TLSCertFile,
+		keyFile:      opts.TLSKeyFile,
+		clientCAFile: opts.ClientCAFile,
+	}
+	// Fail fast on invalid files, rather than on the first handshake.
+	if _, err := files.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return files.load()
+		},
+	}, nil
+}
+
+// load returns the config for the current files, reloading them if any has been modified since they were last loaded.
+// If the changed files cannot be loaded, such as while only some of them have been updated, the previous config is
+// kept.
+func (f *tlsFiles) load() (*tls.Config, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var modTimes [3]time.Time
+	for i, file := range []string{f.certFile, f.keyFile, f.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return f.keep(eris.Wrapf(err, "could not read %s", file))
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if f.config != nil && modTimes == f.modTimes {
+		return f.config, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return f.keep(eris.Wrap(err, "could not load TLS certificate and key"))
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if f.clientCAFile != "" {
+		pem, err := os.ReadFile(f.clientCAFile)
+		if err != nil {
+			return f.keep(eris.Wrapf(err, "could not read %s", f.clientCAFile))
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return f.keep(eris.Errorf("no certificates found in %s", f.clientCAFile))
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	if f.config != nil {
+		slog.Info("Reloaded TLS certificate", "file", f.certFile)
+	}
+	f.config = config
+	f.modTimes = modTimes
+
+	return config, nil
+}
+
+// keep returns the previously loaded config, or the error if nothing has been loaded yet.
+func (f *tlsFiles) keep(err error) (*tls.Config, error) {
+	if f.config == nil {
+		return nil, err
+	}
+
+	slog.Warn("Keeping the previous TLS certificate", "error", err)
+	return f.config, nil
+}
+
+// requireClientCert rejects API requests from clients that did not present a verified certificate.
+func requireClientCert(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if isProbe(c) {
+			return next(c)
+		}
+
+		if state := c.Request().TLS; state == nil || len(state.VerifiedChains) == 0 {
+			return c.JSON(http.StatusUnauthorized, NewPortal401Error("Client certificate required"))
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,210 @@
+package connector_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+// testCA issues certificates for the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a PEM encoded certificate and key for the usage, valid for 127.0.0.1.
+func (ca *testCA) issue(usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+var _ = Describe("TLS", func() {
+	var (
+		ca   *testCA
+		opts *connector.Options
+	)
+
+	// writeServerCert issues a new serving certificate from the CA, and writes it along with the client CA.
+	writeServerCert := func(modTime time.Time) {
+		cert, key := ca.issue(x509.ExtKeyUsageServerAuth)
+		Expect(os.WriteFile(opts.TLSCertFile, cert, 0o600)).To(Succeed())
+		Expect(os.WriteFile(opts.TLSKeyFile, key, 0o600)).To(Succeed())
+		Expect(os.WriteFile(opts.ClientCAFile, ca.pem, 0o600)).To(Succeed())
+		for _, file := range []string{opts.TLSCertFile, opts.TLSKeyFile, opts.ClientCAFile} {
+			Expect(os.Chtimes(file, modTime, modTime)).To(Succeed())
+		}
+	}
+
+	// client returns an HTTPS client that trusts the CA, presenting a client certificate from it if asked to.
+	client := func(withCert bool) *http.Client {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		config := &tls.Config{RootCAs: roots}
+
+		if withCert {
+			certPEM, keyPEM := ca.issue(x509.ExtKeyUsageClientAuth)
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			Expect(err).NotTo(HaveOccurred())
+			config.Certificates = []tls.Certificate{cert}
+		}
+
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	}
+
+	get := func(c *http.Client, path string) (*http.Response, error) {
+		return c.Get("https://127.0.0.1:" + opts.Port + path)
+	}
+
+	BeforeEach(func() {
+		ca = newTestCA()
+
+		dir := GinkgoT().TempDir()
+		opts = &connector.Options{
+			Port:                freePort(),
+			ShutdownGracePeriod: 5 * time.Second,
+			TLSCertFile:         filepath.Join(dir, "tls.crt"),
+			TLSKeyFile:          filepath.Join(dir, "tls.key"),
+			ClientCAFile:        filepath.Join(dir, "ca.crt"),
+		}
+		writeServerCert(time.Now().Add(-time.Minute))
+
+		provider := &fakeProvider{
+			received: make(chan struct{}),
+			release:  make(chan struct{}),
+		}
+		close(provider.release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		go func() {
+			_ = connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			_, err := get(client(false), "/healthz")
+			return err
+		}).Should(Succeed())
+	})
+
+	It("serves API requests from clients with a certificate", func() {
+		resp, err := get(client(true), "/applications")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("rejects API requests from clients without a certificate", func() {
+		resp, err := get(client(false), "/applications")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("serves probes to clients without a certificate", func() {
+		resp, err := get(client(false), "/readyz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("rejects clients with a certificate from another CA", func() {
+		certPEM, keyPEM := newTestCA().issue(x509.ExtKeyUsageClientAuth)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		Expect(err).NotTo(HaveOccurred())
+
+		untrusted := client(false)
+		untrusted.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+		_, err = get(untrusted, "/applications")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reloads the certificates when the files change", func() {
+		ca = newTestCA()
+		writeServerCert(time.Now())
+
+		// Clients of the new CA are trusted, and trust the new serving certificate.
+		Eventually(func() (int, error) {
+			resp, err := get(client(true), "/applications")
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode, nil
+		}).Should(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("TLS configuration", func() {
+	It("requires both a certificate and a key", func() {
+		err := connector.ListenAndServe(context.Background(), &connector.Options{
+			Port:        freePort(),
+			TLSCertFile: "tls.crt",
+		}, &fakeProvider{})
+		Expect(err).To(MatchError(ContainSubstring("TLS")))
+	})
+
+	It("requires a certificate to verify client certificates", func() {
+		err := connector.ListenAndServe(context.Background(), &connector.Options{
+			Port:         freePort(),
+			ClientCAFile: "ca.crt",
+		}, &fakeProvider{})
+		Expect(err).To(MatchError(ContainSubstring("TLS")))
+	})
+})
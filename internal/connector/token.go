@@ -0,0 +1,142 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenExpiryDelta is how long before it expires that a token is refreshed, so that it does not expire in flight.
+	tokenExpiryDelta = 10 * time.Second
+
+	// defaultTokenLifetime is assumed when the token endpoint does not say when the token expires.
+	defaultTokenLifetime = time.Minute
+)
+
+// Token is an access token that a connector authenticates its calls to the IdP with.
+type Token struct {
+	AccessToken string
+	// ExpiresIn is the number of seconds that the token is valid for, or 0 if the token endpoint did not say.
+	ExpiresIn int
+}
+
+// FetchToken requests a new token from the token endpoint of the IdP.
+type FetchToken func(ctx context.Context) (*Token, error)
+
+// TokenSource caches the token of a connector, requesting a new one with its fetch func shortly before it expires. It
+// is safe for concurrent use; concurrent requests for an expired token wait for a single fetch, and the fetch func is
+// never called concurrently.
+type TokenSource struct {
+	fetch FetchToken
+
+	// mu is held while a token is fetched.
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewTokenSource returns a token source that fetches its first token when it is first asked for one.
+func NewTokenSource(fetch FetchToken) *TokenSource {
+	return &TokenSource{fetch: fetch}
+}
+
+// Token returns a token that is valid for at least the next few seconds.
+func (t *TokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.token != "" && now.Before(t.expiry) {
+		return t.token, nil
+	}
+
+	token, err := t.fetch(ctx)
+	if err != nil {
+		t.token = ""
+		return "", err
+	}
+
+	t.token = token.AccessToken
+	t.expiry = now.Add(TokenLifetime(token.ExpiresIn))
+
+	return t.token, nil
+}
+
+// Invalidate discards the token if it is still the cached one, such as when the IdP rejected it, so that the next call
+// to Token fetches a new one.
+func (t *TokenSource) Invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.expiry = time.Time{}
+	}
+}
+
+// TokenLifetime returns how long a token that expires in the given number of seconds can be used for.
+func TokenLifetime(expiresIn int) time.Duration {
+	if expiresIn <= 0 {
+		return defaultTokenLifetime
+	}
+
+	d := time.Duration(expiresIn) * time.Second
+	return d - min(tokenExpiryDelta, d/2)
+}
+
+// tokenTransport authenticates calls to the IdP with the token of a token source.
+type tokenTransport struct {
+	tokens *TokenSource
+	base   http.RoundTripper
+}
+
+// NewTokenTransport wraps the transport used by a connector to call its IdP, so that calls are authenticated with a
+// bearer token from the token source. A call that is rejected with 401 Unauthorized is retried once with a new token,
+// as a token can be rejected before it expires, such as when its session is ended or the signing key is rotated.
+func NewTokenTransport(tokens *TokenSource, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &tokenTransport{
+		tokens: tokens,
+		base:   base,
+	}
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	t.tokens.Invalidate(token)
+
+	if token, err = t.tokens.Token(req.Context()); err != nil {
+		return nil, err
+	}
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.base.RoundTrip(retry)
+}
+
+// withToken returns a copy of the request that is authenticated with the token.
+func withToken(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
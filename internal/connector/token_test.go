@@ -0,0 +1,84 @@
+package connector_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+var _ = Describe("TokenSource", func() {
+	var (
+		fetches atomic.Int32
+		valid   sync.Map
+		tokens  *connector.TokenSource
+		idp     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		fetches.Store(0)
+		valid = sync.Map{}
+		tokens = connector.NewTokenSource(func(context.Context) (*connector.Token, error) {
+			token := fmt.Sprintf("token-%d", fetches.Add(1))
+			valid.Store(token, true)
+			return &connector.Token{AccessToken: token, ExpiresIn: 3600}, nil
+		})
+
+		idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := valid.Load(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")); !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		DeferCleanup(idp.Close)
+	})
+
+	It("fetches a single token for concurrent requests", func() {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				token, err := tokens.Token(context.Background())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token).To(Equal("token-1"))
+			}()
+		}
+		wg.Wait()
+
+		Expect(fetches.Load()).To(Equal(int32(1)))
+	})
+
+	It("retries a call that the IdP rejects once with a new token", func() {
+		client := &http.Client{Transport: connector.NewTokenTransport(tokens, nil)}
+
+		resp, err := client.Get(idp.URL)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		valid.Delete("token-1")
+		resp, err = client.Get(idp.URL)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(fetches.Load()).To(Equal(int32(2)))
+	})
+
+	It("uses a token for a little less than its lifetime", func() {
+		Expect(connector.TokenLifetime(3600)).To(BeNumerically("<", connector.TokenLifetime(3601)))
+		Expect(connector.TokenLifetime(3600).Seconds()).To(BeNumerically("~", 3590, 1))
+		Expect(connector.TokenLifetime(0).Seconds()).To(BeNumerically(">", 0))
+	})
+})
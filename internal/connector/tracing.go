@@ -0,0 +1,171 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	serviceName = "idp-connect"
+
+	// connectorKey is the attribute that gives the connector of the spans and the traces they are exported with.
+	connectorKey = attribute.Key("idp_connect.connector")
+)
+
+// traceContext continues the traces of API requests from their W3C traceparent header, and passes them on to the IdP
+// with the same header.
+var traceContext = propagation.TraceContext{}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// configureTracing sets the global tracer provider to one that exports spans to an OTLP endpoint, if one is set by the
+// options or the standard OTEL_EXPORTER_OTLP environment variables. It returns a function that exports the spans that
+// have not been exported yet and stops exporting them.
+func configureTracing(ctx context.Context, opts *Options, connector string) (func(context.Context) error, error) {
+	if opts.OTLPEndpoint == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporterOpts []otlptracehttp.Option
+	if opts.OTLPEndpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.OTLPEndpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, eris.Wrap(err, "could not create OTLP trace exporter")
+	}
+
+	// The service name can be overridden with OTEL_SERVICE_NAME.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), connectorKey.String(connector)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, eris.Wrap(err, "could not describe the traced service")
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// requestTracing returns middleware that traces each API request with a span named by the operation ID of the matched
+// route in the swagger spec, continuing the trace of the traceparent header of the request if it has one.
+func requestTracing(swagger *openapi3.T) echo.MiddlewareFunc {
+	operations := operationIDs(swagger)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if isProbe(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			operation, ok := operations[req.Method+" "+c.Path()]
+			if !ok {
+				operation = unknownOperation
+			}
+
+			ctx := traceContext.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer().Start(ctx, operation,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(c.Path()),
+					semconv.URLPath(req.URL.Path),
+					attribute.String("idp_connect.request_id", RequestID(ctx)),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			// Handle the error here, so that the response status is known.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= 500 {
+				reason, _ := c.Get(reasonKey).(string)
+				span.SetStatus(codes.Error, reason)
+			}
+
+			return nil
+		}
+	}
+}
+
+// tracingTransport traces each call that a connector makes to its IdP.
+type tracingTransport struct {
+	connector string
+	base      http.RoundTripper
+}
+
+// NewTracingTransport wraps the transport used by a connector to call its IdP, so that each call is traced with a span
+// named by the operation set with WithUpstreamOperation on the request context, which is a child of the span of the
+// API request that the call is made for. The trace is passed on to the IdP with a traceparent header.
+func NewTracingTransport(connector string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &tracingTransport{
+		connector: connector,
+		base:      base,
+	}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer().Start(req.Context(), UpstreamOperation(req.Context()),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			connectorKey.String(t.connector),
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	// A transport must not modify the request it is given.
+	req = req.Clone(ctx)
+	traceContext.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	// Clients that are not found or already exist are expected while looking clients up.
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusConflict {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,130 @@
+package connector_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+var _ = Describe("Tracing", func() {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var (
+		exporter     *tracetest.InMemoryExporter
+		status       int
+		traceparents chan string
+		opts         *connector.Options
+	)
+
+	// span returns the span of the trace of Portal with the name, once it has ended.
+	span := func(name string) tracetest.SpanStub {
+		var found tracetest.SpanStub
+		Eventually(func() bool {
+			for _, s := range exporter.GetSpans() {
+				if s.Name == name && s.SpanContext.TraceID().String() == "4bf92f3577b34da6a3ce929d0e0e4736" {
+					found = s
+					return true
+				}
+			}
+			return false
+		}).Should(BeTrue())
+		return found
+	}
+
+	attr := func(s tracetest.SpanStub, key attribute.Key) interface{} {
+		for _, kv := range s.Attributes {
+			if kv.Key == key {
+				return kv.Value.AsInterface()
+			}
+		}
+		return nil
+	}
+
+	get := func(path string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:"+opts.Port+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Traceparent", traceparent)
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(resp.Body.Close)
+		return resp
+	}
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+		DeferCleanup(otel.SetTracerProvider, noop.NewTracerProvider())
+
+		traceparents = make(chan string, 10)
+		idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparents <- r.Header.Get("Traceparent")
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(idp.Close)
+
+		opts = &connector.Options{
+			Port:                freePort(),
+			ShutdownGracePeriod: 5 * time.Second,
+		}
+		provider := &upstreamProvider{
+			client: &http.Client{Transport: opts.UpstreamTransport("tracing", nil)},
+			url:    idp.URL,
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		go func() {
+			_ = connector.ListenAndServe(ctx, opts, provider)
+		}()
+
+		Eventually(func() error {
+			_, err := http.Get("http://127.0.0.1:" + opts.Port + "/healthz")
+			return err
+		}).Should(Succeed())
+	})
+
+	It("continues the trace of Portal with a span for the operation, and child spans for the calls to the IdP", func() {
+		status = http.StatusOK
+		Expect(get("/applications/app").StatusCode).To(Equal(http.StatusOK))
+
+		server := span("GetOAuthApplication")
+		Expect(server.SpanKind).To(Equal(trace.SpanKindServer))
+		Expect(server.Parent.SpanID().String()).To(Equal("00f067aa0ba902b7"))
+		Expect(attr(server, "http.route")).To(Equal("/applications/:id"))
+		Expect(attr(server, "http.response.status_code")).To(Equal(int64(http.StatusOK)))
+		Expect(server.Status.Code).To(Equal(codes.Unset))
+
+		client := span("GetClient")
+		Expect(client.SpanKind).To(Equal(trace.SpanKindClient))
+		Expect(client.Parent.SpanID()).To(Equal(server.SpanContext.SpanID()))
+		Expect(attr(client, "idp_connect.connector")).To(Equal("tracing"))
+		Expect(attr(client, "http.response.status_code")).To(Equal(int64(http.StatusOK)))
+
+		Expect(<-traceparents).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-" + client.SpanContext.SpanID().String() + "-01"))
+	})
+
+	It("marks the spans of a request that the IdP failed as errors", func() {
+		status = http.StatusBadGateway
+		Expect(get("/applications/app").StatusCode).To(Equal(http.StatusInternalServerError))
+
+		client := span("GetClient")
+		Expect(client.Status.Code).To(Equal(codes.Error))
+
+		server := span("GetOAuthApplication")
+		Expect(server.Status.Code).To(Equal(codes.Error))
+		Expect(server.Status.Description).To(Equal("502 Bad Gateway"))
+	})
+})
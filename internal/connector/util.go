@@ -0,0 +1,89 @@
+package connector
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// DefaultPageLimit is the number of applications listed per page when the request does not set a limit.
+const DefaultPageLimit = 20
+
+// PageLimit returns the requested number of applications per page, or DefaultPageLimit if none was requested.
+func PageLimit(limit *int) int {
+	if limit == nil || *limit <= 0 {
+		return DefaultPageLimit
+	}
+
+	return *limit
+}
+
+// NextPageCursor returns the cursor of the next page from the RFC 8288 Link header of a list response, which is the
+// query parameter of the "next" link, or nil if there is no next page.
+func NextPageCursor(header http.Header, param string) *string {
+	for _, link := range header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(part, ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+
+			next, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				return nil
+			}
+
+			if cursor := next.Query().Get(param); cursor != "" {
+				return &cursor
+			}
+		}
+	}
+
+	return nil
+}
+
+func NewPortalError(code int, msg, reason string) portalv1.Error {
+	return portalv1.Error{
+		Code:    code,
+		Message: msg,
+		Reason:  reason,
+	}
+}
+
+func NewPortal400Error(reason string) portalv1.Error {
+	return NewPortalError(400, "Bad Request", reason)
+}
+
+func NewPortal401Error(reason string) portalv1.Error {
+	return NewPortalError(401, "Unauthorized", reason)
+}
+
+func NewPortal403Error(reason string) portalv1.Error {
+	return NewPortalError(403, "Forbidden", reason)
+}
+
+func NewPortal404Error(reason string) portalv1.Error {
+	return NewPortalError(404, "Not Found", reason)
+}
+
+func NewPortal409Error(reason string) portalv1.Error {
+	return NewPortalError(409, "Conflict", reason)
+}
+
+func NewPortal500Error(reason string) portalv1.Error {
+	return NewPortalError(500, "Internal Server Error", reason)
+}
+
+// NewNotSupportedError is the error of an operation that the connector of the IdP does not support.
+func NewNotSupportedError(idp, operation string) portalv1.Error {
+	return NewPortal500Error("the " + idp + " connector does not support " + operation)
+}
+
+// NewApplicationExistsError is the error of creating an application with an id that a client already exists for.
+// Connectors look for such a client before creating one, as most IdPs do not require client names to be unique, so a
+// retried request would otherwise create a second client for the same application.
+func NewApplicationExistsError(id, clientId string) portalv1.Error {
+	return NewPortal409Error("client [" + clientId + "] already exists for application [" + id + "]")
+}
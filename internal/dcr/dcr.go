@@ -0,0 +1,28 @@
+package dcr
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/dcr/server"
+)
+
+func Command() *cobra.Command {
+	serverOpts := &server.Options{}
+
+	cmd := &cobra.Command{
+		Short: "Start the OAuth 2.0 Dynamic Client Registration (RFC 7591/7592) IDP connector",
+		Use:   "dcr",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return serverOpts.ConfigureLogging()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.ListenAndServe(cmd.Context(), serverOpts)
+		},
+		// option to silence usage when an error occurs
+		SilenceUsage: true,
+	}
+
+	serverOpts.AddToFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,577 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	resty "github.com/go-resty/resty/v2"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// readOnlyMetadata are the fields returned by the registration endpoint that must not be sent when updating a client.
+var readOnlyMetadata = []string{
+	"registration_access_token",
+	"registration_client_uri",
+	"client_secret_expires_at",
+	"client_id_issued_at",
+}
+
+type StrictServerHandler struct {
+	restClient           resty.Client
+	issuer               string
+	registrationEndpoint string
+	initialAccessToken   string
+	store                *Store
+
+	// registering is locked for an id while a client is registered for it, so that concurrent requests for the same id
+	// register one client.
+	registering idLocks
+}
+
+// idLocks holds a lock for each id that is in use, so that requests for different ids do not wait for each other.
+type idLocks struct {
+	mu    sync.Mutex
+	locks map[string]*idLock
+}
+
+type idLock struct {
+	sync.Mutex
+
+	// users is the number of requests that hold or wait for the lock, which is forgotten once there are none.
+	users int
+}
+
+// lock locks the id, and returns the func that unlocks it.
+func (l *idLocks) lock(id string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*idLock{}
+	}
+	lock, ok := l.locks[id]
+	if !ok {
+		lock = &idLock{}
+		l.locks[id] = lock
+	}
+	lock.users++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if lock.users--; lock.users == 0 {
+			delete(l.locks, id)
+		}
+	}
+}
+
+// ClientInformation is the RFC 7591 client information response, as returned when a client is registered, read or
+// updated.
+type ClientInformation struct {
+	ClientId                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientName              string `json:"client_name,omitempty"`
+	Scope                   string `json:"scope,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+}
+
+// RegistrationError is the RFC 7591 error response.
+type RegistrationError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func NewStrictServerHandler(opts *Options, restyClient *resty.Client, registrationEndpoint string, store *Store) *StrictServerHandler {
+	restyClient.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
+		r.SetError(&RegistrationError{})
+		return nil
+	})
+
+	return &StrictServerHandler{
+		restClient:           *restyClient,
+		issuer:               strings.TrimSuffix(opts.Issuer, "/"),
+		registrationEndpoint: registrationEndpoint,
+		initialAccessToken:   opts.InitialAccessToken,
+		store:                store,
+	}
+}
+
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "dcr"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that the OpenID configuration of the issuer can be read.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	resp, err := s.upstream(ctx, "GetOpenIDConfiguration").
+		Get(s.issuer + wellKnownOpenIDConfigPath)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("could not read OpenID configuration: %s", resp.Status())
+	}
+
+	return nil
+}
+
+// CreateOAuthApplication registers a client with the client credentials grant, and keeps its registration access
+// token so that it can be managed later. A client whose registration cannot be kept is deleted again, as it could not
+// be managed.
+func (s *StrictServerHandler) CreateOAuthApplication(
+	ctx context.Context,
+	request portalv1.CreateOAuthApplicationRequestObject,
+) (portalv1.CreateOAuthApplicationResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("client ID is required")), nil
+	}
+
+	defer s.registering.lock(request.Body.Id)()
+
+	if existing, ok := s.store.RegistrationByName(request.Body.Id); ok {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, existing.ClientId)), nil
+	}
+
+	req := s.upstream(ctx, "RegisterClient")
+	if s.initialAccessToken != "" {
+		req.SetAuthToken(s.initialAccessToken)
+	}
+
+	var info ClientInformation
+	resp, err := req.
+		SetBody(map[string]interface{}{
+			"client_name":                request.Body.Id,
+			"grant_types":                []string{"client_credentials"},
+			"response_types":             []string{},
+			"token_endpoint_auth_method": "client_secret_basic",
+		}).
+		SetResult(&info).
+		Post(s.registrationEndpoint)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 400:
+			return portalv1.CreateOAuthApplication400JSONResponse(portalErr), nil
+		default:
+			return portalv1.CreateOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	if info.RegistrationAccessToken == "" || info.RegistrationClientURI == "" {
+		s.unregister(ctx, info)
+		return portalv1.CreateOAuthApplication500JSONResponse(connector.NewPortal500Error(
+			"registration endpoint did not return a registration access token for client " + info.ClientId)), nil
+	}
+
+	if err := s.store.PutRegistration(Registration{
+		ClientId:                info.ClientId,
+		ClientName:              request.Body.Id,
+		RegistrationAccessToken: info.RegistrationAccessToken,
+		RegistrationClientURI:   info.RegistrationClientURI,
+		CreatedAt:               connector.NewOwnership(request.Body.Id).CreatedAt,
+	}); err != nil {
+		s.unregister(ctx, info)
+		return portalv1.CreateOAuthApplication500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	return portalv1.CreateOAuthApplication201JSONResponse{
+		ClientId:     info.ClientId,
+		ClientName:   &request.Body.Id,
+		ClientSecret: info.ClientSecret,
+	}, nil
+}
+
+// DeleteOAuthApplication deletes a client with its registration access token. A client that was already deleted in the
+// provider is forgotten. The store is the record of the clients that the connector created, and clients that are not
+// in it cannot be deleted without a registration access token, even when deletes are forced.
+func (s *StrictServerHandler) DeleteOAuthApplication(
+	ctx context.Context,
+	request portalv1.DeleteOAuthApplicationRequestObject,
+) (portalv1.DeleteOAuthApplicationResponseObject, error) {
+	registration, ok := s.store.Registration(request.Id)
+	if !ok {
+		return portalv1.DeleteOAuthApplication404JSONResponse(notFoundError(request.Id)), nil
+	}
+
+	resp, err := s.upstream(ctx, "DeleteClient").
+		SetAuthToken(registration.RegistrationAccessToken).
+		Delete(registration.RegistrationClientURI)
+	if err != nil || (resp.IsError() && resp.StatusCode() != 404) {
+		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	if err := s.store.DeleteRegistration(registration.ClientId); err != nil {
+		return portalv1.DeleteOAuthApplication500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications lists the clients created by the connector. The cursor is the last client ID of the previous
+// page.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	after := ""
+	if request.Params.Cursor != nil {
+		after = *request.Params.Cursor
+	}
+	limit := connector.PageLimit(request.Params.Limit)
+
+	registrations := s.store.Registrations(after, limit)
+
+	applications := make([]portalv1.OAuthApplicationDetails, 0, len(registrations))
+	for _, registration := range registrations {
+		metadata, resp, err := s.readClient(ctx, &registration)
+		if err != nil || resp.IsError() {
+			return portalv1.ListOAuthApplications500JSONResponse(unwrapError(resp, err)), nil
+		}
+		applications = append(applications, applicationDetails(registration, metadata))
+	}
+
+	page := portalv1.ListOAuthApplications200JSONResponse{Applications: applications}
+	if len(registrations) == limit {
+		page.NextCursor = &registrations[len(registrations)-1].ClientId
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication reads a client with its registration access token.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	registration, ok := s.store.Registration(request.Id)
+	if !ok {
+		return portalv1.GetOAuthApplication404JSONResponse(notFoundError(request.Id)), nil
+	}
+
+	metadata, resp, err := s.readClient(ctx, &registration)
+	if err != nil || resp.IsError() {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(applicationDetails(registration, metadata)), nil
+}
+
+// RotateOAuthApplicationSecret updates a client without its secret, for which RFC 7592 allows the provider to issue a
+// new secret. Providers that keep the secret instead are reported as an error.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	registration, ok := s.store.Registration(request.Id)
+	if !ok {
+		return portalv1.RotateOAuthApplicationSecret404JSONResponse(notFoundError(request.Id)), nil
+	}
+
+	metadata, resp, err := s.readClient(ctx, &registration)
+	if err != nil || resp.IsError() {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	previousSecret, _ := metadata["client_secret"].(string)
+	delete(metadata, "client_secret")
+
+	info, resp, err := s.updateClient(ctx, &registration, metadata)
+	if err != nil || resp.IsError() {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	if info.ClientSecret == "" || info.ClientSecret == previousSecret {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(connector.NewPortal500Error(
+			"the provider did not issue a new secret for client " + request.Id)), nil
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     info.ClientId,
+		ClientName:   &registration.ClientName,
+		ClientSecret: info.ClientSecret,
+	}, nil
+}
+
+// GrantAPIProductAccess adds the scopes of the API products to the client.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("API products are required")), nil
+	}
+
+	registration, ok := s.store.Registration(request.Id)
+	if !ok {
+		return portalv1.GrantAPIProductAccess404JSONResponse(notFoundError(request.Id)), nil
+	}
+
+	for _, apiProduct := range request.Body.ApiProducts {
+		if !s.store.APIProductExists(apiProduct) {
+			return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error(
+				fmt.Sprintf("API product '%s' not found", apiProduct))), nil
+		}
+	}
+
+	resp, err := s.updateScopes(ctx, &registration, func(scopes []string) []string {
+		for _, apiProduct := range request.Body.ApiProducts {
+			if !slices.Contains(scopes, apiProduct) {
+				scopes = append(scopes, apiProduct)
+			}
+		}
+		return scopes
+	})
+	if err != nil || resp.IsError() {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the scopes of the API products from the client.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	registration, ok := s.store.Registration(request.Id)
+	if !ok {
+		return portalv1.RevokeAPIProductAccess404JSONResponse(notFoundError(request.Id)), nil
+	}
+
+	resp, err := s.updateScopes(ctx, &registration, func(scopes []string) []string {
+		return slices.DeleteFunc(scopes, func(scope string) bool {
+			return slices.Contains(request.Params.ApiProducts, scope)
+		})
+	})
+	if err != nil || resp.IsError() {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct records an API product, so that clients can be granted its scope. RFC 7591 has no way to manage
+// scopes, so the scope must also be defined in the provider.
+func (s *StrictServerHandler) CreateAPIProduct(
+	_ context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product ID is required")), nil
+	}
+
+	if s.store.APIProductExists(request.Body.Id) {
+		return portalv1.CreateAPIProduct409JSONResponse(connector.NewPortal409Error(
+			fmt.Sprintf("API product '%s' already exists", request.Body.Id))), nil
+	}
+
+	description := ""
+	if request.Body.Description != nil {
+		description = *request.Body.Description
+	}
+	if err := s.store.PutAPIProduct(request.Body.Id, description); err != nil {
+		return portalv1.CreateAPIProduct500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct forgets an API product. Clients keep its scope until their access is revoked.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	_ context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	if !s.store.APIProductExists(request.Id) {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error(
+			fmt.Sprintf("API product '%s' not found", request.Id))), nil
+	}
+
+	if err := s.store.DeleteAPIProduct(request.Id); err != nil {
+		return portalv1.DeleteAPIProduct500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// upstream returns a request to the provider that is labelled with the given operation in the upstream metrics.
+func (s *StrictServerHandler) upstream(ctx context.Context, operation string) *resty.Request {
+	return s.restClient.R().SetContext(connector.WithUpstreamOperation(ctx, operation))
+}
+
+// unregister deletes a client that was just registered but cannot be kept, with the registration access token it was
+// issued. Its registration client URI is assumed to be under the registration endpoint if it was not returned. Without
+// a registration access token, the client can only be deleted in the provider itself.
+func (s *StrictServerHandler) unregister(ctx context.Context, info ClientInformation) {
+	logger := connector.Logger(ctx).With("client_id", info.ClientId)
+	if info.RegistrationAccessToken == "" {
+		logger.Error("Registered client cannot be deleted without a registration access token, delete it in the provider")
+		return
+	}
+
+	uri := info.RegistrationClientURI
+	if uri == "" {
+		uri = strings.TrimSuffix(s.registrationEndpoint, "/") + "/" + url.PathEscape(info.ClientId)
+	}
+
+	resp, err := s.upstream(ctx, "DeleteClient").
+		SetAuthToken(info.RegistrationAccessToken).
+		Delete(uri)
+	if err != nil || (resp.IsError() && resp.StatusCode() != 404) {
+		logger.Error("Could not delete registered client, delete it in the provider", "reason", unwrapError(resp, err).Reason)
+	}
+}
+
+// readClient returns the metadata of a client, as read from its registration client URI.
+func (s *StrictServerHandler) readClient(ctx context.Context, registration *Registration) (map[string]interface{}, *resty.Response, error) {
+	var metadata map[string]interface{}
+	resp, err := s.upstream(ctx, "ReadClient").
+		SetAuthToken(registration.RegistrationAccessToken).
+		SetResult(&metadata).
+		Get(registration.RegistrationClientURI)
+	if err != nil || resp.IsError() {
+		return nil, resp, err
+	}
+
+	return metadata, resp, s.keepRegistrationAccessToken(registration, metadata)
+}
+
+// updateClient replaces the metadata of a client. Metadata that is left out is removed from the client.
+func (s *StrictServerHandler) updateClient(
+	ctx context.Context,
+	registration *Registration,
+	metadata map[string]interface{},
+) (ClientInformation, *resty.Response, error) {
+	for _, field := range readOnlyMetadata {
+		delete(metadata, field)
+	}
+	metadata["client_id"] = registration.ClientId
+
+	var updated map[string]interface{}
+	resp, err := s.upstream(ctx, "UpdateClient").
+		SetAuthToken(registration.RegistrationAccessToken).
+		SetBody(metadata).
+		SetResult(&updated).
+		Put(registration.RegistrationClientURI)
+	if err != nil || resp.IsError() {
+		return ClientInformation{}, resp, err
+	}
+
+	if err := s.keepRegistrationAccessToken(registration, updated); err != nil {
+		return ClientInformation{}, resp, err
+	}
+
+	info, err := clientInformation(updated)
+	return info, resp, err
+}
+
+// updateScopes changes the scopes of a client, keeping the rest of its metadata, including its secret.
+func (s *StrictServerHandler) updateScopes(
+	ctx context.Context,
+	registration *Registration,
+	change func([]string) []string,
+) (*resty.Response, error) {
+	metadata, resp, err := s.readClient(ctx, registration)
+	if err != nil || resp.IsError() {
+		return resp, err
+	}
+
+	scope, _ := metadata["scope"].(string)
+	scopes := strings.Fields(scope)
+	updatedScopes := change(slices.Clone(scopes))
+	if slices.Equal(scopes, updatedScopes) {
+		return resp, nil
+	}
+	metadata["scope"] = strings.Join(updatedScopes, " ")
+
+	_, resp, err = s.updateClient(ctx, registration, metadata)
+	return resp, err
+}
+
+// keepRegistrationAccessToken updates and stores the registration with the registration access token in the metadata,
+// if the provider issued a new one as RFC 7592 allows on each read or update.
+func (s *StrictServerHandler) keepRegistrationAccessToken(registration *Registration, metadata map[string]interface{}) error {
+	token, _ := metadata["registration_access_token"].(string)
+	if token == "" || token == registration.RegistrationAccessToken {
+		return nil
+	}
+
+	registration.RegistrationAccessToken = token
+	return s.store.PutRegistration(*registration)
+}
+
+func clientInformation(metadata map[string]interface{}) (ClientInformation, error) {
+	var info ClientInformation
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return info, err
+	}
+
+	return info, json.Unmarshal(data, &info)
+}
+
+func applicationDetails(registration Registration, metadata map[string]interface{}) portalv1.OAuthApplicationDetails {
+	scope, _ := metadata["scope"].(string)
+	scopes := strings.Fields(scope)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return portalv1.OAuthApplicationDetails{
+		ClientId:   registration.ClientId,
+		ClientName: &registration.ClientName,
+		Scopes:     scopes,
+	}
+}
+
+func notFoundError(clientId string) portalv1.Error {
+	return connector.NewPortal404Error(fmt.Sprintf("Application '%s' not found", clientId))
+}
+
+func unwrapError(resp *resty.Response, err error) portalv1.Error {
+	if err == nil {
+		if error, ok := resp.Error().(*RegistrationError); ok && error.Error != "" {
+			return portalv1.Error{
+				Code:    resp.StatusCode(),
+				Message: error.Error,
+				Reason:  error.Description,
+			}
+		}
+
+		return portalv1.Error{
+			Code:    resp.StatusCode(),
+			Message: resp.Status(),
+			Reason:  string(resp.Body()),
+		}
+	}
+
+	var respErr *resty.ResponseError
+	if ok := errors.As(err, &respErr); ok {
+		return portalv1.Error{
+			Code:    respErr.Response.StatusCode(),
+			Message: respErr.Response.Status(),
+			Reason:  respErr.Error(),
+		}
+	}
+
+	return connector.NewPortal500Error(err.Error())
+}
@@ -0,0 +1,419 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector/connectortest"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/dcr/server"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const (
+	issuer               = "https://idp.example.com"
+	registrationEndpoint = issuer + "/register"
+	initialAccessToken   = "initial-access-token"
+)
+
+// fakeRegistrationServer implements RFC 7591 and 7592 registration, issuing a new secret on each update without one.
+type fakeRegistrationServer struct {
+	*connectortest.FakeIdP[map[string]interface{}]
+
+	// keepSecrets makes updates keep the secret, as some providers do.
+	keepSecrets bool
+}
+
+func (f *fakeRegistrationServer) register(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "Bearer "+initialAccessToken {
+		return httpmock.NewJsonResponse(401, server.RegistrationError{Error: "invalid_token"})
+	}
+
+	var metadata map[string]interface{}
+	Expect(json.NewDecoder(req.Body).Decode(&metadata)).To(Succeed())
+	Expect(metadata["grant_types"]).To(ConsistOf("client_credentials"))
+
+	clientId := f.Next("client")
+	metadata["client_id"] = clientId
+	metadata["client_secret"] = f.Next("secret")
+	metadata["registration_access_token"] = f.Next("rat")
+	metadata["registration_client_uri"] = registrationEndpoint + "/" + clientId
+	f.Clients[clientId] = metadata
+
+	return httpmock.NewJsonResponse(201, metadata)
+}
+
+func (f *fakeRegistrationServer) manage(req *http.Request) (*http.Response, error) {
+	clientId := strings.TrimPrefix(req.URL.Path, "/register/")
+	metadata, ok := f.Clients[clientId]
+	if !ok {
+		return httpmock.NewJsonResponse(404, server.RegistrationError{Error: "invalid_client_id"})
+	}
+	if req.Header.Get("Authorization") != "Bearer "+metadata["registration_access_token"].(string) {
+		return httpmock.NewJsonResponse(401, server.RegistrationError{Error: "invalid_token"})
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return httpmock.NewJsonResponse(200, metadata)
+	case http.MethodDelete:
+		delete(f.Clients, clientId)
+		return httpmock.NewStringResponse(204, ""), nil
+	}
+
+	var update map[string]interface{}
+	Expect(json.NewDecoder(req.Body).Decode(&update)).To(Succeed())
+	Expect(update).NotTo(HaveKey("registration_access_token"))
+	Expect(update).NotTo(HaveKey("registration_client_uri"))
+	Expect(update["client_id"]).To(Equal(clientId))
+
+	if secret, ok := update["client_secret"]; ok {
+		Expect(secret).To(Equal(metadata["client_secret"]))
+	} else if f.keepSecrets {
+		update["client_secret"] = metadata["client_secret"]
+	} else {
+		update["client_secret"] = f.Next("secret")
+	}
+	// Issue a new registration access token on each update.
+	update["registration_access_token"] = f.Next("rat")
+	update["registration_client_uri"] = metadata["registration_client_uri"]
+	f.Clients[clientId] = update
+
+	return httpmock.NewJsonResponse(200, update)
+}
+
+var _ = Describe("Server", func() {
+	var (
+		s           *server.StrictServerHandler
+		store       *server.Store
+		storePath   string
+		restyClient *resty.Client
+		fake        *fakeRegistrationServer
+		ctx         context.Context
+	)
+
+	create := func(id string) portalv1.CreateOAuthApplication201JSONResponse {
+		resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+			Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+		return resp.(portalv1.CreateOAuthApplication201JSONResponse)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		storePath = filepath.Join(GinkgoT().TempDir(), "registrations.json")
+		store, err = server.OpenStore(storePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		restyClient = resty.New()
+		httpmock.ActivateNonDefault(restyClient.GetClient())
+
+		fake = &fakeRegistrationServer{FakeIdP: connectortest.NewFakeIdP[map[string]interface{}]()}
+		httpmock.RegisterResponder("POST", registrationEndpoint, fake.Responder(fake.register))
+		httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`^`+registrationEndpoint+`/`), fake.Responder(fake.manage))
+		httpmock.RegisterRegexpResponder("PUT", regexp.MustCompile(`^`+registrationEndpoint+`/`), fake.Responder(fake.manage))
+		httpmock.RegisterRegexpResponder("DELETE", regexp.MustCompile(`^`+registrationEndpoint+`/`), fake.Responder(fake.manage))
+
+		s = server.NewStrictServerHandler(&server.Options{
+			Issuer:             issuer,
+			InitialAccessToken: initialAccessToken,
+		}, restyClient, registrationEndpoint, store)
+	})
+
+	Context("Application", func() {
+		It("registers a client and can delete it by the id it was created with", func() {
+			created := create("portal-app")
+			Expect(created.ClientId).To(Equal("client-1"))
+			Expect(created.ClientSecret).To(Equal("secret-2"))
+			Expect(*created.ClientName).To(Equal("portal-app"))
+
+			registration, _ := store.Registration("portal-app")
+			Expect(registration.CreatedAt).NotTo(BeZero())
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+				Id: "portal-app",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(fake.Clients).To(BeEmpty())
+
+			_, ok := store.Registration(created.ClientId)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("can delete a client after a restart", func() {
+			create("portal-app")
+
+			restarted, err := server.OpenStore(storePath)
+			Expect(err).NotTo(HaveOccurred())
+			s = server.NewStrictServerHandler(&server.Options{Issuer: issuer}, restyClient, registrationEndpoint, restarted)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+				Id: "portal-app",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			Expect(fake.Clients).To(BeEmpty())
+		})
+
+		It("returns an error if the initial access token is rejected", func() {
+			restyClient := resty.New()
+			httpmock.ActivateNonDefault(restyClient.GetClient())
+			s = server.NewStrictServerHandler(&server.Options{Issuer: issuer}, restyClient, registrationEndpoint, store)
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "portal-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+		})
+
+		It("returns conflict for an id that a client was already registered for", func() {
+			created := create("portal-app")
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "portal-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(created.ClientId))
+			Expect(fake.Clients).To(HaveLen(1))
+		})
+
+		It("registers a single client for concurrent requests with the same id", func() {
+			responses := make(chan portalv1.CreateOAuthApplicationResponseObject, 2)
+			for range 2 {
+				go func() {
+					defer GinkgoRecover()
+					resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+						Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "portal-app"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					responses <- resp
+				}()
+			}
+
+			Expect([]portalv1.CreateOAuthApplicationResponseObject{<-responses, <-responses}).To(ConsistOf(
+				BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}),
+				BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}),
+			))
+			Expect(fake.Clients).To(HaveLen(1))
+		})
+
+		It("registers clients for other ids while a registration is in progress", func() {
+			release := make(chan struct{})
+			register := fake.Responder(fake.register)
+			httpmock.RegisterResponder("POST", registrationEndpoint, func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				Expect(err).NotTo(HaveOccurred())
+				if strings.Contains(string(body), "slow-app") {
+					<-release
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				return register(req)
+			})
+
+			slow := make(chan portalv1.CreateOAuthApplicationResponseObject, 1)
+			go func() {
+				defer GinkgoRecover()
+				resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+					Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "slow-app"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				slow <- resp
+			}()
+
+			Expect(*create("portal-app").ClientName).To(Equal("portal-app"))
+			close(release)
+			Expect(<-slow).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+		})
+
+		It("deletes the client it registered if its registration cannot be kept", func() {
+			unwritable, err := server.OpenStore(filepath.Join(GinkgoT().TempDir(), "missing", "registrations.json"))
+			Expect(err).NotTo(HaveOccurred())
+			s = server.NewStrictServerHandler(&server.Options{
+				Issuer:             issuer,
+				InitialAccessToken: initialAccessToken,
+			}, restyClient, registrationEndpoint, unwritable)
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "portal-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+			Expect(fake.Clients).To(BeEmpty())
+		})
+
+		It("returns not found for a client it did not create", func() {
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+				Id: "unknown-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
+		})
+
+		It("forgets a client that was already deleted in the provider", func() {
+			created := create("portal-app")
+			delete(fake.Clients, created.ClientId)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+				Id: created.ClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			_, ok := store.Registration(created.ClientId)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("Application details", func() {
+		It("lists the clients a page at a time", func() {
+			for i := range 3 {
+				create(fmt.Sprintf("portal-app-%d", i))
+			}
+
+			limit := 2
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{Limit: &limit},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+			Expect(page.Applications).To(HaveLen(2))
+			Expect(page.NextCursor).NotTo(BeNil())
+
+			resp, err = s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{Limit: &limit, Cursor: page.NextCursor},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			page = resp.(portalv1.ListOAuthApplications200JSONResponse)
+			Expect(page.Applications).To(HaveLen(1))
+			Expect(page.NextCursor).To(BeNil())
+		})
+
+		It("rotates the client secret", func() {
+			created := create("portal-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+				Id: created.ClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(created.ClientId))
+			Expect(rotated.ClientSecret).NotTo(BeEmpty())
+			Expect(rotated.ClientSecret).NotTo(Equal(created.ClientSecret))
+
+			// The new registration access token is kept.
+			registration, _ := store.Registration(created.ClientId)
+			Expect(registration.RegistrationAccessToken).To(Equal(fake.Clients[created.ClientId]["registration_access_token"]))
+		})
+
+		It("returns an error if the provider does not issue a new secret", func() {
+			fake.keepSecrets = true
+			created := create("portal-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+				Id: created.ClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret500JSONResponse{}))
+		})
+	})
+
+	Context("API Product access", func() {
+		var created portalv1.CreateOAuthApplication201JSONResponse
+
+		BeforeEach(func() {
+			created = create("portal-app")
+
+			for _, apiProduct := range []string{"tracks", "artists"} {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{Id: apiProduct},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+			}
+		})
+
+		getScopes := func() []string {
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: created.ClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			return resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes
+		}
+
+		It("grants and revokes access while keeping the client secret", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   created.ClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"tracks", "artists"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(getScopes()).To(ConsistOf("tracks", "artists"))
+
+			revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     created.ClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"tracks"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+			Expect(getScopes()).To(ConsistOf("artists"))
+
+			Expect(fake.Clients[created.ClientId]["client_secret"]).To(Equal(created.ClientSecret))
+		})
+
+		It("returns not found for an unknown API product", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   created.ClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"unknown-api"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns conflict for an existing API product and can delete it", func() {
+			resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+				Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "tracks"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+
+			deleted, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "tracks"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			Expect(store.APIProductExists("tracks")).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Options", func() {
+	It("requires a registrations file", func() {
+		opts := &server.Options{Issuer: "https://idp.example.com"}
+		Expect(opts.Validate()).To(MatchError(ContainSubstring("Registrations file is required")))
+
+		opts.RegistrationsFile = "/var/lib/idp-connect/registrations.json"
+		Expect(opts.Validate()).To(Succeed())
+	})
+
+	It("keeps registrations in memory only when asked to", func() {
+		opts := &server.Options{Issuer: "https://idp.example.com", InMemoryRegistrations: true}
+		Expect(opts.Validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/rotisserie/eris"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+const wellKnownOpenIDConfigPath = "/.well-known/openid-configuration"
+
+type Options struct {
+	connector.Options
+	Issuer             string
+	InitialAccessToken string
+	RegistrationsFile  string
+
+	// InMemoryRegistrations allows registrations to be kept only in memory, such as for demos, without a registrations
+	// file.
+	InMemoryRegistrations bool
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	o.Options.AddToFlags(flag)
+	flag.StringVar(&o.Issuer, "issuer", "", "Issuer URL of the OpenID provider, from which the registration endpoint is discovered")
+	flag.StringVar(&o.InitialAccessToken, "initial-access-token", "", "Initial access token to register clients with, if the provider requires one")
+	flag.StringVar(&o.RegistrationsFile, "registrations-file", "", "File in which the registration access tokens of created clients are kept, so that they can be managed after a restart")
+	flag.BoolVar(&o.InMemoryRegistrations, "in-memory-registrations", false, "Keep registrations only in memory, so that clients created before a restart cannot be managed, not even deleted")
+}
+
+func (o *Options) Validate() error {
+	if o.Issuer == "" {
+		return eris.New("Issuer is required")
+	}
+	if o.RegistrationsFile == "" && !o.InMemoryRegistrations {
+		return eris.New("Registrations file is required, as clients created before a restart cannot be deleted without their registrations")
+	}
+
+	// Try to get the initial access token from the environment if not provided via flag
+	if o.InitialAccessToken == "" {
+		o.InitialAccessToken = os.Getenv("DCR_INITIAL_ACCESS_TOKEN")
+	}
+
+	return nil
+}
+
+func ListenAndServe(ctx context.Context, opts *Options) error {
+	type OpenIDConfiguration struct {
+		RegistrationEndpoint string `json:"registration_endpoint"`
+	}
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	store, err := OpenStore(opts.RegistrationsFile)
+	if err != nil {
+		return err
+	}
+	if opts.RegistrationsFile == "" {
+		slog.Warn("Registrations are only kept in memory, clients created before a restart cannot be managed")
+	}
+
+	client := resty.New().SetTransport(opts.UpstreamTransport("dcr", nil))
+
+	openIDConfiguration, err := client.R().
+		SetContext(connector.WithUpstreamOperation(ctx, "GetOpenIDConfiguration")).
+		SetResult(OpenIDConfiguration{}).
+		Get(strings.TrimSuffix(opts.Issuer, "/") + wellKnownOpenIDConfigPath)
+	if err != nil {
+		return eris.Wrap(err, "OpenID configuration could not be discovered")
+	}
+	if openIDConfiguration.IsError() {
+		return eris.Errorf("OpenID configuration could not be discovered: %s", openIDConfiguration.Status())
+	}
+
+	registrationEndpoint := openIDConfiguration.Result().(*OpenIDConfiguration).RegistrationEndpoint
+	if len(registrationEndpoint) == 0 {
+		return eris.New("Registration endpoint was not provided by the issuer")
+	}
+
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, client, registrationEndpoint, store))
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// Registration is what is needed to manage a client after it has been registered, as RFC 7592 only lets a client be
+// read, updated or deleted with the registration access token issued when it was created.
+type Registration struct {
+	ClientId                string `json:"clientId"`
+	ClientName              string `json:"clientName,omitempty"`
+	RegistrationAccessToken string `json:"registrationAccessToken"`
+	RegistrationClientURI   string `json:"registrationClientUri"`
+
+	// CreatedAt is when the connector registered the client.
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// Store holds the registrations of the clients created by the connector, and the API products that clients can be
+// granted access to. If it has a file, the store is loaded from it and saved to it after each change, so that clients
+// can still be managed after a restart.
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data storeData
+}
+
+type storeData struct {
+	Registrations map[string]Registration `json:"registrations"`
+	APIProducts   map[string]string       `json:"apiProducts"`
+}
+
+// OpenStore loads the store from the file, if it exists. An empty path keeps the store in memory only.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: storeData{
+			Registrations: map[string]Registration{},
+			APIProducts:   map[string]string{},
+		},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, eris.Wrapf(err, "could not read registrations from %s", path)
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return nil, eris.Wrapf(err, "could not parse registrations in %s", path)
+	}
+	if s.data.Registrations == nil {
+		s.data.Registrations = map[string]Registration{}
+	}
+	if s.data.APIProducts == nil {
+		s.data.APIProducts = map[string]string{}
+	}
+
+	return s, nil
+}
+
+// Registration returns the registration of the client with the ID Portal gave it, which is its name, or else with the
+// client ID, if it was created by the connector.
+func (s *Store) Registration(id string) (Registration, bool) {
+	if r, ok := s.RegistrationByName(id); ok {
+		return r, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.data.Registrations[id]
+	return r, ok
+}
+
+// RegistrationByName returns the registration of the client with the name, if it was created by the connector.
+func (s *Store) RegistrationByName(name string) (Registration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.data.Registrations {
+		if r.ClientName == name {
+			return r, true
+		}
+	}
+
+	return Registration{}, false
+}
+
+// Registrations returns up to limit registrations, ordered by client ID, starting after the given client ID.
+func (s *Store) Registrations(after string, limit int) []Registration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var registrations []Registration
+	for _, r := range s.data.Registrations {
+		if r.ClientId > after {
+			registrations = append(registrations, r)
+		}
+	}
+	slices.SortFunc(registrations, func(a, b Registration) int {
+		return strings.Compare(a.ClientId, b.ClientId)
+	})
+
+	return registrations[:min(limit, len(registrations))]
+}
+
+// PutRegistration adds or replaces the registration of a client.
+func (s *Store) PutRegistration(r Registration) error {
+	return s.update(func(data *storeData) {
+		data.Registrations[r.ClientId] = r
+	})
+}
+
+// DeleteRegistration removes the registration of a client.
+func (s *Store) DeleteRegistration(clientId string) error {
+	return s.update(func(data *storeData) {
+		delete(data.Registrations, clientId)
+	})
+}
+
+// APIProductExists reports whether the API product has been created.
+func (s *Store) APIProductExists(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.data.APIProducts[id]
+	return ok
+}
+
+// PutAPIProduct adds or replaces an API product.
+func (s *Store) PutAPIProduct(id, description string) error {
+	return s.update(func(data *storeData) {
+		data.APIProducts[id] = description
+	})
+}
+
+// DeleteAPIProduct removes an API product.
+func (s *Store) DeleteAPIProduct(id string) error {
+	return s.update(func(data *storeData) {
+		delete(data.APIProducts, id)
+	})
+}
+
+// update applies the change and saves the store. If it cannot be saved, the change is undone.
+func (s *Store) update(change func(*storeData)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := storeData{
+		Registrations: maps.Clone(s.data.Registrations),
+		APIProducts:   maps.Clone(s.data.APIProducts),
+	}
+	change(&s.data)
+
+	if err := s.save(); err != nil {
+		s.data = previous
+		return err
+	}
+
+	return nil
+}
+
+// save writes the store to a temporary file and renames it over the file, so that the file is never left half-written.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return eris.Wrap(err, "could not serialize registrations")
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return eris.Wrapf(err, "could not write registrations to %s", tmp)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return eris.Wrapf(err, "could not write registrations to %s", s.path)
+	}
+
+	return nil
+}
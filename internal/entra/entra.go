@@ -0,0 +1,28 @@
+package entra
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/entra/server"
+)
+
+func Command() *cobra.Command {
+	serverOpts := &server.Options{}
+
+	cmd := &cobra.Command{
+		Short: "Start the Microsoft Entra ID IDP connector",
+		Use:   "entra",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return serverOpts.ConfigureLogging()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.ListenAndServe(cmd.Context(), serverOpts)
+		},
+		// option to silence usage when an error occurs.
+		SilenceUsage: true,
+	}
+
+	serverOpts.AddToFlags(cmd.Flags())
+
+	return cmd
+}
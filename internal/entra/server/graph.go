@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+//go:generate mockgen -destination=mock/graph_client.go . GraphClient
+
+// GraphClient is the part of the Microsoft Graph API that the connector uses.
+type GraphClient interface {
+	// CreateApplication creates an application registration.
+	CreateApplication(ctx context.Context, application Application) (*Application, error)
+
+	// ListApplications lists up to top application registrations.
+	ListApplications(ctx context.Context, top int) ([]Application, error)
+
+	// FindApplications lists the application registrations with the given display name.
+	FindApplications(ctx context.Context, displayName string) ([]Application, error)
+
+	// GetApplication gets the application registration with the given application (client) ID.
+	GetApplication(ctx context.Context, appId string) (*Application, error)
+
+	// DeleteApplication deletes the application registration with the given application (client) ID.
+	DeleteApplication(ctx context.Context, appId string) error
+
+	// CreateServicePrincipal creates the service principal of an application in the tenant, which is needed for the
+	// application to obtain tokens.
+	CreateServicePrincipal(ctx context.Context, appId string) (*ServicePrincipal, error)
+
+	// AddPassword adds a client secret to the application registration with the given object ID.
+	AddPassword(ctx context.Context, objectId string, credential PasswordCredential) (*PasswordCredential, error)
+}
+
+type Application struct {
+	Id             string `json:"id,omitempty"`
+	AppId          string `json:"appId,omitempty"`
+	DisplayName    string `json:"displayName,omitempty"`
+	SignInAudience string `json:"signInAudience,omitempty"`
+
+	// Tags of the application, which include the ownership tags of applications that IdP Connect created.
+	Tags []string `json:"tags,omitempty"`
+}
+
+type ServicePrincipal struct {
+	Id    string `json:"id,omitempty"`
+	AppId string `json:"appId,omitempty"`
+}
+
+type PasswordCredential struct {
+	KeyId       string `json:"keyId,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	SecretText  string `json:"secretText,omitempty"`
+}
+
+// GraphError is an error response of the Graph API.
+type GraphError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *GraphError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+type graphErrorResponse struct {
+	Error GraphError `json:"error"`
+}
+
+// graphClient calls the Graph API with a client that authenticates its requests.
+type graphClient struct {
+	restClient *resty.Client
+	endpoint   string
+}
+
+func newGraphClient(httpClient *http.Client, endpoint string) *graphClient {
+	return &graphClient{
+		restClient: resty.NewWithClient(httpClient).SetError(&graphErrorResponse{}),
+		endpoint:   endpoint + "/v1.0",
+	}
+}
+
+var _ GraphClient = &graphClient{}
+
+func (c *graphClient) CreateApplication(ctx context.Context, application Application) (*Application, error) {
+	var created Application
+	resp, err := c.upstream(ctx, "CreateApplication").
+		SetBody(application).
+		SetResult(&created).
+		Post(c.endpoint + "/applications")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (c *graphClient) ListApplications(ctx context.Context, top int) ([]Application, error) {
+	var page struct {
+		Value []Application `json:"value"`
+	}
+	resp, err := c.upstream(ctx, "ListApplications").
+		SetQueryParam("$top", fmt.Sprint(top)).
+		SetResult(&page).
+		Get(c.endpoint + "/applications")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return page.Value, nil
+}
+
+func (c *graphClient) FindApplications(ctx context.Context, displayName string) ([]Application, error) {
+	var page struct {
+		Value []Application `json:"value"`
+	}
+	resp, err := c.upstream(ctx, "FindApplications").
+		SetQueryParam("$filter", "displayName eq '"+strings.ReplaceAll(displayName, "'", "''")+"'").
+		SetResult(&page).
+		Get(c.endpoint + "/applications")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return page.Value, nil
+}
+
+func (c *graphClient) GetApplication(ctx context.Context, appId string) (*Application, error) {
+	var app Application
+	resp, err := c.upstream(ctx, "GetApplication").
+		SetPathParam("appId", appId).
+		SetResult(&app).
+		Get(c.endpoint + "/applications(appId='{appId}')")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return &app, nil
+}
+
+func (c *graphClient) DeleteApplication(ctx context.Context, appId string) error {
+	resp, err := c.upstream(ctx, "DeleteApplication").
+		SetPathParam("appId", appId).
+		Delete(c.endpoint + "/applications(appId='{appId}')")
+
+	return graphError(resp, err)
+}
+
+func (c *graphClient) CreateServicePrincipal(ctx context.Context, appId string) (*ServicePrincipal, error) {
+	var created ServicePrincipal
+	resp, err := c.upstream(ctx, "CreateServicePrincipal").
+		SetBody(ServicePrincipal{AppId: appId}).
+		SetResult(&created).
+		Post(c.endpoint + "/servicePrincipals")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (c *graphClient) AddPassword(ctx context.Context, objectId string, credential PasswordCredential) (*PasswordCredential, error) {
+	var added PasswordCredential
+	resp, err := c.upstream(ctx, "AddPassword").
+		SetPathParam("id", objectId).
+		SetBody(map[string]PasswordCredential{"passwordCredential": credential}).
+		SetResult(&added).
+		Post(c.endpoint + "/applications/{id}/addPassword")
+
+	if err := graphError(resp, err); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// upstream returns a request to the Graph API that is labelled with the given operation in the upstream metrics.
+func (c *graphClient) upstream(ctx context.Context, operation string) *resty.Request {
+	return c.restClient.R().SetContext(connector.WithUpstreamOperation(ctx, operation))
+}
+
+// graphError returns the error of a failed request, as a *GraphError if the Graph API responded with one.
+func graphError(resp *resty.Response, err error) error {
+	if err != nil || !resp.IsError() {
+		return err
+	}
+
+	graphErr := &GraphError{StatusCode: resp.StatusCode(), Code: resp.Status()}
+	if body, ok := resp.Error().(*graphErrorResponse); ok && body.Error.Code != "" {
+		graphErr.Code = body.Error.Code
+		graphErr.Message = body.Error.Message
+	}
+
+	return graphErr
+}
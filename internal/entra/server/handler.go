@@ -0,0 +1,274 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const (
+	// singleTenantAudience only lets accounts of the tenant sign in to the application, which is all that the client
+	// credentials grant needs.
+	singleTenantAudience = "AzureADMyOrg"
+
+	// passwordDisplayName is the name of the client secrets created by the connector.
+	passwordDisplayName = "Gloo Portal"
+)
+
+// servicePrincipalRetries retries creating the service principal of a new application for a few seconds, while Graph
+// has not yet replicated the application.
+var servicePrincipalRetries = connector.RetryPolicy{
+	MaxAttempts: 6,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+type StrictServerHandler struct {
+	graphClient GraphClient
+	forceDelete bool
+}
+
+func NewStrictServerHandler(opts *Options, graphClient GraphClient) *StrictServerHandler {
+	return &StrictServerHandler{
+		graphClient: graphClient,
+		forceDelete: opts.ForceDelete,
+	}
+}
+
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "entra"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that application registrations can be read with the configured credentials.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	_, err := s.graphClient.ListApplications(ctx, 1)
+	return err
+}
+
+// CreateOAuthApplication registers an application in Entra ID with a service principal, so that it can use the
+// client credentials grant, and a client secret.
+func (s *StrictServerHandler) CreateOAuthApplication(
+	ctx context.Context,
+	request portalv1.CreateOAuthApplicationRequestObject,
+) (portalv1.CreateOAuthApplicationResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	existing, err := s.graphClient.FindApplications(ctx, request.Body.Id)
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapGraphError(err)), nil
+	}
+	if len(existing) > 0 {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, existing[0].AppId)), nil
+	}
+
+	app, err := s.graphClient.CreateApplication(ctx, Application{
+		DisplayName:    request.Body.Id,
+		SignInAudience: singleTenantAudience,
+		Tags:           ownershipTags(connector.NewOwnership(request.Body.Id)),
+	})
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapGraphError(err)), nil
+	}
+
+	secret, err := s.completeRegistration(ctx, app)
+	if err != nil {
+		// Do not leave a registration behind that Portal does not know about.
+		if deleteErr := s.graphClient.DeleteApplication(ctx, app.AppId); deleteErr != nil {
+			err = errors.Join(err, deleteErr)
+		}
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapGraphError(err)), nil
+	}
+
+	return portalv1.CreateOAuthApplication201JSONResponse{
+		ClientId:     app.AppId,
+		ClientName:   &app.DisplayName,
+		ClientSecret: secret,
+	}, nil
+}
+
+// completeRegistration creates the service principal of a new application and returns a new client secret for it.
+func (s *StrictServerHandler) completeRegistration(ctx context.Context, app *Application) (string, error) {
+	if err := s.createServicePrincipal(ctx, app.AppId); err != nil {
+		return "", err
+	}
+
+	password, err := s.graphClient.AddPassword(ctx, app.Id, PasswordCredential{DisplayName: passwordDisplayName})
+	if err != nil {
+		return "", err
+	}
+
+	return password.SecretText, nil
+}
+
+// createServicePrincipal creates the service principal of a new application. Graph is eventually consistent, so it may
+// not find an application that was just created, in which case the call is retried with backoff.
+func (s *StrictServerHandler) createServicePrincipal(ctx context.Context, appId string) error {
+	for retry := 1; ; retry++ {
+		_, err := s.graphClient.CreateServicePrincipal(ctx, appId)
+
+		var graphErr *GraphError
+		if !errors.As(err, &graphErr) || graphErr.StatusCode != http.StatusNotFound {
+			return err
+		}
+
+		delay, ok := servicePrincipalRetries.Delay(retry, nil)
+		if !ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// DeleteOAuthApplication deletes an application registration in Entra ID by the ID Portal gave it, which is its
+// display name, or else by application (client) ID. Registrations without the ownership tags are only deleted when
+// deletes are forced.
+func (s *StrictServerHandler) DeleteOAuthApplication(
+	ctx context.Context,
+	request portalv1.DeleteOAuthApplicationRequestObject,
+) (portalv1.DeleteOAuthApplicationResponseObject, error) {
+	if len(request.Id) == 0 {
+		return portalv1.DeleteOAuthApplication404JSONResponse(connector.NewPortal404Error("client ID is required")), nil
+	}
+
+	apps, err := s.graphClient.FindApplications(ctx, request.Id)
+	if err != nil {
+		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapGraphError(err)), nil
+	}
+
+	var app *Application
+	switch len(apps) {
+	case 0:
+		if app, err = s.graphClient.GetApplication(ctx, request.Id); err != nil {
+			switch graphErr := unwrapGraphError(err); graphErr.Code {
+			case 404:
+				return portalv1.DeleteOAuthApplication404JSONResponse(graphErr), nil
+			default:
+				return portalv1.DeleteOAuthApplication500JSONResponse(graphErr), nil
+			}
+		}
+	case 1:
+		app = &apps[0]
+	default:
+		// Display names are not unique in Entra ID, so one that Portal did not create may have the same name.
+		return portalv1.DeleteOAuthApplication500JSONResponse(connector.NewPortal500Error("more than one matching application found for [" + request.Id + "]")), nil
+	}
+
+	if !s.forceDelete && !slices.Contains(app.Tags, connector.ManagedBy) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
+	}
+
+	if err := s.graphClient.DeleteApplication(ctx, app.AppId); err != nil {
+		switch graphErr := unwrapGraphError(err); graphErr.Code {
+		case 404:
+			return portalv1.DeleteOAuthApplication404JSONResponse(graphErr), nil
+		default:
+			return portalv1.DeleteOAuthApplication500JSONResponse(graphErr), nil
+		}
+	}
+
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications is not supported by the Entra ID connector.
+func (s *StrictServerHandler) ListOAuthApplications(
+	_ context.Context,
+	_ portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	return portalv1.ListOAuthApplications500JSONResponse(notSupported("listing applications")), nil
+}
+
+// GetOAuthApplication is not supported by the Entra ID connector.
+func (s *StrictServerHandler) GetOAuthApplication(
+	_ context.Context,
+	_ portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	return portalv1.GetOAuthApplication500JSONResponse(notSupported("getting applications")), nil
+}
+
+// RotateOAuthApplicationSecret is not supported by the Entra ID connector.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	_ context.Context,
+	_ portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	return portalv1.RotateOAuthApplicationSecret500JSONResponse(notSupported("rotating secrets")), nil
+}
+
+// GrantAPIProductAccess is not supported by the Entra ID connector.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	_ context.Context,
+	_ portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	return portalv1.GrantAPIProductAccess500JSONResponse(notSupported("API products")), nil
+}
+
+// RevokeAPIProductAccess is not supported by the Entra ID connector.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	_ context.Context,
+	_ portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	return portalv1.RevokeAPIProductAccess500JSONResponse(notSupported("API products")), nil
+}
+
+// CreateAPIProduct is not supported by the Entra ID connector.
+func (s *StrictServerHandler) CreateAPIProduct(
+	_ context.Context,
+	_ portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	return portalv1.CreateAPIProduct500JSONResponse(notSupported("API products")), nil
+}
+
+// DeleteAPIProduct is not supported by the Entra ID connector.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	_ context.Context,
+	_ portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	return portalv1.DeleteAPIProduct500JSONResponse(notSupported("API products")), nil
+}
+
+// ownershipTags returns the tags that mark an application registration as created by IdP Connect. Entra ID keeps
+// tags as plain strings, so the Portal id and creation time are tags prefixed with the marker.
+func ownershipTags(o connector.Ownership) []string {
+	return []string{
+		connector.ManagedBy,
+		connector.ManagedBy + ":" + connector.PortalIdKey + "=" + o.PortalId,
+		connector.ManagedBy + ":" + connector.CreatedAtKey + "=" + o.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func notSupported(feature string) portalv1.Error {
+	return connector.NewNotSupportedError("Entra ID", feature)
+}
+
+func unwrapGraphError(err error) portalv1.Error {
+	var graphErr *GraphError
+	if errors.As(err, &graphErr) {
+		return portalv1.Error{
+			Code:    graphErr.StatusCode,
+			Message: graphErr.Code,
+			Reason:  err.Error(),
+		}
+	}
+
+	return connector.NewPortal500Error(err.Error())
+}
@@ -0,0 +1,222 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/entra/server"
+	mock_server "github.com/solo-io/gloo-portal-idp-connect/internal/entra/server/mock"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+var _ = Describe("Server", func() {
+	const (
+		objectId = "5f3c1a8e-0000-0000-0000-000000000001"
+		appId    = "0b9d2c4f-0000-0000-0000-000000000002"
+		secret   = "generated-secret"
+	)
+
+	var (
+		s               *server.StrictServerHandler
+		mockCtrl        *gomock.Controller
+		mockGraphClient *mock_server.MockGraphClient
+		ctx             context.Context
+
+		notFound = &server.GraphError{StatusCode: 404, Code: "Request_ResourceNotFound", Message: "Resource does not exist"}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockGraphClient = mock_server.NewMockGraphClient(mockCtrl)
+		ctx = context.Background()
+
+		s = server.NewStrictServerHandler(&server.Options{}, mockGraphClient)
+	})
+
+	create := func(id string) (portalv1.CreateOAuthApplicationResponseObject, error) {
+		return s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+			Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: id},
+		})
+	}
+
+	When("creating an application", func() {
+		BeforeEach(func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{}, nil)
+			mockGraphClient.EXPECT().CreateApplication(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, app server.Application) (*server.Application, error) {
+					Expect(app.DisplayName).To(Equal("my-app"))
+					Expect(app.SignInAudience).To(Equal("AzureADMyOrg"))
+					Expect(app.Tags).To(ContainElements("gloo-portal-idp-connect", "gloo-portal-idp-connect:portal_id=my-app"))
+					Expect(app.Tags).To(ContainElement(HavePrefix("gloo-portal-idp-connect:created_at=")))
+
+					return &server.Application{Id: objectId, AppId: appId, DisplayName: "my-app", Tags: app.Tags}, nil
+				})
+		})
+
+		It("registers the application with a service principal and returns its secret", func() {
+			mockGraphClient.EXPECT().CreateServicePrincipal(ctx, appId).Return(&server.ServicePrincipal{AppId: appId}, nil)
+			mockGraphClient.EXPECT().AddPassword(ctx, objectId, gomock.Any()).Return(&server.PasswordCredential{SecretText: secret}, nil)
+
+			resp, err := create("my-app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+
+			app := resp.(portalv1.CreateOAuthApplication201JSONResponse)
+			Expect(app.ClientId).To(Equal(appId))
+			Expect(*app.ClientName).To(Equal("my-app"))
+			Expect(app.ClientSecret).To(Equal(secret))
+		})
+
+		It("retries creating the service principal until Graph finds the new application", func() {
+			gomock.InOrder(
+				mockGraphClient.EXPECT().CreateServicePrincipal(ctx, appId).Return(nil, &server.GraphError{StatusCode: 404, Code: "Request_ResourceNotFound"}),
+				mockGraphClient.EXPECT().CreateServicePrincipal(ctx, appId).Return(&server.ServicePrincipal{AppId: appId}, nil),
+			)
+			mockGraphClient.EXPECT().AddPassword(ctx, objectId, gomock.Any()).Return(&server.PasswordCredential{SecretText: secret}, nil)
+
+			resp, err := create("my-app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+		})
+
+		It("deletes the registration if the service principal cannot be created", func() {
+			mockGraphClient.EXPECT().CreateServicePrincipal(ctx, appId).Return(nil, &server.GraphError{StatusCode: 403, Code: "Authorization_RequestDenied"})
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(nil)
+
+			resp, err := create("my-app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication500JSONResponse).Reason).To(ContainSubstring("Authorization_RequestDenied"))
+		})
+
+		It("deletes the registration if the secret cannot be added", func() {
+			mockGraphClient.EXPECT().CreateServicePrincipal(ctx, appId).Return(&server.ServicePrincipal{AppId: appId}, nil)
+			mockGraphClient.EXPECT().AddPassword(ctx, objectId, gomock.Any()).Return(nil, errors.New("connection reset"))
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(errors.New("connection refused"))
+
+			resp, err := create("my-app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+
+			reason := resp.(portalv1.CreateOAuthApplication500JSONResponse).Reason
+			Expect(reason).To(ContainSubstring("connection reset"))
+			Expect(reason).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	It("returns 400 when creating an application without an id", func() {
+		resp, err := create("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication400JSONResponse{}))
+	})
+
+	It("returns 409 when an application is already registered with the id", func() {
+		mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{{Id: objectId, AppId: appId, DisplayName: "my-app"}}, nil)
+
+		resp, err := create("my-app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+		Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(appId))
+	})
+
+	It("returns 500 when the application cannot be registered", func() {
+		mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{}, nil)
+		mockGraphClient.EXPECT().CreateApplication(ctx, gomock.Any()).Return(nil, &server.GraphError{StatusCode: 400, Code: "Request_BadRequest"})
+
+		resp, err := create("my-app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication500JSONResponse{}))
+	})
+
+	When("deleting an application", func() {
+		owned := server.Application{Id: objectId, AppId: appId, DisplayName: "my-app", Tags: []string{"gloo-portal-idp-connect"}}
+
+		It("deletes the registration by the id it was created with", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{owned}, nil)
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(nil)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+		})
+
+		It("deletes the registration by application ID", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, appId).Return([]server.Application{}, nil)
+			mockGraphClient.EXPECT().GetApplication(ctx, appId).Return(&owned, nil)
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(nil)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: appId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+		})
+
+		It("returns 403 for a registration that IdP Connect did not create", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{{Id: objectId, AppId: appId, DisplayName: "my-app"}}, nil)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+		})
+
+		It("deletes a registration that IdP Connect did not create when forced to", func() {
+			opts := &server.Options{}
+			opts.ForceDelete = true
+			s = server.NewStrictServerHandler(opts, mockGraphClient)
+			mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{{Id: objectId, AppId: appId, DisplayName: "my-app"}}, nil)
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(nil)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+		})
+
+		It("returns 404 for an unknown application", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, "unknown").Return([]server.Application{}, nil)
+			mockGraphClient.EXPECT().GetApplication(ctx, "unknown").Return(nil, notFound)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
+		})
+
+		It("returns 500 when more than one registration has the id as its name", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, "my-app").Return([]server.Application{
+				{AppId: appId, DisplayName: "my-app"},
+				{AppId: "other", DisplayName: "my-app"},
+			}, nil)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication500JSONResponse{}))
+		})
+
+		It("returns 500 when Graph fails", func() {
+			mockGraphClient.EXPECT().FindApplications(ctx, appId).Return([]server.Application{}, nil)
+			mockGraphClient.EXPECT().GetApplication(ctx, appId).Return(&owned, nil)
+			mockGraphClient.EXPECT().DeleteApplication(ctx, appId).Return(&server.GraphError{StatusCode: 503, Code: "ServiceUnavailable"})
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: appId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication500JSONResponse{}))
+		})
+	})
+
+	It("reports that API products are not supported", func() {
+		resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+			Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "petstore"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct500JSONResponse{}))
+		Expect(resp.(portalv1.CreateAPIProduct500JSONResponse).Reason).To(ContainSubstring("does not support"))
+	})
+
+	It("is ready when application registrations can be listed", func() {
+		mockGraphClient.EXPECT().ListApplications(ctx, 1).Return([]server.Application{}, nil)
+
+		Expect(s.Ready(ctx)).To(Succeed())
+	})
+})
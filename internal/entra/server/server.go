@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/spf13/pflag"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+type Options struct {
+	connector.Options
+	TenantId         string
+	MgmtClientId     string
+	MgmtClientSecret string
+	AuthorityHost    string
+	GraphEndpoint    string
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	o.Options.AddToFlags(flag)
+	flag.StringVar(&o.TenantId, "tenant-id", "", "ID of the Microsoft Entra tenant in which applications are registered")
+	flag.StringVar(&o.MgmtClientId, "client-id", "", "Application (client) ID of the application that is authorised to manage application registrations")
+	flag.StringVar(&o.MgmtClientSecret, "client-secret", "", "Client secret of the application that is authorised to manage application registrations")
+	flag.StringVar(&o.AuthorityHost, "authority-host", "https://login.microsoftonline.com", "Microsoft Entra authority to obtain Graph tokens from")
+	flag.StringVar(&o.GraphEndpoint, "graph-endpoint", "https://graph.microsoft.com", "Microsoft Graph endpoint, for national clouds")
+}
+
+func (o *Options) Validate() error {
+	if o.TenantId == "" {
+		return eris.New("Tenant ID is required")
+	}
+	if o.MgmtClientId == "" {
+		return eris.New("Client ID is required")
+	}
+
+	// Try to get the client secret from the environment if not provided via flag
+	if o.MgmtClientSecret == "" {
+		if envSecret := os.Getenv("AZURE_CLIENT_SECRET"); envSecret != "" {
+			o.MgmtClientSecret = envSecret
+		} else {
+			return eris.New("Client secret is required (via --client-secret flag or AZURE_CLIENT_SECRET environment variable)")
+		}
+	}
+
+	return nil
+}
+
+func ListenAndServe(ctx context.Context, opts *Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	graphEndpoint := strings.TrimSuffix(opts.GraphEndpoint, "/")
+	credentials := clientcredentials.Config{
+		ClientID:     opts.MgmtClientId,
+		ClientSecret: opts.MgmtClientSecret,
+		TokenURL:     strings.TrimSuffix(opts.AuthorityHost, "/") + "/" + opts.TenantId + "/oauth2/v2.0/token",
+		Scopes:       []string{graphEndpoint + "/.default"},
+	}
+
+	// The token source caches the Graph token until it expires. Tokens are requested with the context it is created
+	// with, rather than that of the request that needs one.
+	transport := opts.UpstreamTransport("entra", nil)
+	tokenCtx := context.WithValue(
+		connector.WithUpstreamOperation(context.Background(), "GetToken"),
+		oauth2.HTTPClient,
+		&http.Client{Transport: transport},
+	)
+	graph := newGraphClient(&http.Client{
+		Transport: &oauth2.Transport{Source: credentials.TokenSource(tokenCtx), Base: transport},
+	}, graphEndpoint)
+
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, graph))
+}
@@ -0,0 +1,28 @@
+package hydra
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/hydra/server"
+)
+
+func Command() *cobra.Command {
+	serverOpts := &server.Options{}
+
+	cmd := &cobra.Command{
+		Short: "Start the Ory Hydra IDP connector",
+		Use:   "hydra",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return serverOpts.ConfigureLogging()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.ListenAndServe(cmd.Context(), serverOpts)
+		},
+		// option to silence usage when an error occurs.
+		SilenceUsage: true,
+	}
+
+	serverOpts.AddToFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,579 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// hydraDefaultScopes are the scopes Hydra gives a client that is registered without any, which are not API products.
+var hydraDefaultScopes = []string{"openid", "offline", "offline_access"}
+
+type StrictServerHandler struct {
+	restClient  resty.Client
+	clients     string
+	forceDelete bool
+}
+
+type HydraClient struct {
+	ClientId                string   `json:"client_id,omitempty"`
+	ClientName              string   `json:"client_name,omitempty"`
+	ClientSecret            string   `json:"client_secret,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+	Scope                   string   `json:"scope,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+
+	// Metadata of the client, which includes the ownership metadata of clients that IdP Connect created. Hydra
+	// accepts any JSON object, so clients created by others may have values that are not strings.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// HydraPatch is a JSON Patch operation on a client.
+type HydraPatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+type HydraError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+	StatusCode  int    `json:"status_code,omitempty"`
+}
+
+func NewStrictServerHandler(opts *Options, restyClient *resty.Client) *StrictServerHandler {
+	restyClient.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
+		r.SetError(&HydraError{})
+		return nil
+	})
+
+	return &StrictServerHandler{
+		restClient:  *restyClient,
+		clients:     strings.TrimSuffix(opts.AdminURL, "/") + "/admin/clients",
+		forceDelete: opts.ForceDelete,
+	}
+}
+
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "hydra"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that clients can be listed with the admin API.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	resp, err := s.upstream(ctx, "ListOAuth2Clients").
+		SetQueryParam("page_size", "1").
+		Get(s.clients)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		portalErr := unwrapError(resp, nil)
+		return errors.New(portalErr.Message + ": " + portalErr.Reason)
+	}
+
+	return nil
+}
+
+// CreateOAuthApplication creates a client in Hydra that can use the client credentials grant.
+func (s *StrictServerHandler) CreateOAuthApplication(
+	ctx context.Context,
+	request portalv1.CreateOAuthApplicationRequestObject,
+) (portalv1.CreateOAuthApplicationResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	existing, resp, err := s.findClientByName(ctx, request.Body.Id)
+	if err != nil || resp.IsError() {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapError(resp, err)), nil
+	}
+	if existing != nil {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, existing.ClientId)), nil
+	}
+
+	metadata := map[string]interface{}{}
+	for key, value := range connector.NewOwnership(request.Body.Id).Metadata() {
+		metadata[key] = value
+	}
+
+	var createdClient HydraClient
+	resp, err = s.upstream(ctx, "CreateOAuth2Client").
+		SetBody(HydraClient{
+			ClientName:              request.Body.Id,
+			GrantTypes:              []string{"client_credentials"},
+			TokenEndpointAuthMethod: "client_secret_basic",
+			Metadata:                metadata,
+		}).
+		SetResult(&createdClient).
+		Post(s.clients)
+
+	if err != nil || resp.IsError() {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	return portalv1.CreateOAuthApplication201JSONResponse{
+		ClientId:     createdClient.ClientId,
+		ClientName:   &createdClient.ClientName,
+		ClientSecret: createdClient.ClientSecret,
+	}, nil
+}
+
+// DeleteOAuthApplication deletes a client in Hydra by the ID Portal gave it, or by client ID. Clients without the
+// ownership metadata are only deleted when deletes are forced.
+func (s *StrictServerHandler) DeleteOAuthApplication(
+	ctx context.Context,
+	request portalv1.DeleteOAuthApplicationRequestObject,
+) (portalv1.DeleteOAuthApplicationResponseObject, error) {
+	if len(request.Id) == 0 {
+		return portalv1.DeleteOAuthApplication404JSONResponse(connector.NewPortal404Error("client ID is required")), nil
+	}
+
+	client, resp, err := s.findClient(ctx, request.Id)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !s.forceDelete && !isOwned(client) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
+	}
+
+	resp, err = s.upstream(ctx, "DeleteOAuth2Client").
+		SetPathParam("id", client.ClientId).
+		Delete(s.clients + "/{id}")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications lists the clients in Hydra that the connector created, along with the API products they can
+// access. The cursor is Hydra's own page token.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	clients, next, resp, err := s.listClients(ctx, connector.PageLimit(request.Params.Limit), request.Params.Cursor)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 400:
+			return portalv1.ListOAuthApplications400JSONResponse(portalErr), nil
+		default:
+			return portalv1.ListOAuthApplications500JSONResponse(portalErr), nil
+		}
+	}
+
+	page := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+		NextCursor:   next,
+	}
+	for _, client := range clients {
+		if !isOwned(&client) {
+			continue
+		}
+
+		page.Applications = append(page.Applications, applicationDetails(client))
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication gets a client in Hydra by the ID Portal gave it, or by client ID.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	client, resp, err := s.findClient(ctx, request.Id)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.GetOAuthApplication404JSONResponse(portalErr), nil
+		default:
+			return portalv1.GetOAuthApplication500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(applicationDetails(*client)), nil
+}
+
+// RotateOAuthApplicationSecret replaces the secret of a client in Hydra with a new random one. Hydra only lets the
+// secret be set by replacing the whole client, so the client is read and written back with the new secret.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	found, resp, err := s.findClient(ctx, request.Id)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !isOwned(found) {
+		return portalv1.RotateOAuthApplicationSecret403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	var client map[string]interface{}
+	resp, err = s.upstream(ctx, "GetOAuth2Client").
+		SetPathParam("id", found.ClientId).
+		SetResult(&client).
+		Get(s.clients + "/{id}")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+	client["client_secret"] = secret
+
+	var updatedClient HydraClient
+	resp, err = s.upstream(ctx, "SetOAuth2Client").
+		SetPathParam("id", found.ClientId).
+		SetBody(client).
+		SetResult(&updatedClient).
+		Put(s.clients + "/{id}")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     updatedClient.ClientId,
+		ClientName:   &updatedClient.ClientName,
+		ClientSecret: secret,
+	}, nil
+}
+
+// GrantAPIProductAccess adds the API products to the scope of the client.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, resp, err := s.findClient(ctx, request.Id)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.GrantAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.GrantAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !isOwned(client) {
+		return portalv1.GrantAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	resp, err = s.updateScope(ctx, client.ClientId, func(scope []string) []string {
+		for _, apiProduct := range request.Body.ApiProducts {
+			if !slices.Contains(scope, apiProduct) {
+				scope = append(scope, apiProduct)
+			}
+		}
+		return scope
+	})
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.GrantAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.GrantAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the API products from the scope of the client.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	client, resp, err := s.findClient(ctx, request.Id)
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RevokeAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RevokeAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	if !isOwned(client) {
+		return portalv1.RevokeAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	resp, err = s.updateScope(ctx, client.ClientId, func(scope []string) []string {
+		return slices.DeleteFunc(scope, func(s string) bool {
+			return slices.Contains(request.Params.ApiProducts, s)
+		})
+	})
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RevokeAPIProductAccess404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RevokeAPIProductAccess500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct is not supported by the Hydra connector, as Hydra has no registry of scopes to create the API
+// product in. Clients can be granted any scope without it.
+func (s *StrictServerHandler) CreateAPIProduct(
+	_ context.Context,
+	_ portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	return portalv1.CreateAPIProduct500JSONResponse(connector.NewNotSupportedError("Hydra", "creating API products")), nil
+}
+
+// DeleteAPIProduct removes the API product from the scope of every client that the connector created and has been
+// granted access to it. Other clients may have a scope of the same name for their own reasons.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	ctx context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	var cursor *string
+	for {
+		clients, next, resp, err := s.listClients(ctx, connector.DefaultPageLimit, cursor)
+		if err != nil || resp.IsError() {
+			return portalv1.DeleteAPIProduct500JSONResponse(unwrapError(resp, err)), nil
+		}
+
+		for _, client := range clients {
+			if !isOwned(&client) || !slices.Contains(strings.Fields(client.Scope), request.Id) {
+				continue
+			}
+
+			resp, err := s.updateScope(ctx, client.ClientId, func(scope []string) []string {
+				return slices.DeleteFunc(scope, func(s string) bool {
+					return s == request.Id
+				})
+			})
+
+			// The client may have been deleted since it was listed.
+			if err != nil || resp.IsError() {
+				if portalErr := unwrapError(resp, err); portalErr.Code != 404 {
+					return portalv1.DeleteAPIProduct500JSONResponse(portalErr), nil
+				}
+			}
+		}
+
+		if cursor = next; cursor == nil {
+			return portalv1.DeleteAPIProduct204Response{}, nil
+		}
+	}
+}
+
+// findClient finds a client by the ID Portal gave it, which is its name, or else by client ID.
+func (s *StrictServerHandler) findClient(ctx context.Context, id string) (*HydraClient, *resty.Response, error) {
+	client, resp, err := s.findClientByName(ctx, id)
+	if err != nil || resp.IsError() || client != nil {
+		return client, resp, err
+	}
+
+	return s.getClient(ctx, id)
+}
+
+// findClientByName returns the client with the name, or nil if there is none. Hydra does not require client names to
+// be unique, so the first client with the name is returned.
+func (s *StrictServerHandler) findClientByName(ctx context.Context, name string) (*HydraClient, *resty.Response, error) {
+	var clients []HydraClient
+	resp, err := s.upstream(ctx, "ListOAuth2Clients").
+		SetQueryParams(map[string]string{
+			"client_name": name,
+			"page_size":   "1",
+		}).
+		SetResult(&clients).
+		Get(s.clients)
+
+	if err != nil || resp.IsError() || len(clients) == 0 {
+		return nil, resp, err
+	}
+
+	return &clients[0], resp, nil
+}
+
+// getClient gets a client by client ID.
+func (s *StrictServerHandler) getClient(ctx context.Context, clientId string) (*HydraClient, *resty.Response, error) {
+	var client HydraClient
+	resp, err := s.upstream(ctx, "GetOAuth2Client").
+		SetPathParam("id", clientId).
+		SetResult(&client).
+		Get(s.clients + "/{id}")
+
+	return &client, resp, err
+}
+
+// listClients lists a page of clients, and returns the page token of the next page if there is one.
+func (s *StrictServerHandler) listClients(
+	ctx context.Context,
+	limit int,
+	pageToken *string,
+) ([]HydraClient, *string, *resty.Response, error) {
+	req := s.upstream(ctx, "ListOAuth2Clients").
+		SetQueryParam("page_size", strconv.Itoa(limit))
+	if pageToken != nil {
+		req.SetQueryParam("page_token", *pageToken)
+	}
+
+	var clients []HydraClient
+	resp, err := req.SetResult(&clients).Get(s.clients)
+	if err != nil || resp.IsError() {
+		return nil, nil, resp, err
+	}
+
+	// A page with fewer clients than requested is the last one.
+	var next *string
+	if len(clients) == limit {
+		next = connector.NextPageCursor(resp.Header(), "page_token")
+	}
+
+	return clients, next, resp, nil
+}
+
+// updateScope reads the current scope of the client, and replaces it with the scope returned by update if that
+// differs.
+func (s *StrictServerHandler) updateScope(
+	ctx context.Context,
+	clientId string,
+	update func(scope []string) []string,
+) (*resty.Response, error) {
+	client, resp, err := s.getClient(ctx, clientId)
+	if err != nil || resp.IsError() {
+		return resp, err
+	}
+
+	scope := strings.Fields(client.Scope)
+	updated := update(slices.Clone(scope))
+	if slices.Equal(scope, updated) {
+		return resp, nil
+	}
+
+	return s.upstream(ctx, "PatchOAuth2Client").
+		SetPathParam("id", client.ClientId).
+		SetBody([]HydraPatch{{Op: "replace", Path: "/scope", Value: strings.Join(updated, " ")}}).
+		Patch(s.clients + "/{id}")
+}
+
+// upstream returns a request to the Hydra admin API that is labelled with the given operation in the upstream
+// metrics.
+func (s *StrictServerHandler) upstream(ctx context.Context, operation string) *resty.Request {
+	return s.restClient.R().SetContext(connector.WithUpstreamOperation(ctx, operation))
+}
+
+// isOwned reports whether the metadata of the client marks it as created by IdP Connect.
+func isOwned(client *HydraClient) bool {
+	managedBy, _ := client.Metadata[connector.ManagedByKey].(string)
+	return managedBy == connector.ManagedBy
+}
+
+// applicationDetails returns the client without its secret, along with the API products that it has been granted
+// access to.
+func applicationDetails(client HydraClient) portalv1.OAuthApplicationDetails {
+	details := portalv1.OAuthApplicationDetails{
+		ClientId:   client.ClientId,
+		ClientName: &client.ClientName,
+		Scopes:     []string{},
+	}
+	for _, scope := range strings.Fields(client.Scope) {
+		if !slices.Contains(hydraDefaultScopes, scope) {
+			details.Scopes = append(details.Scopes, scope)
+		}
+	}
+
+	return details
+}
+
+// newSecret returns a random client secret.
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func unwrapError(resp *resty.Response, err error) portalv1.Error {
+	if err == nil {
+		error, _ := resp.Error().(*HydraError)
+		if error == nil || error.Error == "" {
+			return connector.NewPortalError(resp.StatusCode(), resp.Status(), string(resp.Body()))
+		}
+		return portalv1.Error{
+			Code:    resp.StatusCode(),
+			Message: error.Error,
+			Reason:  error.Description,
+		}
+	}
+
+	var respErr *resty.ResponseError
+	if ok := errors.As(err, &respErr); ok {
+		return portalv1.Error{
+			Code:    respErr.Response.StatusCode(),
+			Message: respErr.Response.Status(),
+			Reason:  respErr.Error(),
+		}
+	}
+
+	return connector.NewPortal500Error(err.Error())
+}
@@ -0,0 +1,442 @@
+package server_test
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector/connectortest"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/hydra/server"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+const fakeAdminURL = "http://hydra-admin:4445"
+
+// fakeHydra implements the /admin/clients endpoints of the Hydra admin API.
+type fakeHydra struct {
+	*connectortest.FakeIdP[map[string]interface{}]
+}
+
+func (f *fakeHydra) serve(req *http.Request) (*http.Response, error) {
+	id, hasId := strings.CutPrefix(req.URL.Path, "/admin/clients/")
+	Expect(hasId || req.URL.Path == "/admin/clients").To(BeTrue(), req.URL.String())
+
+	if !hasId {
+		switch req.Method {
+		case http.MethodPost:
+			var client map[string]interface{}
+			Expect(json.NewDecoder(req.Body).Decode(&client)).To(Succeed())
+
+			client["client_id"] = f.Next("client")
+			if client["scope"] == nil || client["scope"] == "" {
+				client["scope"] = "offline_access offline openid"
+			}
+			f.Clients[client["client_id"].(string)] = client
+
+			created := maps.Clone(client)
+			created["client_secret"] = f.Next("secret")
+			return httpmock.NewJsonResponse(201, created)
+		case http.MethodGet:
+			return f.list(req)
+		}
+	}
+
+	client, ok := f.Clients[id]
+	if !ok {
+		return httpmock.NewJsonResponse(404, server.HydraError{Error: "Not Found", Description: "Unable to locate the resource", StatusCode: 404})
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return httpmock.NewJsonResponse(200, client)
+	case http.MethodDelete:
+		delete(f.Clients, id)
+		return httpmock.NewStringResponse(204, ""), nil
+	case http.MethodPut:
+		var replacement map[string]interface{}
+		Expect(json.NewDecoder(req.Body).Decode(&replacement)).To(Succeed())
+		Expect(replacement["client_id"]).To(Equal(id))
+
+		secret, _ := replacement["client_secret"].(string)
+		Expect(len(secret)).To(BeNumerically(">=", 6))
+		delete(replacement, "client_secret")
+		f.Clients[id] = replacement
+
+		updated := maps.Clone(replacement)
+		updated["client_secret"] = secret
+		return httpmock.NewJsonResponse(200, updated)
+	case http.MethodPatch:
+		var patches []server.HydraPatch
+		Expect(json.NewDecoder(req.Body).Decode(&patches)).To(Succeed())
+		for _, patch := range patches {
+			Expect(patch.Op).To(Equal("replace"))
+			Expect(patch.Path).To(Equal("/scope"))
+			client["scope"] = patch.Value
+		}
+		return httpmock.NewJsonResponse(200, client)
+	}
+
+	return httpmock.NewJsonResponse(405, server.HydraError{Error: "Method Not Allowed"})
+}
+
+// list pages through the clients ordered by client ID, using the last client ID of a page as the next page token.
+// Like Hydra, it only lists the clients with the client_name if one is given.
+func (f *fakeHydra) list(req *http.Request) (*http.Response, error) {
+	pageSize, err := strconv.Atoi(req.URL.Query().Get("page_size"))
+	Expect(err).NotTo(HaveOccurred())
+	after := req.URL.Query().Get("page_token")
+	name := req.URL.Query().Get("client_name")
+
+	clients := []map[string]interface{}{}
+	for _, id := range slices.Sorted(maps.Keys(f.Clients)) {
+		if name != "" && f.Clients[id]["client_name"] != name {
+			continue
+		}
+		if id > after && len(clients) < pageSize {
+			clients = append(clients, f.Clients[id])
+		}
+	}
+
+	resp, err := httpmock.NewJsonResponse(200, clients)
+	if len(clients) > 0 {
+		last := clients[len(clients)-1]["client_id"].(string)
+		resp.Header.Add("Link", fmt.Sprintf(`</admin/clients?page_size=%d&page_token=>; rel="first",</admin/clients?page_size=%d&page_token=%s>; rel="next"`, pageSize, pageSize, last))
+	}
+
+	return resp, err
+}
+
+var _ = Describe("Server", func() {
+	var (
+		s           *server.StrictServerHandler
+		restyClient *resty.Client
+		ctx         context.Context
+
+		// The specs run against the Hydra admin API at HYDRA_ADMIN_URL if it is set, such as one started with
+		// `make run-hydra-tests`, and otherwise against a fake admin API.
+		adminURL = os.Getenv("HYDRA_ADMIN_URL")
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		restyClient = resty.New()
+		if adminURL == "" {
+			httpmock.ActivateNonDefault(restyClient.GetClient())
+			fake := &fakeHydra{FakeIdP: connectortest.NewFakeIdP[map[string]interface{}]()}
+			httpmock.RegisterNoResponder(fake.Responder(fake.serve))
+		}
+
+		s = server.NewStrictServerHandler(&server.Options{AdminURL: cmp.Or(adminURL, fakeAdminURL)}, restyClient)
+	})
+
+	create := func(id string) portalv1.CreateOAuthApplication201JSONResponse {
+		resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+			Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+
+		app := resp.(portalv1.CreateOAuthApplication201JSONResponse)
+		DeferCleanup(func() {
+			_, _ = s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: app.ClientId})
+		})
+
+		return app
+	}
+
+	// createUnowned creates a client directly in Hydra, as something other than IdP Connect would.
+	createUnowned := func(name string, scopes ...string) server.HydraClient {
+		var client server.HydraClient
+		resp, err := restyClient.R().
+			SetBody(server.HydraClient{ClientName: name, GrantTypes: []string{"client_credentials"}, Scope: strings.Join(scopes, " ")}).
+			SetResult(&client).
+			Post(cmp.Or(adminURL, fakeAdminURL) + "/admin/clients")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode()).To(Equal(http.StatusCreated))
+
+		DeferCleanup(func() {
+			_, _ = restyClient.R().Delete(cmp.Or(adminURL, fakeAdminURL) + "/admin/clients/" + client.ClientId)
+		})
+
+		return client
+	}
+
+	get := func(clientId string) portalv1.OAuthApplicationDetails {
+		resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: clientId})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+
+		return portalv1.OAuthApplicationDetails(resp.(portalv1.GetOAuthApplication200JSONResponse))
+	}
+
+	grant := func(clientId string, apiProducts ...string) portalv1.GrantAPIProductAccessResponseObject {
+		resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+			Id:   clientId,
+			Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: apiProducts},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return resp
+	}
+
+	It("is ready when clients can be listed", func() {
+		Expect(s.Ready(ctx)).To(Succeed())
+	})
+
+	When("creating a client", func() {
+		It("returns its ID and secret, and has no API products", func() {
+			app := create("my-app")
+
+			Expect(app.ClientId).NotTo(BeEmpty())
+			Expect(*app.ClientName).To(Equal("my-app"))
+			Expect(app.ClientSecret).NotTo(BeEmpty())
+
+			details := get("my-app")
+			Expect(details.ClientId).To(Equal(app.ClientId))
+			Expect(*details.ClientName).To(Equal("my-app"))
+			Expect(details.Scopes).To(BeEmpty())
+		})
+
+		It("marks it as created by IdP Connect", func() {
+			app := create("my-app")
+
+			var client server.HydraClient
+			_, err := restyClient.R().SetResult(&client).Get(cmp.Or(adminURL, fakeAdminURL) + "/admin/clients/" + app.ClientId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.Metadata).To(HaveKeyWithValue("managed_by", "gloo-portal-idp-connect"))
+			Expect(client.Metadata).To(HaveKeyWithValue("portal_id", "my-app"))
+			Expect(client.Metadata).To(HaveKey("created_at"))
+		})
+
+		It("returns 409 for an id that a client already exists for", func() {
+			app := create("my-app")
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "my-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(app.ClientId))
+		})
+
+		It("returns 400 without an id", func() {
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication400JSONResponse{}))
+		})
+	})
+
+	When("deleting a client", func() {
+		It("deletes it by the id it was created with", func() {
+			app := create("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+
+			getResp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+
+		It("deletes it, and returns 404 once it is gone", func() {
+			app := create("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+
+			resp, err = s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
+
+			getResp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+
+		It("returns 403 for a client that IdP Connect did not create", func() {
+			client := createUnowned("my-app")
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+			Expect(get(client.ClientId).ClientId).To(Equal(client.ClientId))
+		})
+
+		It("deletes a client that IdP Connect did not create when forced to", func() {
+			client := createUnowned("my-app")
+			opts := &server.Options{AdminURL: cmp.Or(adminURL, fakeAdminURL)}
+			opts.ForceDelete = true
+			s = server.NewStrictServerHandler(opts, restyClient)
+
+			resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+
+			getResp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: client.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+	})
+
+	When("listing clients", func() {
+		It("pages through the clients that it created", func() {
+			var created []string
+			for _, id := range []string{"a", "b", "c"} {
+				created = append(created, create(id).ClientId)
+			}
+
+			other := createUnowned("other")
+
+			limit := 2
+			var listed []string
+			var cursor *string
+			for {
+				resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+					Params: portalv1.ListOAuthApplicationsParams{Limit: &limit, Cursor: cursor},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications200JSONResponse{}))
+
+				page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+				Expect(len(page.Applications)).To(BeNumerically("<=", limit))
+				for _, app := range page.Applications {
+					listed = append(listed, app.ClientId)
+				}
+
+				if cursor = page.NextCursor; cursor == nil {
+					break
+				}
+			}
+
+			Expect(listed).To(ContainElements(created))
+			Expect(listed).NotTo(ContainElement(other.ClientId))
+		})
+	})
+
+	When("rotating the secret of a client", func() {
+		It("returns a new secret and keeps the client", func() {
+			app := create("my-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(app.ClientId))
+			Expect(*rotated.ClientName).To(Equal("my-app"))
+			Expect(rotated.ClientSecret).NotTo(BeEmpty())
+			Expect(rotated.ClientSecret).NotTo(Equal(app.ClientSecret))
+		})
+
+		It("returns 404 for an unknown client", func() {
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "unknown"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret404JSONResponse{}))
+		})
+
+		It("returns 403 for a client that IdP Connect did not create", func() {
+			createUnowned("my-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret403JSONResponse{}))
+		})
+	})
+
+	When("granting and revoking access to API products", func() {
+		It("keeps the scope of the client in sync", func() {
+			app := create("my-app")
+
+			Expect(grant("my-app", "petstore", "tracks")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(get(app.ClientId).Scopes).To(ConsistOf("petstore", "tracks"))
+
+			Expect(grant(app.ClientId, "petstore")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(get(app.ClientId).Scopes).To(ConsistOf("petstore", "tracks"))
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     "my-app",
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+			Expect(get(app.ClientId).Scopes).To(ConsistOf("tracks"))
+		})
+
+		It("returns 404 for an unknown client", func() {
+			Expect(grant("unknown", "petstore")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns 403 for a client that IdP Connect did not create", func() {
+			createUnowned("other", "petstore")
+
+			Expect(grant("other", "tracks")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess403JSONResponse{}))
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     "other",
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess403JSONResponse{}))
+		})
+	})
+
+	When("deleting an API product", func() {
+		It("revokes access to it from every client", func() {
+			first := create("first")
+			second := create("second")
+			Expect(grant(first.ClientId, "petstore", "tracks")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(grant(second.ClientId, "petstore")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+
+			resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "petstore"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+
+			Expect(get(first.ClientId).Scopes).To(ConsistOf("tracks"))
+			Expect(get(second.ClientId).Scopes).To(BeEmpty())
+		})
+
+		It("keeps the scope of clients that IdP Connect did not create", func() {
+			other := createUnowned("other", "petstore")
+
+			resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "petstore"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+
+			var client server.HydraClient
+			getResp, err := restyClient.R().
+				SetResult(&client).
+				Get(cmp.Or(adminURL, fakeAdminURL) + "/admin/clients/" + other.ClientId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp.IsSuccess()).To(BeTrue(), getResp.String())
+			Expect(strings.Fields(client.Scope)).To(ContainElement("petstore"))
+		})
+	})
+
+	When("creating an API product", func() {
+		It("is not supported", func() {
+			resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+				Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "petstore"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct500JSONResponse{}))
+			Expect(resp.(portalv1.CreateAPIProduct500JSONResponse).Reason).To(ContainSubstring("does not support"))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"os"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/rotisserie/eris"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+type Options struct {
+	connector.Options
+	AdminURL string
+	APIKey   string
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	o.Options.AddToFlags(flag)
+	flag.StringVar(&o.AdminURL, "admin-url", "", "URL of the Hydra admin API (e.g. http://hydra-admin:4445)")
+	flag.StringVar(&o.APIKey, "api-key", "", "Bearer token for the admin API, if it is protected, such as on the Ory Network")
+}
+
+func (o *Options) Validate() error {
+	if o.AdminURL == "" {
+		return eris.New("Admin URL is required")
+	}
+
+	// Try to get the API key from the environment if not provided via flag
+	if o.APIKey == "" {
+		o.APIKey = os.Getenv("ORY_API_KEY")
+	}
+
+	return nil
+}
+
+func ListenAndServe(ctx context.Context, opts *Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	client := resty.New().SetTransport(opts.UpstreamTransport("hydra", nil))
+	if opts.APIKey != "" {
+		client.SetAuthToken(opts.APIKey)
+	}
+
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, client))
+}
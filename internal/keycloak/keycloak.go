@@ -1,8 +1,6 @@
 package keycloak
 
 import (
-	"context"
-
 	"github.com/spf13/cobra"
 
 	"github.com/solo-io/gloo-portal-idp-connect/internal/keycloak/server"
@@ -14,8 +12,11 @@ func Command() *cobra.Command {
 	cmd := &cobra.Command{
 		Short: "Start the Keycloak IDP connector",
 		Use:   "keycloak",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return serverOpts.ConfigureLogging()
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return server.ListenAndServe(context.Background(), serverOpts)
+			return server.ListenAndServe(cmd.Context(), serverOpts)
 		},
 		// option to silence usage when an error occurs
 		SilenceUsage: true,
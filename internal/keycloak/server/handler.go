@@ -3,14 +3,19 @@ package server
 import (
 	"context"
 	"errors"
-	"fmt"
+	"net/http"
 	"regexp"
-	"time"
+	"strconv"
+	"strings"
 
 	resty "github.com/go-resty/resty/v2"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
 )
 
+// apiProductResourceType is the type given to the Keycloak resources that represent API products.
+const apiProductResourceType = "urn:gloo-portal:resources:api-product"
+
 type StrictServerHandler struct {
 	restClient          resty.Client
 	issuer              string
@@ -18,16 +23,46 @@ type StrictServerHandler struct {
 	adminRoot           string
 	mgmtClientId        string
 	mgmtClientSecret    string
+	tokens              *connector.TokenSource
+	forceDelete         bool
 }
 
 type KeycloakToken struct {
-	AccessToken string `json:"access_token"`
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in,omitempty"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	RefreshExpiresIn int    `json:"refresh_expires_in,omitempty"`
 }
 
 type KeycloakClient struct {
-	Id     string `json:"id"`
-	Name   string `json:"name"`
-	Secret string `json:"secret"`
+	Id                     string `json:"id"`
+	ClientId               string `json:"clientId"`
+	Name                   string `json:"name"`
+	Secret                 string `json:"secret"`
+	ServiceAccountsEnabled bool   `json:"serviceAccountsEnabled"`
+
+	// Attributes of the client, which include the ownership metadata of clients that IdP Connect created.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type KeycloakCredential struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type KeycloakResource struct {
+	Id                 string `json:"_id,omitempty"`
+	Name               string `json:"name"`
+	DisplayName        string `json:"displayName,omitempty"`
+	Type               string `json:"type,omitempty"`
+	OwnerManagedAccess bool   `json:"ownerManagedAccess"`
+}
+
+type KeycloakPermission struct {
+	Id          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Clients     []string `json:"clients,omitempty"`
 }
 
 type KeycloakError struct {
@@ -39,42 +74,22 @@ func NewStrictServerHandler(opts *Options, restyClient *resty.Client, discovered
 	r := regexp.MustCompile("^(https?:.*?)/realms/(.[^/]*)/?$")
 	adminRoot := r.ReplaceAllString(opts.Issuer, "$1/admin/realms/$2")
 
-	var token *KeycloakToken
-	var tokenRefreshed time.Time
+	// Tokens are requested with the client's transport, before requests are authenticated with them.
+	base := restyClient.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	management := &managementTokens{
+		client:       resty.NewWithClient(&http.Client{Transport: base}),
+		endpoint:     discoveredEndpoints.Tokens,
+		clientId:     opts.MgmtClientId,
+		clientSecret: opts.MgmtClientSecret,
+	}
+	tokens := connector.NewTokenSource(management.fetch)
+	restyClient.SetTransport(connector.NewTokenTransport(tokens, base))
 
 	restyClient.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
-		// If we already have user info, assume this is a request to fetch a token
-		if r.UserInfo != nil {
-			return nil
-		}
-
-		// Reuse the last token if we got it less than a minute ago
-		if token == nil || time.Since(tokenRefreshed).Seconds() > 60 {
-			tokenResponse, err := c.R().
-				SetBasicAuth(opts.MgmtClientId, opts.MgmtClientSecret).
-				SetFormData(map[string]string{
-					"grant_type": "urn:ietf:params:oauth:grant-type:uma-ticket",
-					"audience":   opts.MgmtClientId,
-				}).
-				SetResult(&token).
-				SetError(&KeycloakError{}).
-				Post(discoveredEndpoints.Tokens)
-
-			tokenRefreshed = time.Now()
-
-			if err != nil {
-				return err
-			}
-
-			if tokenResponse.IsError() {
-				error := tokenResponse.Error().(*KeycloakError)
-				return fmt.Errorf("could not obtain token for client %s: [%s] %s", opts.MgmtClientId, error.Error, error.Description)
-			}
-		}
-
-		r.SetAuthToken(token.AccessToken)
 		r.SetError(&KeycloakError{})
-
 		return nil
 	})
 
@@ -85,25 +100,59 @@ func NewStrictServerHandler(opts *Options, restyClient *resty.Client, discovered
 		adminRoot:           adminRoot,
 		mgmtClientId:        opts.MgmtClientId,
 		mgmtClientSecret:    opts.MgmtClientSecret,
+		tokens:              tokens,
+		forceDelete:         opts.ForceDelete,
 	}
 }
 
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "keycloak"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that a management token can be obtained from the token endpoint. The cached token is used until it
+// expires, so that probes do not request a token each time.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	_, err := s.tokens.Token(ctx)
+	return err
+}
+
 // CreateOAuthApplication creates a client in Keycloak
 func (s *StrictServerHandler) CreateOAuthApplication(
-	_ context.Context,
+	ctx context.Context,
 	request portalv1.CreateOAuthApplicationRequestObject,
 ) (portalv1.CreateOAuthApplicationResponseObject, error) {
 	if request.Body == nil || len(request.Body.Id) == 0 {
-		return portalv1.CreateOAuthApplication400JSONResponse(newPortal400Error("unique id is required")), nil
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	// The registration endpoint rejects a client ID that is in use as invalid metadata, so look for the client first.
+	clients, getId, err := s.findClients(ctx, request.Body.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(clients) > 0 {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, clients[0].ClientId)), nil
 	}
 
 	var createdClient KeycloakClient
 
-	resp, err := s.restClient.R().
+	resp, err := s.upstream(ctx, "CreateClient").
 		SetBody(map[string]interface{}{
 			"clientId":               request.Body.Id,
 			"name":                   request.Body.Id,
 			"serviceAccountsEnabled": true,
+			"attributes":             connector.NewOwnership(request.Body.Id).Metadata(),
 		}).
 		SetResult(&createdClient).
 		Post(s.issuer + "/clients-registrations/default")
@@ -119,39 +168,38 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 	}, nil
 }
 
-// DeleteOAuthApplication deletes a client in Keycloak by ID.
+// DeleteOAuthApplication deletes a client in Keycloak by ID. The client ID of a Keycloak client is the ID Portal gave
+// the application on creation, so no lookup by name is needed. Clients without the ownership attributes are only
+// deleted when deletes are forced.
 func (s *StrictServerHandler) DeleteOAuthApplication(
-	_ context.Context,
+	ctx context.Context,
 	request portalv1.DeleteOAuthApplicationRequestObject,
 ) (portalv1.DeleteOAuthApplicationResponseObject, error) {
 	if len(request.Id) == 0 {
-		return portalv1.DeleteOAuthApplication404JSONResponse(newPortal400Error("client ID is required")), nil
+		return portalv1.DeleteOAuthApplication404JSONResponse(connector.NewPortal404Error("client ID is required")), nil
 	}
 
 	// Get the Keycloak internal ID of the client
-	var clients []KeycloakClient
-	getId, err := s.restClient.R().
-		SetQueryParams(map[string]string{
-			"clientId": request.Id,
-		}).
-		SetResult(&clients).
-		Get(s.adminRoot + "/clients")
-
+	clients, getId, err := s.findClients(ctx, request.Id)
 	if err != nil || getId.IsError() {
 		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapError(getId, err)), nil
 	}
 
 	if len(clients) == 0 {
-		return portalv1.DeleteOAuthApplication404JSONResponse(newPortal400Error("no client matches name [" + request.Id + "]")), nil
+		return portalv1.DeleteOAuthApplication404JSONResponse(connector.NewPortal404Error("no client matches name [" + request.Id + "]")), nil
 	}
 
 	if len(clients) > 1 {
 		// If we get this then we're not looking up the ID properly
-		return portalv1.DeleteOAuthApplication500JSONResponse(newPortal500Error("more than one matching client found for [" + request.Id + "]")), nil
+		return portalv1.DeleteOAuthApplication500JSONResponse(connector.NewPortal500Error("more than one matching client found for [" + request.Id + "]")), nil
+	}
+
+	if !s.forceDelete && !connector.IsOwned(clients[0].Attributes) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
 	}
 
 	// Delete the client with the single ID we located
-	resp, err := s.restClient.R().
+	resp, err := s.upstream(ctx, "DeleteClient").
 		Delete(s.adminRoot + "/clients/" + clients[0].Id)
 
 	if err != nil || resp.IsError() {
@@ -166,6 +214,368 @@ func (s *StrictServerHandler) DeleteOAuthApplication(
 	return portalv1.DeleteOAuthApplication204Response{}, nil
 }
 
+// ListOAuthApplications lists the clients in the Keycloak realm that the connector created, along with the API products
+// they can access. The cursor is the offset of the next page in the realm's clients.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	first := 0
+	if request.Params.Cursor != nil {
+		var err error
+		if first, err = strconv.Atoi(*request.Params.Cursor); err != nil || first < 0 {
+			return portalv1.ListOAuthApplications400JSONResponse(connector.NewPortal400Error("invalid cursor [" + *request.Params.Cursor + "]")), nil
+		}
+	}
+	limit := connector.PageLimit(request.Params.Limit)
+
+	var clients []KeycloakClient
+	resp, err := s.upstream(ctx, "ListClients").
+		SetQueryParams(map[string]string{
+			"first": strconv.Itoa(first),
+			"max":   strconv.Itoa(limit),
+		}).
+		SetResult(&clients).
+		Get(s.adminRoot + "/clients")
+
+	if err != nil || resp.IsError() {
+		return portalv1.ListOAuthApplications500JSONResponse(unwrapError(resp, err)), nil
+	}
+
+	page := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+	}
+	if len(clients) == limit {
+		next := strconv.Itoa(first + limit)
+		page.NextCursor = &next
+	}
+
+	for _, client := range clients {
+		// The realm's built-in clients and the management client are not owned by IdP Connect either.
+		if !connector.IsOwned(client.Attributes) {
+			continue
+		}
+
+		details, getPermissions, err := s.applicationDetails(ctx, client)
+		if err != nil || getPermissions.IsError() {
+			return portalv1.ListOAuthApplications500JSONResponse(unwrapError(getPermissions, err)), nil
+		}
+
+		page.Applications = append(page.Applications, details)
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication gets a client in Keycloak by client ID.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	clients, getId, err := s.findClients(ctx, request.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(clients) == 0 {
+		return portalv1.GetOAuthApplication404JSONResponse(connector.NewPortal404Error("no client matches name [" + request.Id + "]")), nil
+	}
+
+	details, getPermissions, err := s.applicationDetails(ctx, clients[0])
+	if err != nil || getPermissions.IsError() {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapError(getPermissions, err)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(details), nil
+}
+
+// RotateOAuthApplicationSecret regenerates the secret of a client in Keycloak. Clients without the ownership attributes
+// are not changed.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	clients, getId, err := s.findClients(ctx, request.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(clients) == 0 {
+		return portalv1.RotateOAuthApplicationSecret404JSONResponse(connector.NewPortal404Error("no client matches name [" + request.Id + "]")), nil
+	}
+
+	if len(clients) > 1 {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(connector.NewPortal500Error("more than one matching client found for [" + request.Id + "]")), nil
+	}
+
+	if !connector.IsOwned(clients[0].Attributes) {
+		return portalv1.RotateOAuthApplicationSecret403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	var secret KeycloakCredential
+	resp, err := s.upstream(ctx, "RegenerateClientSecret").
+		SetResult(&secret).
+		Post(s.adminRoot + "/clients/" + clients[0].Id + "/client-secret")
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.RotateOAuthApplicationSecret404JSONResponse(portalErr), nil
+		default:
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     clients[0].ClientId,
+		ClientName:   &clients[0].Name,
+		ClientSecret: secret.Value,
+	}, nil
+}
+
+// GrantAPIProductAccess creates a permission on each API product resource that grants access to the client.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	clients, getId, err := s.findClients(ctx, request.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(clients) == 0 {
+		return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error("no client matches name [" + request.Id + "]")), nil
+	}
+
+	if !connector.IsOwned(clients[0].Attributes) {
+		return portalv1.GrantAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	for _, apiProduct := range request.Body.ApiProducts {
+		resourceIds, getId, err := s.findResources(ctx, apiProduct)
+		if err != nil || getId.IsError() {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapError(getId, err)), nil
+		}
+
+		if len(resourceIds) != 1 {
+			return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error("no single API product matches name [" + apiProduct + "]")), nil
+		}
+
+		resp, err := s.upstream(ctx, "CreatePermission").
+			SetBody(KeycloakPermission{
+				Name:        permissionName(request.Id, apiProduct),
+				Description: "Grants client " + request.Id + " access to API product " + apiProduct,
+				Clients:     []string{request.Id},
+			}).
+			Post(s.discoveredEndpoints.Policy + "/" + resourceIds[0])
+
+		if err != nil || resp.IsError() {
+			// A conflict means the client has already been granted access.
+			if portalErr := unwrapError(resp, err); portalErr.Code != 409 {
+				return portalv1.GrantAPIProductAccess500JSONResponse(portalErr), nil
+			}
+		}
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess deletes the permissions that grant the client access to the given API products.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	clients, getId, err := s.findClients(ctx, request.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(clients) == 0 {
+		return portalv1.RevokeAPIProductAccess404JSONResponse(connector.NewPortal404Error("no client matches name [" + request.Id + "]")), nil
+	}
+
+	if !connector.IsOwned(clients[0].Attributes) {
+		return portalv1.RevokeAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	for _, apiProduct := range request.Params.ApiProducts {
+		name := permissionName(request.Id, apiProduct)
+
+		var permissions []KeycloakPermission
+		getPermissions, err := s.upstream(ctx, "ListPermissions").
+			SetQueryParam("name", name).
+			SetResult(&permissions).
+			Get(s.discoveredEndpoints.Policy)
+
+		if err != nil || getPermissions.IsError() {
+			return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapError(getPermissions, err)), nil
+		}
+
+		for _, permission := range permissions {
+			// The name query parameter is a partial match.
+			if permission.Name != name {
+				continue
+			}
+
+			resp, err := s.upstream(ctx, "DeletePermission").
+				Delete(s.discoveredEndpoints.Policy + "/" + permission.Id)
+
+			if err != nil || resp.IsError() {
+				if portalErr := unwrapError(resp, err); portalErr.Code != 404 {
+					return portalv1.RevokeAPIProductAccess500JSONResponse(portalErr), nil
+				}
+			}
+		}
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct registers the API product as a resource on the Keycloak resource server.
+func (s *StrictServerHandler) CreateAPIProduct(
+	ctx context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product id is required")), nil
+	}
+
+	resource := KeycloakResource{
+		Name:               request.Body.Id,
+		Type:               apiProductResourceType,
+		OwnerManagedAccess: true,
+	}
+	if request.Body.Description != nil {
+		resource.DisplayName = *request.Body.Description
+	}
+
+	resp, err := s.upstream(ctx, "CreateResource").
+		SetBody(resource).
+		Post(s.discoveredEndpoints.ResourceRegistration)
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 409:
+			return portalv1.CreateAPIProduct409JSONResponse(portalErr), nil
+		default:
+			return portalv1.CreateAPIProduct500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct deletes the Keycloak resource representing the API product.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	ctx context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	// Get the Keycloak internal ID of the resource
+	resourceIds, getId, err := s.findResources(ctx, request.Id)
+	if err != nil || getId.IsError() {
+		return portalv1.DeleteAPIProduct500JSONResponse(unwrapError(getId, err)), nil
+	}
+
+	if len(resourceIds) == 0 {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error("no API product matches name [" + request.Id + "]")), nil
+	}
+
+	if len(resourceIds) > 1 {
+		return portalv1.DeleteAPIProduct500JSONResponse(connector.NewPortal500Error("more than one matching API product found for [" + request.Id + "]")), nil
+	}
+
+	resp, err := s.upstream(ctx, "DeleteResource").
+		Delete(s.discoveredEndpoints.ResourceRegistration + "/" + resourceIds[0])
+
+	if err != nil || resp.IsError() {
+		switch portalErr := unwrapError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteAPIProduct404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteAPIProduct500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// findClients looks up clients in the realm by their client ID.
+func (s *StrictServerHandler) findClients(ctx context.Context, clientId string) ([]KeycloakClient, *resty.Response, error) {
+	var clients []KeycloakClient
+	resp, err := s.upstream(ctx, "ListClients").
+		SetQueryParams(map[string]string{
+			"clientId": clientId,
+		}).
+		SetResult(&clients).
+		Get(s.adminRoot + "/clients")
+
+	return clients, resp, err
+}
+
+// findResources looks up the IDs of the resources on the resource server with the given name.
+func (s *StrictServerHandler) findResources(ctx context.Context, name string) ([]string, *resty.Response, error) {
+	var resourceIds []string
+	resp, err := s.upstream(ctx, "ListResources").
+		SetQueryParams(map[string]string{
+			"name":      name,
+			"exactName": "true",
+		}).
+		SetResult(&resourceIds).
+		Get(s.discoveredEndpoints.ResourceRegistration)
+
+	return resourceIds, resp, err
+}
+
+// applicationDetails returns the client without its secret, along with the API products that it has been granted
+// access to.
+func (s *StrictServerHandler) applicationDetails(ctx context.Context, client KeycloakClient) (portalv1.OAuthApplicationDetails, *resty.Response, error) {
+	prefix := permissionName(client.ClientId, "")
+
+	var permissions []KeycloakPermission
+	resp, err := s.upstream(ctx, "ListPermissions").
+		SetQueryParam("name", prefix).
+		SetResult(&permissions).
+		Get(s.discoveredEndpoints.Policy)
+
+	details := portalv1.OAuthApplicationDetails{
+		ClientId:   client.ClientId,
+		ClientName: &client.Name,
+		Scopes:     []string{},
+	}
+
+	if err != nil || resp.IsError() {
+		return details, resp, err
+	}
+
+	for _, permission := range permissions {
+		// The name query parameter is a partial match.
+		if apiProduct, ok := strings.CutPrefix(permission.Name, prefix); ok && apiProduct != "" {
+			details.Scopes = append(details.Scopes, apiProduct)
+		}
+	}
+
+	return details, resp, nil
+}
+
+// upstream returns a request to Keycloak that is made with the context of the API request, so that it is cancelled
+// along with it, and is labelled with the given operation in the upstream metrics.
+func (s *StrictServerHandler) upstream(ctx context.Context, operation string) *resty.Request {
+	return s.restClient.R().SetContext(connector.WithUpstreamOperation(ctx, operation))
+}
+
+// permissionName returns the name of the permission that grants a client access to an API product.
+func permissionName(clientId, apiProduct string) string {
+	return clientId + "/" + apiProduct
+}
+
 func unwrapError(resp *resty.Response, err error) portalv1.Error {
 	if err == nil {
 		error := resp.Error().(*KeycloakError)
@@ -185,5 +595,5 @@ func unwrapError(resp *resty.Response, err error) portalv1.Error {
 		}
 	}
 
-	return newPortal500Error(err.Error())
+	return connector.NewPortal500Error(err.Error())
 }
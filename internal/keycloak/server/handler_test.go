@@ -2,6 +2,12 @@ package server_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
 
 	resty "github.com/go-resty/resty/v2"
 	_ "github.com/golang/mock/mockgen/model"
@@ -9,6 +15,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 	"github.com/solo-io/gloo-portal-idp-connect/internal/keycloak/server"
 	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
 )
@@ -31,6 +38,7 @@ var _ = Describe("Server", func() {
 		ctx context.Context
 
 		endpoints = server.DiscoveredEndpoints{
+			Policy:               issuer + "/authz/protection/uma-policy",
 			Tokens:               issuer + "/protocol/openid-connect/token",
 			ResourceRegistration: issuer + "/authz/protection/resource_set",
 		}
@@ -70,11 +78,20 @@ var _ = Describe("Server", func() {
 		When("no client exists", func() {
 
 			BeforeEach(func() {
-				newClientResponder, _ := httpmock.NewJsonResponder(200, dummyClient)
-				httpmock.RegisterResponder("POST", issuer+"/clients-registrations/default", newClientResponder)
+				httpmock.RegisterResponder("POST", issuer+"/clients-registrations/default", func(req *http.Request) (*http.Response, error) {
+					var body server.KeycloakClient
+					if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+						return httpmock.NewStringResponse(400, err.Error()), nil
+					}
+					if body.Attributes["managed_by"] != "gloo-portal-idp-connect" || body.Attributes["portal_id"] != body.ClientId {
+						return httpmock.NewStringResponse(400, "missing ownership attributes"), nil
+					}
+					return httpmock.NewJsonResponse(200, dummyClient)
+				})
 
 				getClientResponder, _ := httpmock.NewJsonResponder(200, []string{})
 				httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId=non-existing-client", getClientResponder)
+				httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientResponder)
 			})
 
 			It("can create a client", func() {
@@ -123,13 +140,15 @@ var _ = Describe("Server", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
 				resp404 := resp.(portalv1.DeleteOAuthApplication404JSONResponse)
-				Expect(resp404.Code).To(Equal(400))
+				Expect(resp404.Code).To(Equal(404))
 			})
 		})
 
 		When("client exists", func() {
 			BeforeEach(func() {
-				getClientIdResponder, _ := httpmock.NewJsonResponder(200, [1]server.KeycloakClient{dummyClient})
+				ownedClient := dummyClient
+				ownedClient.Attributes = map[string]string{"managed_by": "gloo-portal-idp-connect", "portal_id": applicationClientId}
+				getClientIdResponder, _ := httpmock.NewJsonResponder(200, [1]server.KeycloakClient{ownedClient})
 				httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientIdResponder)
 
 				deleteClientResponder, _ := httpmock.NewJsonResponder(204, nil)
@@ -146,6 +165,500 @@ var _ = Describe("Server", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
 			})
+
+			It("returns conflict code on creation", func() {
+				resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+					Body: &portalv1.CreateOAuthApplicationJSONRequestBody{
+						Id: applicationClientId,
+					},
+					Params: portalv1.CreateOAuthApplicationParams{
+						Token: &testToken,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+				Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Code).To(Equal(409))
+			})
+		})
+
+		When("a client that IdP Connect did not create exists", func() {
+			BeforeEach(func() {
+				getClientIdResponder, _ := httpmock.NewJsonResponder(200, [1]server.KeycloakClient{dummyClient})
+				httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientIdResponder)
+
+				deleteClientResponder, _ := httpmock.NewJsonResponder(204, nil)
+				httpmock.RegisterResponder("DELETE", fakeAdminEndpoint+"/clients/"+applicationClientId, deleteClientResponder)
+			})
+
+			It("refuses to delete the client", func() {
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+				Expect(httpmock.GetCallCountInfo()["DELETE "+fakeAdminEndpoint+"/clients/"+applicationClientId]).To(BeZero())
+			})
+
+			It("refuses to rotate the secret of the client", func() {
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret403JSONResponse{}))
+				Expect(httpmock.GetCallCountInfo()["POST "+fakeAdminEndpoint+"/clients/"+applicationClientId+"/client-secret"]).To(BeZero())
+			})
+
+			It("refuses to grant or revoke API products for the client", func() {
+				grant, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+					Id:   applicationClientId,
+					Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: []string{"tracks-rest-api"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(grant).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess403JSONResponse{}))
+
+				revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+					Id:     applicationClientId,
+					Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"tracks-rest-api"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess403JSONResponse{}))
+			})
+
+			It("deletes the client when forced to", func() {
+				opts := &server.Options{
+					Issuer:           issuer,
+					MgmtClientId:     mgmtClientId,
+					MgmtClientSecret: mgmtClientSecret,
+				}
+				opts.ForceDelete = true
+				restyClient := resty.New()
+				httpmock.ActivateNonDefault(restyClient.GetClient())
+				s = server.NewStrictServerHandler(opts, restyClient, endpoints)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+		})
+	})
+
+	Context("Application details", func() {
+		const apiProductId = "tracks-rest-api"
+
+		var serviceClient = server.KeycloakClient{
+			Id:                     "6f1d5a0e-3c9b-4c1e-9a57-0f1c1f6b2d11",
+			ClientId:               applicationClientId,
+			Name:                   applicationClientId,
+			ServiceAccountsEnabled: true,
+			Attributes:             connector.NewOwnership(applicationClientId).Metadata(),
+		}
+
+		BeforeEach(func() {
+			getPermissionsResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakPermission{
+				{Id: "3c0b1ba4", Name: applicationClientId + "/" + apiProductId},
+				{Id: "9d2e7f10", Name: applicationClientId + "-v2/" + apiProductId},
+			})
+			httpmock.RegisterResponder("GET", endpoints.Policy+"?name="+url.QueryEscape(applicationClientId+"/"), getPermissionsResponder)
+		})
+
+		It("lists the clients that it created with their API products", func() {
+			listClientsResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{
+				{Id: "b3a1", ClientId: "admin-cli", Name: "admin-cli"},
+				{Id: "c4d2", ClientId: mgmtClientId, Name: mgmtClientId, ServiceAccountsEnabled: true},
+				{Id: "d5e3", ClientId: "other-app", Name: "other-app", ServiceAccountsEnabled: true},
+				serviceClient,
+			})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?first=4&max=4", listClientsResponder)
+
+			limit, cursor := 4, "4"
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{
+					Limit:  &limit,
+					Cursor: &cursor,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications200JSONResponse{}))
+			page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+			Expect(page.Applications).To(HaveLen(1))
+			Expect(page.Applications[0].ClientId).To(Equal(applicationClientId))
+			Expect(page.Applications[0].Scopes).To(ConsistOf(apiProductId))
+			Expect(*page.NextCursor).To(Equal("8"))
+		})
+
+		It("returns error code on an invalid cursor", func() {
+			cursor := "next"
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{
+					Cursor: &cursor,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications400JSONResponse{}))
+		})
+
+		It("gets the client without its secret", func() {
+			getClientResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{serviceClient})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientResponder)
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			Expect(resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes).To(ConsistOf(apiProductId))
+		})
+
+		It("calls Keycloak with the context of the request", func() {
+			type requestKey struct{}
+			requestCtx := context.WithValue(ctx, requestKey{}, "request")
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, func(req *http.Request) (*http.Response, error) {
+				Expect(req.Context().Value(requestKey{})).To(Equal("request"))
+				return httpmock.NewJsonResponse(200, []server.KeycloakClient{serviceClient})
+			})
+
+			resp, err := s.GetOAuthApplication(requestCtx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+		})
+
+		It("regenerates the client secret", func() {
+			getClientResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{serviceClient})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientResponder)
+
+			newSecretResponder, _ := httpmock.NewJsonResponder(200, server.KeycloakCredential{
+				Type:  "secret",
+				Value: "r0t4t3d",
+			})
+			httpmock.RegisterResponder("POST", fakeAdminEndpoint+"/clients/"+serviceClient.Id+"/client-secret", newSecretResponder)
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(applicationClientId))
+			Expect(rotated.ClientSecret).To(Equal("r0t4t3d"))
+		})
+
+		It("returns not found code when the client does not exist", func() {
+			getClientResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId=non-existing-client", getClientResponder)
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: "non-existing-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		})
+	})
+
+	Context("API Product access", func() {
+		const (
+			apiProductId         = "tracks-rest-api"
+			apiProductResourceId = "aa6edf59-a4b7-4532-b6b1-a5b423da7809"
+			permissionId         = "3c0b1ba4-0f7b-4b19-a2c9-4d04c1c3e3f1"
+		)
+
+		var permissionName = applicationClientId + "/" + apiProductId
+
+		BeforeEach(func() {
+			ownedClient := dummyClient
+			ownedClient.Attributes = connector.NewOwnership(applicationClientId).Metadata()
+			getClientIdResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{ownedClient})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, getClientIdResponder)
+
+			getNoClientResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakClient{})
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId=non-existing-client", getNoClientResponder)
+
+			getResourceResponder, _ := httpmock.NewJsonResponder(200, []string{apiProductResourceId})
+			httpmock.RegisterResponder("GET", endpoints.ResourceRegistration+"?exactName=true&name="+apiProductId, getResourceResponder)
+
+			getNoResourceResponder, _ := httpmock.NewJsonResponder(200, []string{})
+			httpmock.RegisterResponder("GET", endpoints.ResourceRegistration+"?exactName=true&name=unknown-api", getNoResourceResponder)
+		})
+
+		It("creates a permission for the client on the API product", func() {
+			var created server.KeycloakPermission
+			httpmock.RegisterResponder("POST", endpoints.Policy+"/"+apiProductResourceId, func(req *http.Request) (*http.Response, error) {
+				Expect(json.NewDecoder(req.Body).Decode(&created)).To(Succeed())
+				return httpmock.NewJsonResponse(200, created)
+			})
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(created.Name).To(Equal(permissionName))
+			Expect(created.Clients).To(ConsistOf(applicationClientId))
+		})
+
+		It("returns not found code when the API product does not exist", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{"unknown-api"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns not found code when the client does not exist", func() {
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: "non-existing-client",
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess404JSONResponse{}))
+		})
+
+		It("deletes the permission for the client on the API product", func() {
+			getPermissionsResponder, _ := httpmock.NewJsonResponder(200, []server.KeycloakPermission{
+				{Id: permissionId, Name: permissionName},
+				{Id: "other-permission", Name: permissionName + "-v2"},
+			})
+			httpmock.RegisterResponder("GET", endpoints.Policy+"?name="+url.QueryEscape(permissionName), getPermissionsResponder)
+
+			deletePermissionResponder, _ := httpmock.NewJsonResponder(204, nil)
+			httpmock.RegisterResponder("DELETE", endpoints.Policy+"/"+permissionId, deletePermissionResponder)
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+			Expect(httpmock.GetCallCountInfo()["DELETE "+endpoints.Policy+"/"+permissionId]).To(Equal(1))
+			Expect(httpmock.GetCallCountInfo()["DELETE "+endpoints.Policy+"/other-permission"]).To(BeZero())
+		})
+	})
+
+	Context("API Product", func() {
+		const (
+			apiProductId         = "tracks-rest-api"
+			apiProductResourceId = "aa6edf59-a4b7-4532-b6b1-a5b423da7809"
+		)
+
+		When("no resource exists", func() {
+			BeforeEach(func() {
+				newResourceResponder, _ := httpmock.NewJsonResponder(201, server.KeycloakResource{
+					Id:   apiProductResourceId,
+					Name: apiProductId,
+				})
+				httpmock.RegisterResponder("POST", endpoints.ResourceRegistration, newResourceResponder)
+
+				getResourceResponder, _ := httpmock.NewJsonResponder(200, []string{})
+				httpmock.RegisterResponder("GET", endpoints.ResourceRegistration+"?exactName=true&name="+apiProductId, getResourceResponder)
+			})
+
+			It("can create an API product", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+				resp201 := resp.(portalv1.CreateAPIProduct201JSONResponse)
+				Expect(resp201.Id).To(Equal(apiProductId))
+			})
+
+			It("returns error code on empty API product id", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct400JSONResponse{}))
+			})
+
+			It("returns not found code on deletion", func() {
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct404JSONResponse{}))
+			})
+		})
+
+		When("resource exists", func() {
+			BeforeEach(func() {
+				conflictResponder, _ := httpmock.NewJsonResponder(409, server.KeycloakError{
+					Error:       "conflict",
+					Description: "Resource with name [" + apiProductId + "] already exists.",
+				})
+				httpmock.RegisterResponder("POST", endpoints.ResourceRegistration, conflictResponder)
+
+				getResourceResponder, _ := httpmock.NewJsonResponder(200, []string{apiProductResourceId})
+				httpmock.RegisterResponder("GET", endpoints.ResourceRegistration+"?exactName=true&name="+apiProductId, getResourceResponder)
+
+				deleteResourceResponder, _ := httpmock.NewJsonResponder(204, nil)
+				httpmock.RegisterResponder("DELETE", endpoints.ResourceRegistration+"/"+apiProductResourceId, deleteResourceResponder)
+			})
+
+			It("returns conflict code on creation", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+			})
+
+			It("can delete the API product", func() {
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			})
+		})
+	})
+
+	Context("Readiness", func() {
+		It("is ready when a management token can be obtained", func() {
+			Expect(s.Ready(ctx)).To(Succeed())
+		})
+
+		It("is not ready when the management client is rejected", func() {
+			invalidClientResponder, _ := httpmock.NewJsonResponder(401, server.KeycloakError{
+				Error:       "invalid_client",
+				Description: "Invalid client or Invalid client credentials",
+			})
+			httpmock.RegisterResponder("POST", endpoints.Tokens, invalidClientResponder)
+
+			Expect(s.Ready(ctx)).To(MatchError(ContainSubstring("invalid_client")))
+		})
+	})
+
+	Context("Management token", func() {
+		var (
+			mu           sync.Mutex
+			grants       []string
+			authHeaders  []string
+			expiresIn    int
+			rejectTokens map[string]bool
+		)
+
+		getClient := func() {
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication404JSONResponse{}))
+		}
+
+		BeforeEach(func() {
+			grants = nil
+			authHeaders = nil
+			expiresIn = 300
+			rejectTokens = map[string]bool{}
+
+			httpmock.RegisterResponder("POST", endpoints.Tokens, func(req *http.Request) (*http.Response, error) {
+				Expect(req.ParseForm()).To(Succeed())
+
+				mu.Lock()
+				defer mu.Unlock()
+				grants = append(grants, req.PostForm.Get("grant_type"))
+				n := strconv.Itoa(len(grants))
+
+				return httpmock.NewJsonResponse(200, server.KeycloakToken{
+					AccessToken:      "access-token-" + n,
+					ExpiresIn:        expiresIn,
+					RefreshToken:     "refresh-token-" + n,
+					RefreshExpiresIn: 1800,
+				})
+			})
+
+			httpmock.RegisterResponder("GET", fakeAdminEndpoint+"/clients?clientId="+applicationClientId, func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				auth := req.Header.Get("Authorization")
+				authHeaders = append(authHeaders, auth)
+				if rejectTokens[auth] {
+					return httpmock.NewJsonResponse(401, server.KeycloakError{Error: "invalid_token"})
+				}
+
+				return httpmock.NewJsonResponse(200, []server.KeycloakClient{})
+			})
+		})
+
+		It("reuses the token until it expires", func() {
+			getClient()
+			getClient()
+
+			Expect(grants).To(Equal([]string{"urn:ietf:params:oauth:grant-type:uma-ticket"}))
+			Expect(authHeaders).To(Equal([]string{"Bearer access-token-1", "Bearer access-token-1"}))
+		})
+
+		It("checks readiness with the cached token", func() {
+			Expect(s.Ready(ctx)).To(Succeed())
+			Expect(s.Ready(ctx)).To(Succeed())
+			getClient()
+
+			Expect(grants).To(Equal([]string{"urn:ietf:params:oauth:grant-type:uma-ticket"}))
+		})
+
+		It("refreshes an expired token with its refresh token", func() {
+			// The token is refreshed halfway through such a short lifetime.
+			expiresIn = 1
+			getClient()
+			time.Sleep(600 * time.Millisecond)
+			getClient()
+
+			Expect(grants).To(Equal([]string{"urn:ietf:params:oauth:grant-type:uma-ticket", "refresh_token"}))
+			Expect(authHeaders).To(Equal([]string{"Bearer access-token-1", "Bearer access-token-2"}))
+		})
+
+		It("retries once with a new token when the token is rejected", func() {
+			rejectTokens["Bearer access-token-1"] = true
+
+			getClient()
+
+			Expect(grants).To(HaveLen(2))
+			Expect(authHeaders).To(Equal([]string{"Bearer access-token-1", "Bearer access-token-2"}))
+		})
+
+		It("does not retry more than once", func() {
+			rejectTokens["Bearer access-token-1"] = true
+			rejectTokens["Bearer access-token-2"] = true
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication500JSONResponse{}))
+			Expect(authHeaders).To(HaveLen(2))
+		})
+
+		It("requests a single token for concurrent requests", func() {
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					getClient()
+				}()
+			}
+			wg.Wait()
+
+			Expect(grants).To(HaveLen(1))
+			Expect(authHeaders).To(HaveLen(10))
 		})
 	})
 })
@@ -2,25 +2,18 @@ package server
 
 import (
 	"context"
-	"log"
-	"net"
-	"net/http"
 
-	"github.com/getkin/kin-openapi/openapi3filter"
 	resty "github.com/go-resty/resty/v2"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
-	middleware "github.com/oapi-codegen/echo-middleware"
 	"github.com/rotisserie/eris"
 	"github.com/spf13/pflag"
 
-	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 )
 
 const wellKnownUmaConfigPath = "/.well-known/uma2-configuration"
 
 type Options struct {
-	Port             string
+	connector.Options
 	Issuer           string
 	MgmtClientId     string
 	MgmtClientSecret string
@@ -33,7 +26,7 @@ type DiscoveredEndpoints struct {
 }
 
 func (o *Options) AddToFlags(flag *pflag.FlagSet) {
-	flag.StringVar(&o.Port, "port", "8080", "Port for HTTP server")
+	o.Options.AddToFlags(flag)
 	flag.StringVar(&o.Issuer, "issuer", "", "Keycloak issuer URL (e.g. https://keycloak.example.com/realms/my-org)")
 	flag.StringVar(&o.MgmtClientId, "client-id", "", "ID of the Keycloak client that is authorised to manage app clients")
 	flag.StringVar(&o.MgmtClientSecret, "client-secret", "", "Secret of the Keycloak client that is authorised to manage app clients")
@@ -57,7 +50,7 @@ func ListenAndServe(ctx context.Context, opts *Options) error {
 		return err
 	}
 
-	client := resty.New()
+	client := resty.New().SetTransport(opts.UpstreamTransport("keycloak", nil))
 
 	umaConfiguration, err := client.R().
 		SetResult(UmaConfiguration{}).
@@ -87,39 +80,5 @@ func ListenAndServe(ctx context.Context, opts *Options) error {
 		Tokens:               tokenEndpoint,
 	}
 
-	swagger, err := portalv1.GetSwagger()
-	if err != nil {
-		return eris.Wrap(err, "could not load swagger spec")
-	}
-
-	// Clear out the servers array in the swagger spec, that skips validating
-	// that server names match. We don't know how this thing will be run.
-	swagger.Servers = nil
-
-	// Create an instance of our handler which satisfies the generated interface
-	keycloakHandler := NewStrictServerHandler(opts, client, discoveredEndpoints)
-	portalHandler := portalv1.NewStrictHandler(keycloakHandler, nil)
-
-	e := echo.New()
-	// Log all requests
-	e.Use(echomiddleware.Logger())
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	e.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
-		Options: openapi3filter.Options{
-			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
-		}},
-	))
-
-	// We now register our portal handler above as the handler for the interface
-	portalv1.RegisterHandlers(e, portalHandler)
-
-	// And we serve HTTP until the world ends.
-	s := &http.Server{
-		Handler: e,
-		Addr:    net.JoinHostPort("0.0.0.0", opts.Port),
-	}
-
-	log.Printf("Starting server on port %v\n", opts.Port)
-	return s.ListenAndServe()
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, client, discoveredEndpoints))
 }
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+// managementTokens requests the management token with a UMA ticket grant, or refreshes it with its refresh token while
+// that is valid. Its fetch func is only called by a single token source, which never calls it concurrently.
+type managementTokens struct {
+	client       *resty.Client
+	endpoint     string
+	clientId     string
+	clientSecret string
+
+	refreshToken  string
+	refreshExpiry time.Time
+}
+
+// fetch refreshes the management token with its refresh token if possible, and otherwise requests a new token.
+func (m *managementTokens) fetch(ctx context.Context) (*connector.Token, error) {
+	now := time.Now()
+
+	var token *KeycloakToken
+	var err error
+	if m.refreshToken != "" && (m.refreshExpiry.IsZero() || now.Before(m.refreshExpiry)) {
+		token, err = m.request(ctx, map[string]string{
+			"grant_type":    "refresh_token",
+			"refresh_token": m.refreshToken,
+		})
+	}
+	// Fall back to a new token if there is no refresh token, or it was rejected.
+	if token == nil {
+		token, err = m.request(ctx, map[string]string{
+			"grant_type": "urn:ietf:params:oauth:grant-type:uma-ticket",
+			"audience":   m.clientId,
+		})
+	}
+	if err != nil {
+		m.refreshToken = ""
+		return nil, err
+	}
+
+	m.refreshToken = token.RefreshToken
+	m.refreshExpiry = time.Time{}
+	if token.RefreshExpiresIn > 0 {
+		m.refreshExpiry = now.Add(connector.TokenLifetime(token.RefreshExpiresIn))
+	}
+
+	return &connector.Token{AccessToken: token.AccessToken, ExpiresIn: token.ExpiresIn}, nil
+}
+
+func (m *managementTokens) request(ctx context.Context, form map[string]string) (*KeycloakToken, error) {
+	var token *KeycloakToken
+	tokenResponse, err := m.client.R().
+		SetContext(connector.WithUpstreamOperation(ctx, "GetToken")).
+		SetBasicAuth(m.clientId, m.clientSecret).
+		SetFormData(form).
+		SetResult(&token).
+		SetError(&KeycloakError{}).
+		Post(m.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenResponse.IsError() {
+		error := tokenResponse.Error().(*KeycloakError)
+		return nil, fmt.Errorf("could not obtain token for client %s: [%s] %s", m.clientId, error.Error, error.Description)
+	}
+
+	return token, nil
+}
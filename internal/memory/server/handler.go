@@ -0,0 +1,392 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+// StrictServerHandler keeps applications and API products in process memory, and issues access tokens to the
+// applications from its own token endpoint. Everything is lost when the process exits.
+type StrictServerHandler struct {
+	mu          sync.RWMutex
+	clients     map[string]*client
+	apiProducts map[string]portalv1.APIProduct
+
+	tokenPort   string
+	tokens      *tokenIssuer
+	tokenServer *http.Server
+}
+
+// client is an application registered with the connector. Only a hash of its secret is kept.
+type client struct {
+	clientId   string
+	clientName string
+	secretHash [sha256.Size]byte
+	scopes     []string
+}
+
+func NewStrictServerHandler(opts *Options) (*StrictServerHandler, error) {
+	tokens, err := newTokenIssuer(opts.Issuer, opts.Audience, opts.TokenLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StrictServerHandler{
+		clients:     map[string]*client{},
+		apiProducts: map[string]portalv1.APIProduct{},
+		tokenPort:   opts.TokenPort,
+		tokens:      tokens,
+	}
+	s.tokens.authenticate = s.authenticate
+
+	return s, nil
+}
+
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "memory"
+}
+
+// Start serves the token endpoint, and the keys that access tokens can be verified with, on the token port.
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", s.tokenPort))
+	if err != nil {
+		return err
+	}
+
+	s.tokenServer = &http.Server{Handler: s.TokenHandler()}
+	go func() {
+		slog.Info("Starting server", "connector", "memory", "server", "token", "address", listener.Addr().String())
+		if err := s.tokenServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Token server stopped", "connector", "memory", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(ctx context.Context) error {
+	if s.tokenServer == nil {
+		return nil
+	}
+
+	return s.tokenServer.Shutdown(ctx)
+}
+
+// Ready always succeeds, since there is no upstream to depend on.
+func (s *StrictServerHandler) Ready(_ context.Context) error {
+	return nil
+}
+
+// TokenHandler returns the handler of the token endpoint and the discovery documents.
+func (s *StrictServerHandler) TokenHandler() http.Handler {
+	return s.tokens.handler()
+}
+
+// CreateOAuthApplication registers a client with a random client ID and secret, named after the Portal application.
+func (s *StrictServerHandler) CreateOAuthApplication(
+	_ context.Context,
+	request portalv1.CreateOAuthApplicationRequestObject,
+) (portalv1.CreateOAuthApplicationResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	clientId, err := randomString(16, hex.EncodeToString)
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.findByName(request.Body.Id); ok {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, existing.clientId)), nil
+	}
+
+	s.clients[clientId] = &client{
+		clientId:   clientId,
+		clientName: request.Body.Id,
+		secretHash: sha256.Sum256([]byte(secret)),
+		scopes:     []string{},
+	}
+
+	return portalv1.CreateOAuthApplication201JSONResponse{
+		ClientId:     clientId,
+		ClientName:   &request.Body.Id,
+		ClientSecret: secret,
+	}, nil
+}
+
+// DeleteOAuthApplication deletes a client by the ID Portal gave it, or by client ID. Tokens that were already issued
+// to it stay valid until they expire. Every client was created by the connector, so none is refused.
+func (s *StrictServerHandler) DeleteOAuthApplication(
+	_ context.Context,
+	request portalv1.DeleteOAuthApplicationRequestObject,
+) (portalv1.DeleteOAuthApplicationResponseObject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.find(request.Id)
+	if !ok {
+		return portalv1.DeleteOAuthApplication404JSONResponse(clientNotFound(request.Id)), nil
+	}
+	delete(s.clients, c.clientId)
+
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications lists the clients in order of client ID. The cursor is the client ID of the last client on
+// the previous page, so clients that are created or deleted between pages do not shift the pages.
+func (s *StrictServerHandler) ListOAuthApplications(
+	_ context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clientIds := slices.Sorted(maps.Keys(s.clients))
+	if request.Params.Cursor != nil {
+		start, _ := slices.BinarySearch(clientIds, *request.Params.Cursor)
+		if start < len(clientIds) && clientIds[start] == *request.Params.Cursor {
+			start++
+		}
+		clientIds = clientIds[start:]
+	}
+
+	page := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+	}
+	if limit := connector.PageLimit(request.Params.Limit); len(clientIds) > limit {
+		clientIds = clientIds[:limit]
+		page.NextCursor = &clientIds[limit-1]
+	}
+	for _, clientId := range clientIds {
+		page.Applications = append(page.Applications, s.clients[clientId].details())
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication gets a client by the ID Portal gave it, or by client ID.
+func (s *StrictServerHandler) GetOAuthApplication(
+	_ context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.find(request.Id)
+	if !ok {
+		return portalv1.GetOAuthApplication404JSONResponse(clientNotFound(request.Id)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(c.details()), nil
+}
+
+// RotateOAuthApplicationSecret replaces the secret of a client with a new random one. The old secret stops working
+// immediately.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	_ context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(connector.NewPortal500Error(err.Error())), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.find(request.Id)
+	if !ok {
+		return portalv1.RotateOAuthApplicationSecret404JSONResponse(clientNotFound(request.Id)), nil
+	}
+	c.secretHash = sha256.Sum256([]byte(secret))
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     c.clientId,
+		ClientName:   &c.clientName,
+		ClientSecret: secret,
+	}, nil
+}
+
+// GrantAPIProductAccess adds the API products to the scopes of the client. The API products must have been created.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	_ context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.find(request.Id)
+	if !ok {
+		return portalv1.GrantAPIProductAccess404JSONResponse(clientNotFound(request.Id)), nil
+	}
+	for _, apiProduct := range request.Body.ApiProducts {
+		if _, ok := s.apiProducts[apiProduct]; !ok {
+			return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error("API product " + apiProduct + " not found")), nil
+		}
+	}
+
+	for _, apiProduct := range request.Body.ApiProducts {
+		if !slices.Contains(c.scopes, apiProduct) {
+			c.scopes = append(c.scopes, apiProduct)
+		}
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the API products from the scopes of the client.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	_ context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.find(request.Id)
+	if !ok {
+		return portalv1.RevokeAPIProductAccess404JSONResponse(clientNotFound(request.Id)), nil
+	}
+	c.scopes = slices.DeleteFunc(c.scopes, func(scope string) bool {
+		return slices.Contains(request.Params.ApiProducts, scope)
+	})
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct registers the API product as a scope that clients can be granted.
+func (s *StrictServerHandler) CreateAPIProduct(
+	_ context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product id is required")), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.apiProducts[request.Body.Id]; ok {
+		return portalv1.CreateAPIProduct409JSONResponse(connector.NewPortal409Error("API product " + request.Body.Id + " already exists")), nil
+	}
+	s.apiProducts[request.Body.Id] = *request.Body
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct deletes the API product, and revokes the access of every client that was granted it.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	_ context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.apiProducts[request.Id]; !ok {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error("API product " + request.Id + " not found")), nil
+	}
+	delete(s.apiProducts, request.Id)
+
+	for _, c := range s.clients {
+		c.scopes = slices.DeleteFunc(c.scopes, func(scope string) bool {
+			return scope == request.Id
+		})
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// find returns the client with the ID Portal gave it, which is its name, or else with the client ID. The caller must
+// hold the lock.
+func (s *StrictServerHandler) find(id string) (*client, bool) {
+	if c, ok := s.findByName(id); ok {
+		return c, true
+	}
+
+	c, ok := s.clients[id]
+	return c, ok
+}
+
+// findByName returns the client with the name. The caller must hold the lock.
+func (s *StrictServerHandler) findByName(name string) (*client, bool) {
+	for _, c := range s.clients {
+		if c.clientName == name {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
+
+// authenticate returns a copy of the client if the secret is its current one.
+func (s *StrictServerHandler) authenticate(clientId, secret string) (client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Compare against a hash even for unknown clients, so that the time taken does not reveal which clients exist.
+	c, ok := s.clients[clientId]
+	var expected [sha256.Size]byte
+	if ok {
+		expected = c.secretHash
+	}
+	actual := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(expected[:], actual[:]) != 1 || !ok {
+		return client{}, false
+	}
+
+	authenticated := *c
+	authenticated.scopes = slices.Clone(c.scopes)
+	return authenticated, true
+}
+
+func (c *client) details() portalv1.OAuthApplicationDetails {
+	return portalv1.OAuthApplicationDetails{
+		ClientId:   c.clientId,
+		ClientName: &c.clientName,
+		Scopes:     slices.Clone(c.scopes),
+	}
+}
+
+func clientNotFound(clientId string) portalv1.Error {
+	return connector.NewPortal404Error("client " + clientId + " not found")
+}
+
+// randomString returns n random bytes in the given encoding.
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encode(b), nil
+}
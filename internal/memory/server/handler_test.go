@@ -0,0 +1,315 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/memory/server"
+	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		s   *server.StrictServerHandler
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		opts := &server.Options{TokenPort: "8081", Audience: "portal", TokenLifetime: time.Hour}
+		Expect(opts.Validate()).To(Succeed())
+
+		var err error
+		s, err = server.NewStrictServerHandler(opts)
+		Expect(err).NotTo(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	create := func(id string) portalv1.CreateOAuthApplication201JSONResponse {
+		resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+			Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication201JSONResponse{}))
+		return resp.(portalv1.CreateOAuthApplication201JSONResponse)
+	}
+
+	createAPIProduct := func(id string) {
+		resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+			Body: &portalv1.CreateAPIProductJSONRequestBody{Id: id},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+	}
+
+	grant := func(clientId string, apiProducts ...string) portalv1.GrantAPIProductAccessResponseObject {
+		resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+			Id:   clientId,
+			Body: &portalv1.GrantAPIProductAccessJSONRequestBody{ApiProducts: apiProducts},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	scopes := func(clientId string) []string {
+		resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: clientId})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+		return resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes
+	}
+
+	When("managing applications", func() {
+		It("creates, gets and deletes an application by the id it was created with", func() {
+			app := create("my-app")
+			Expect(app.ClientId).NotTo(BeEmpty())
+			Expect(app.ClientSecret).NotTo(BeEmpty())
+			Expect(*app.ClientName).To(Equal("my-app"))
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(portalv1.GetOAuthApplication200JSONResponse{
+				ClientId:   app.ClientId,
+				ClientName: app.ClientName,
+				Scopes:     []string{},
+			}))
+
+			resp, err = s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+
+			deleteResp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: "my-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteResp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+
+			deleteResp, err = s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteResp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication404JSONResponse{}))
+		})
+
+		It("returns 409 when creating an application with an id that is in use", func() {
+			app := create("my-app")
+
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{Id: "my-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+			Expect(resp.(portalv1.CreateOAuthApplication409JSONResponse).Reason).To(ContainSubstring(app.ClientId))
+		})
+
+		It("returns 400 when creating an application without an id", func() {
+			resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+				Body: &portalv1.CreateOAuthApplicationJSONRequestBody{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication400JSONResponse{}))
+		})
+
+		It("lists applications a page at a time", func() {
+			for _, id := range []string{"a", "b", "c", "d", "e"} {
+				create(id)
+			}
+
+			var (
+				names  []string
+				cursor *string
+				limit  = 2
+			)
+			for {
+				resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+					Params: portalv1.ListOAuthApplicationsParams{Limit: &limit, Cursor: cursor},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+				Expect(len(page.Applications)).To(BeNumerically("<=", limit))
+				for _, app := range page.Applications {
+					names = append(names, *app.ClientName)
+				}
+
+				if cursor = page.NextCursor; cursor == nil {
+					break
+				}
+			}
+
+			Expect(names).To(ConsistOf("a", "b", "c", "d", "e"))
+		})
+
+		It("rotates the secret of an application", func() {
+			app := create("my-app")
+
+			resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+
+			rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+			Expect(rotated.ClientId).To(Equal(app.ClientId))
+			Expect(rotated.ClientSecret).NotTo(Equal(app.ClientSecret))
+		})
+	})
+
+	When("managing API product access", func() {
+		var clientId string
+
+		BeforeEach(func() {
+			clientId = create("my-app").ClientId
+			createAPIProduct("petstore")
+			createAPIProduct("bookstore")
+		})
+
+		It("grants and revokes access", func() {
+			Expect(grant(clientId, "petstore", "bookstore")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+			Expect(scopes(clientId)).To(ConsistOf("petstore", "bookstore"))
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id:     clientId,
+				Params: portalv1.RevokeAPIProductAccessParams{ApiProducts: []string{"petstore"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+			Expect(scopes(clientId)).To(ConsistOf("bookstore"))
+		})
+
+		It("returns 404 when granting an API product that does not exist", func() {
+			Expect(grant(clientId, "petstore", "unknown")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+			Expect(scopes(clientId)).To(BeEmpty())
+		})
+
+		It("returns 404 when granting access to an unknown application", func() {
+			Expect(grant("unknown", "petstore")).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns 409 when creating an API product that already exists", func() {
+			resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+				Body: &portalv1.CreateAPIProductJSONRequestBody{Id: "petstore"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+		})
+
+		It("revokes access to an API product when it is deleted", func() {
+			grant(clientId, "petstore", "bookstore")
+
+			resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "petstore"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			Expect(scopes(clientId)).To(ConsistOf("bookstore"))
+
+			resp, err = s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{Id: "petstore"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct404JSONResponse{}))
+		})
+	})
+
+	When("issuing access tokens", func() {
+		var (
+			tokenServer *httptest.Server
+			app         portalv1.CreateOAuthApplication201JSONResponse
+		)
+
+		BeforeEach(func() {
+			tokenServer = httptest.NewServer(s.TokenHandler())
+			DeferCleanup(tokenServer.Close)
+
+			app = create("my-app")
+			createAPIProduct("petstore")
+			createAPIProduct("bookstore")
+			grant(app.ClientId, "petstore", "bookstore")
+		})
+
+		requestToken := func(clientId, secret string, form url.Values) *http.Response {
+			req, err := http.NewRequest(http.MethodPost, tokenServer.URL+"/oauth2/token", strings.NewReader(form.Encode()))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth(clientId, secret)
+
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+			return resp
+		}
+
+		tokenError := func(resp *http.Response) string {
+			var body server.TokenError
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			return body.Error
+		}
+
+		It("issues a token that can be verified with the published keys", func() {
+			resp := requestToken(app.ClientId, app.ClientSecret, url.Values{
+				"grant_type": {"client_credentials"},
+				"scope":      {"petstore"},
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var body server.TokenResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.TokenType).To(Equal("Bearer"))
+			Expect(body.ExpiresIn).To(Equal(3600))
+			Expect(body.Scope).To(Equal("petstore"))
+
+			keys, err := jwk.Fetch(ctx, tokenServer.URL+"/.well-known/jwks.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			token, err := jwt.Parse([]byte(body.AccessToken),
+				jwt.WithKeySet(keys, jws.WithInferAlgorithmFromKey(true)),
+				jwt.WithIssuer("http://localhost:8081"),
+				jwt.WithAudience("portal"),
+				jwt.WithSubject(app.ClientId),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			var scope string
+			Expect(token.Get("scope", &scope)).To(Succeed())
+			Expect(scope).To(Equal("petstore"))
+		})
+
+		It("grants every granted scope when none are requested", func() {
+			resp := requestToken(app.ClientId, app.ClientSecret, url.Values{"grant_type": {"client_credentials"}})
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var body server.TokenResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(strings.Fields(body.Scope)).To(ConsistOf("petstore", "bookstore"))
+		})
+
+		It("rejects a wrong secret", func() {
+			resp := requestToken(app.ClientId, "wrong", url.Values{"grant_type": {"client_credentials"}})
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(resp.Header.Get("WWW-Authenticate")).NotTo(BeEmpty())
+			Expect(tokenError(resp)).To(Equal("invalid_client"))
+		})
+
+		It("rejects the old secret once it is rotated", func() {
+			_, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{Id: app.ClientId})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp := requestToken(app.ClientId, app.ClientSecret, url.Values{"grant_type": {"client_credentials"}})
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("rejects a scope that was not granted", func() {
+			resp := requestToken(app.ClientId, app.ClientSecret, url.Values{
+				"grant_type": {"client_credentials"},
+				"scope":      {"petstore admin"},
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(tokenError(resp)).To(Equal("invalid_scope"))
+		})
+
+		It("rejects other grants", func() {
+			resp := requestToken(app.ClientId, app.ClientSecret, url.Values{"grant_type": {"password"}})
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(tokenError(resp)).To(Equal("unsupported_grant_type"))
+		})
+	})
+})
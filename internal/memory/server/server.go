@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
+)
+
+type Options struct {
+	connector.Options
+	TokenPort     string
+	Issuer        string
+	Audience      string
+	TokenLifetime time.Duration
+}
+
+func (o *Options) AddToFlags(flag *pflag.FlagSet) {
+	o.Options.AddToFlags(flag)
+	flag.StringVar(&o.TokenPort, "token-port", "8081", "Port for the OAuth2 token endpoint and JWKS")
+	flag.StringVar(&o.Issuer, "issuer", "", "Issuer of the access tokens, which is where the token endpoint is reachable (default http://localhost:<token-port>)")
+	flag.StringVar(&o.Audience, "audience", "", "Audience of the access tokens, if they should have one")
+	flag.DurationVar(&o.TokenLifetime, "token-lifetime", time.Hour, "Time that access tokens are valid for")
+}
+
+func (o *Options) Validate() error {
+	if o.TokenPort == "" {
+		return eris.New("Token port is required")
+	}
+	if o.TokenLifetime <= 0 {
+		return eris.New("Token lifetime must be positive")
+	}
+
+	if o.Issuer == "" {
+		o.Issuer = "http://localhost:" + o.TokenPort
+	}
+
+	return nil
+}
+
+func ListenAndServe(ctx context.Context, opts *Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	handler, err := NewStrictServerHandler(opts)
+	if err != nil {
+		return err
+	}
+
+	return connector.ListenAndServe(ctx, &opts.Options, handler)
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// tokenIssuer issues access tokens to clients with the client credentials grant, signed with a key that is generated
+// at startup. Tokens issued before a restart cannot be verified afterwards.
+type tokenIssuer struct {
+	issuer   string
+	audience string
+	lifetime time.Duration
+	key      jwk.Key
+	jwks     jwk.Set
+
+	// authenticate returns the client with the client ID if the secret is its current one.
+	authenticate func(clientId, secret string) (client, bool)
+}
+
+// TokenResponse is the successful response of the token endpoint (RFC 6749 section 5.1).
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// TokenError is the error response of the token endpoint (RFC 6749 section 5.2).
+type TokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func newTokenIssuer(issuer, audience string, lifetime time.Duration) (*tokenIssuer, error) {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := jwk.Import(raw)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.Set(jwk.KeyIDKey, base64.RawURLEncoding.EncodeToString(thumbprint)); err != nil {
+		return nil, err
+	}
+	if err := key.Set(jwk.AlgorithmKey, jwa.RS256()); err != nil {
+		return nil, err
+	}
+
+	jwks := jwk.NewSet()
+	public, err := key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := jwks.AddKey(public); err != nil {
+		return nil, err
+	}
+
+	return &tokenIssuer{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		lifetime: lifetime,
+		key:      key,
+		jwks:     jwks,
+	}, nil
+}
+
+func (t *tokenIssuer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth2/token", t.token)
+	mux.HandleFunc("GET /.well-known/jwks.json", t.keys)
+	mux.HandleFunc("GET /.well-known/openid-configuration", t.configuration)
+	return mux
+}
+
+// token issues an access token for the client credentials grant. Clients authenticate with HTTP basic
+// authentication or with credentials in the form. The token has the requested scopes, which must have been granted,
+// or every granted scope if none are requested.
+func (t *tokenIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if grantType := r.PostForm.Get("grant_type"); grantType != "client_credentials" {
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "only the client_credentials grant is supported")
+		return
+	}
+
+	clientId, secret, ok := r.BasicAuth()
+	if !ok {
+		clientId, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	c, ok := t.authenticate(clientId, secret)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+
+	scopes := c.scopes
+	if requested := strings.Fields(r.PostForm.Get("scope")); len(requested) > 0 {
+		for _, scope := range requested {
+			if !slices.Contains(c.scopes, scope) {
+				writeTokenError(w, http.StatusBadRequest, "invalid_scope", "client has not been granted scope "+scope)
+				return
+			}
+		}
+		scopes = requested
+	}
+
+	jti, err := randomString(16, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	issuedAt := time.Now()
+	builder := jwt.NewBuilder().
+		Issuer(t.issuer).
+		Subject(c.clientId).
+		IssuedAt(issuedAt).
+		Expiration(issuedAt.Add(t.lifetime)).
+		JwtID(jti).
+		Claim("client_id", c.clientId)
+	if t.audience != "" {
+		builder = builder.Audience([]string{t.audience})
+	}
+	if len(scopes) > 0 {
+		builder = builder.Claim("scope", strings.Join(scopes, " "))
+	}
+
+	token, err := builder.Build()
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256(), t.key))
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, TokenResponse{
+		AccessToken: string(signed),
+		TokenType:   "Bearer",
+		ExpiresIn:   int(t.lifetime.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	})
+}
+
+// keys serves the public key that access tokens are signed with.
+func (t *tokenIssuer) keys(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, t.jwks)
+}
+
+// configuration serves enough of the OpenID Connect discovery document for gateways to find the keys.
+func (t *tokenIssuer) configuration(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                t.issuer,
+		"token_endpoint":                        t.issuer + "/oauth2/token",
+		"jwks_uri":                              t.issuer + "/.well-known/jwks.json",
+		"grant_types_supported":                 []string{"client_credentials"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, TokenError{Error: code, Description: description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -2,22 +2,31 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"slices"
 
 	"github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
 )
 
-//go:generate mockgen -destination=mock/okta_client.go . OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest
+//go:generate mockgen -destination=mock/okta_client.go . OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiGetApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest,ApplicationSecretsAPI,ApiCreateOAuth2ClientSecretRequest,ApiListOAuth2ClientSecretsRequest,ApiDeactivateOAuth2ClientSecretRequest,ApiDeleteOAuth2ClientSecretRequest,AuthorizationServerScopesAPI,ApiCreateOAuth2ScopeRequest,ApiListOAuth2ScopesRequest,ApiDeleteOAuth2ScopeRequest,AuthorizationServerPoliciesAPI,ApiCreateAuthorizationServerPolicyRequest,ApiListAuthorizationServerPoliciesRequest,ApiDeleteAuthorizationServerPolicyRequest,AuthorizationServerRulesAPI,ApiCreateAuthorizationServerPolicyRuleRequest,ApiListAuthorizationServerPolicyRulesRequest,ApiReplaceAuthorizationServerPolicyRuleRequest
 
 type OktaClient interface {
 	GetApplicationAPI() ApplicationAPI
+	GetApplicationSecretsAPI() ApplicationSecretsAPI
+	GetAuthorizationServerScopesAPI() AuthorizationServerScopesAPI
+	GetAuthorizationServerPoliciesAPI() AuthorizationServerPoliciesAPI
+	GetAuthorizationServerRulesAPI() AuthorizationServerRulesAPI
 }
 
 type ApplicationAPI interface {
 	CreateApplication(ctx context.Context) ApiCreateApplicationRequest
+	GetApplication(ctx context.Context, appId string) ApiGetApplicationRequest
 	ListApplications(ctx context.Context) ApiListApplicationsRequest
 	DeactivateApplication(ctx context.Context, appId string) ApiDeactivateApplicationRequest
 	DeleteApplication(ctx context.Context, appId string) ApiDeleteApplicationRequest
@@ -28,7 +37,15 @@ type ApiCreateApplicationRequest interface {
 	Execute() (*okta.ListApplications200ResponseInner, *okta.APIResponse, error)
 }
 
+type ApiGetApplicationRequest interface {
+	Execute() (*okta.ListApplications200ResponseInner, *okta.APIResponse, error)
+}
+
 type ApiListApplicationsRequest interface {
+	After(after string) ApiListApplicationsRequest
+	Limit(limit int32) ApiListApplicationsRequest
+	Filter(filter string) ApiListApplicationsRequest
+	Q(q string) ApiListApplicationsRequest
 	Execute() ([]okta.ListApplications200ResponseInner, *okta.APIResponse, error)
 }
 
@@ -40,23 +57,139 @@ type ApiDeleteApplicationRequest interface {
 	Execute() (*okta.APIResponse, error)
 }
 
+type ApplicationSecretsAPI interface {
+	CreateOAuth2ClientSecret(ctx context.Context, appId string) ApiCreateOAuth2ClientSecretRequest
+	ListOAuth2ClientSecrets(ctx context.Context, appId string) ApiListOAuth2ClientSecretsRequest
+	DeactivateOAuth2ClientSecret(ctx context.Context, appId string, secretId string) ApiDeactivateOAuth2ClientSecretRequest
+	DeleteOAuth2ClientSecret(ctx context.Context, appId string, secretId string) ApiDeleteOAuth2ClientSecretRequest
+}
+
+type ApiCreateOAuth2ClientSecretRequest interface {
+	Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error)
+}
+
+type ApiListOAuth2ClientSecretsRequest interface {
+	Execute() ([]okta.OAuth2ClientSecret, *okta.APIResponse, error)
+}
+
+type ApiDeactivateOAuth2ClientSecretRequest interface {
+	Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error)
+}
+
+type ApiDeleteOAuth2ClientSecretRequest interface {
+	Execute() (*okta.APIResponse, error)
+}
+
+type AuthorizationServerScopesAPI interface {
+	CreateOAuth2Scope(ctx context.Context, authServerId string) ApiCreateOAuth2ScopeRequest
+	ListOAuth2Scopes(ctx context.Context, authServerId string) ApiListOAuth2ScopesRequest
+	DeleteOAuth2Scope(ctx context.Context, authServerId string, scopeId string) ApiDeleteOAuth2ScopeRequest
+}
+
+type ApiCreateOAuth2ScopeRequest interface {
+	OAuth2Scope(oAuth2Scope okta.OAuth2Scope) ApiCreateOAuth2ScopeRequest
+	Execute() (*okta.OAuth2Scope, *okta.APIResponse, error)
+}
+
+type ApiListOAuth2ScopesRequest interface {
+	Q(q string) ApiListOAuth2ScopesRequest
+	Execute() ([]okta.OAuth2Scope, *okta.APIResponse, error)
+}
+
+type ApiDeleteOAuth2ScopeRequest interface {
+	Execute() (*okta.APIResponse, error)
+}
+
+type AuthorizationServerPoliciesAPI interface {
+	CreateAuthorizationServerPolicy(ctx context.Context, authServerId string) ApiCreateAuthorizationServerPolicyRequest
+	ListAuthorizationServerPolicies(ctx context.Context, authServerId string) ApiListAuthorizationServerPoliciesRequest
+	DeleteAuthorizationServerPolicy(ctx context.Context, authServerId string, policyId string) ApiDeleteAuthorizationServerPolicyRequest
+}
+
+type ApiCreateAuthorizationServerPolicyRequest interface {
+	Policy(policy okta.AuthorizationServerPolicy) ApiCreateAuthorizationServerPolicyRequest
+	Execute() (*okta.AuthorizationServerPolicy, *okta.APIResponse, error)
+}
+
+type ApiListAuthorizationServerPoliciesRequest interface {
+	Execute() ([]okta.AuthorizationServerPolicy, *okta.APIResponse, error)
+}
+
+type ApiDeleteAuthorizationServerPolicyRequest interface {
+	Execute() (*okta.APIResponse, error)
+}
+
+type AuthorizationServerRulesAPI interface {
+	CreateAuthorizationServerPolicyRule(ctx context.Context, authServerId string, policyId string) ApiCreateAuthorizationServerPolicyRuleRequest
+	ListAuthorizationServerPolicyRules(ctx context.Context, authServerId string, policyId string) ApiListAuthorizationServerPolicyRulesRequest
+	ReplaceAuthorizationServerPolicyRule(ctx context.Context, authServerId string, policyId string, ruleId string) ApiReplaceAuthorizationServerPolicyRuleRequest
+}
+
+type ApiCreateAuthorizationServerPolicyRuleRequest interface {
+	PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) ApiCreateAuthorizationServerPolicyRuleRequest
+	Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error)
+}
+
+type ApiListAuthorizationServerPolicyRulesRequest interface {
+	Execute() ([]okta.AuthorizationServerPolicyRule, *okta.APIResponse, error)
+}
+
+type ApiReplaceAuthorizationServerPolicyRuleRequest interface {
+	PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) ApiReplaceAuthorizationServerPolicyRuleRequest
+	Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error)
+}
+
 type StrictServerHandler struct {
-	oktaClient OktaClient
+	oktaClient          OktaClient
+	authorizationServer string
+	forceDelete         bool
 }
 
-func NewStrictServerHandler(oktaClient OktaClient) *StrictServerHandler {
+func NewStrictServerHandler(opts *Options, oktaClient OktaClient) *StrictServerHandler {
 	return &StrictServerHandler{
-		oktaClient: oktaClient,
+		oktaClient:          oktaClient,
+		authorizationServer: opts.AuthorizationServer,
+		forceDelete:         opts.ForceDelete,
 	}
 }
 
+var _ connector.Provider = &StrictServerHandler{}
+
+func (s *StrictServerHandler) Name() string {
+	return "okta"
+}
+
+func (s *StrictServerHandler) Start(_ context.Context) error {
+	return nil
+}
+
+func (s *StrictServerHandler) Stop(_ context.Context) error {
+	return nil
+}
+
+// Ready checks that the API token is accepted by Okta by listing a single application.
+func (s *StrictServerHandler) Ready(ctx context.Context) error {
+	_, _, err := s.oktaClient.GetApplicationAPI().ListApplications(ctx).Limit(1).Execute()
+	return err
+}
+
 // CreateOAuthApplication creates a client in Okta
 func (s *StrictServerHandler) CreateOAuthApplication(
 	ctx context.Context,
 	request portalv1.CreateOAuthApplicationRequestObject,
 ) (portalv1.CreateOAuthApplicationResponseObject, error) {
 	if request.Body == nil || len(request.Body.Id) == 0 {
-		return portalv1.CreateOAuthApplication400JSONResponse(newPortal400Error("unique id is required")), nil
+		return portalv1.CreateOAuthApplication400JSONResponse(connector.NewPortal400Error("unique id is required")), nil
+	}
+
+	existing, resp, err := s.searchApplication(ctx, request.Body.Id, func(app *okta.OpenIdConnectApplication) bool {
+		return app.GetLabel() == request.Body.Id
+	})
+	if err != nil {
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+	if existing != nil {
+		return portalv1.CreateOAuthApplication409JSONResponse(connector.NewApplicationExistsError(request.Body.Id, applicationClientId(existing))), nil
 	}
 
 	// Create OAuth 2.0 Service Application in Okta using SDK
@@ -78,6 +211,13 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 
 	app := okta.NewOpenIdConnectApplication(*credentials, "oidc_client", *settings, request.Body.Id, "OPENID_CONNECT")
 
+	// Mark the application as ours in its profile, which Okta keeps as is.
+	profile := map[string]interface{}{}
+	for key, value := range connector.NewOwnership(request.Body.Id).Metadata() {
+		profile[key] = value
+	}
+	app.SetProfile(profile)
+
 	// Create the application - wrap in union type
 	appUnion := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(app)
 
@@ -87,12 +227,12 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 		Execute()
 
 	if err != nil {
-		return portalv1.CreateOAuthApplication500JSONResponse(unwrapSDKError(resp.Response, err)), nil
+		return portalv1.CreateOAuthApplication500JSONResponse(unwrapSDKError(resp, err)), nil
 	}
 
 	// Extract the OpenIdConnectApplication from the union type
 	if createdAppUnion == nil || createdAppUnion.OpenIdConnectApplication == nil {
-		return portalv1.CreateOAuthApplication500JSONResponse(newPortal500Error("unexpected application type returned")), nil
+		return portalv1.CreateOAuthApplication500JSONResponse(connector.NewPortal500Error("unexpected application type returned")), nil
 	}
 
 	oidcApp := createdAppUnion.OpenIdConnectApplication
@@ -118,99 +258,647 @@ func (s *StrictServerHandler) CreateOAuthApplication(
 	}, nil
 }
 
-// DeleteOAuthApplication deletes a client in Okta by ID.
+// DeleteOAuthApplication deletes a client in Okta by the ID Portal gave it, which is its label, or by client ID.
+// Applications without the ownership profile are only deleted when deletes are forced.
 func (s *StrictServerHandler) DeleteOAuthApplication(
 	ctx context.Context,
 	request portalv1.DeleteOAuthApplicationRequestObject,
 ) (portalv1.DeleteOAuthApplicationResponseObject, error) {
 	if len(request.Id) == 0 {
-		return portalv1.DeleteOAuthApplication500JSONResponse(newPortal500Error("client ID is required")), nil
+		return portalv1.DeleteOAuthApplication500JSONResponse(connector.NewPortal500Error("client ID is required")), nil
 	}
 
 	// First, find the application by searching for apps with matching label
-	apps, resp, err := s.oktaClient.GetApplicationAPI().
-		ListApplications(ctx).
+	app, resp, err := s.findApplication(ctx, request.Id)
+	if err != nil {
+		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if app == nil {
+		return portalv1.DeleteOAuthApplication404JSONResponse(portalv1.Error{
+			Code:    404,
+			Message: "Not Found",
+			Reason:  fmt.Sprintf("Application '%s' not found", request.Id),
+		}), nil
+	}
+
+	if !s.forceDelete && !isOwned(app) {
+		return portalv1.DeleteOAuthApplication403JSONResponse(connector.NewNotOwnedError(request.Id)), nil
+	}
+
+	targetAppId := app.GetId()
+
+	// Step 1: Deactivate the application first (Okta requires this before deletion)
+	deactivateResp, err := s.oktaClient.GetApplicationAPI().
+		DeactivateApplication(ctx, targetAppId).
+		Execute()
+
+	if err != nil {
+		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapSDKError(deactivateResp, err)), nil
+	}
+
+	// Step 2: Now delete the deactivated application
+	deleteResp, err := s.oktaClient.GetApplicationAPI().
+		DeleteApplication(ctx, targetAppId).
 		Execute()
 
 	if err != nil {
-		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapSDKError(resp.Response, err)), nil
+		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapSDKError(deleteResp, err)), nil
 	}
 
-	// Find the app with matching label, name, ID, or client ID
-	var targetAppId string
+	return portalv1.DeleteOAuthApplication204Response{}, nil
+}
+
+// ListOAuthApplications lists the OIDC applications in Okta that the connector created, along with the API product
+// scopes they are granted. The cursor is Okta's own pagination cursor.
+func (s *StrictServerHandler) ListOAuthApplications(
+	ctx context.Context,
+	request portalv1.ListOAuthApplicationsRequestObject,
+) (portalv1.ListOAuthApplicationsResponseObject, error) {
+	listReq := s.oktaClient.GetApplicationAPI().
+		ListApplications(ctx).
+		Filter(oidcApplicationFilter).
+		Limit(int32(connector.PageLimit(request.Params.Limit)))
+	if request.Params.Cursor != nil {
+		listReq = listReq.After(*request.Params.Cursor)
+	}
 
+	apps, listResp, err := listReq.Execute()
+	if err != nil {
+		switch oktaErr := unwrapSDKError(listResp, err); oktaErr.Code {
+		case 400:
+			return portalv1.ListOAuthApplications400JSONResponse(oktaErr), nil
+		default:
+			return portalv1.ListOAuthApplications500JSONResponse(oktaErr), nil
+		}
+	}
+
+	policies, resp, err := s.oktaClient.GetAuthorizationServerPoliciesAPI().
+		ListAuthorizationServerPolicies(ctx, s.authorizationServer).
+		Execute()
+
+	if err != nil {
+		return portalv1.ListOAuthApplications500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	page := portalv1.ListOAuthApplications200JSONResponse{
+		Applications: []portalv1.OAuthApplicationDetails{},
+		NextCursor:   nextPageCursor(listResp),
+	}
 	for _, appUnion := range apps {
-		var appId string
-		var appLabel string
-		var appName string
-		var clientId string
+		app := appUnion.OpenIdConnectApplication
+		if app == nil || !isOwned(app) {
+			continue
+		}
 
-		// Try OpenIdConnectApplication
-		if appUnion.OpenIdConnectApplication != nil {
-			app := appUnion.OpenIdConnectApplication
-			if id, ok := app.GetIdOk(); ok && id != nil {
-				appId = *id
+		_, rule, resp, err := s.accessPolicy(ctx, policies, applicationClientId(app))
+		if err != nil {
+			return portalv1.ListOAuthApplications500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+
+		page.Applications = append(page.Applications, applicationDetails(app, rule))
+	}
+
+	return page, nil
+}
+
+// GetOAuthApplication gets an OIDC application in Okta by the ID Portal gave it, which is its label, or by client ID.
+func (s *StrictServerHandler) GetOAuthApplication(
+	ctx context.Context,
+	request portalv1.GetOAuthApplicationRequestObject,
+) (portalv1.GetOAuthApplicationResponseObject, error) {
+	app, resp, err := s.findApplication(ctx, request.Id)
+	if err != nil {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if app == nil {
+		return portalv1.GetOAuthApplication404JSONResponse(connector.NewPortal404Error(fmt.Sprintf("Application '%s' not found", request.Id))), nil
+	}
+
+	_, rule, resp, err := s.findAccessPolicy(ctx, applicationClientId(app))
+	if err != nil {
+		return portalv1.GetOAuthApplication500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	return portalv1.GetOAuthApplication200JSONResponse(applicationDetails(app, rule)), nil
+}
+
+// RotateOAuthApplicationSecret adds a new client secret to the OIDC application in Okta, then deactivates and deletes
+// its previous secrets. Okta allows at most two secrets per application, so inactive secrets are deleted before the
+// new secret is added, and the new secret is deleted again if the previous secrets cannot be, so that the rotation can
+// be retried.
+func (s *StrictServerHandler) RotateOAuthApplicationSecret(
+	ctx context.Context,
+	request portalv1.RotateOAuthApplicationSecretRequestObject,
+) (portalv1.RotateOAuthApplicationSecretResponseObject, error) {
+	app, resp, err := s.findApplication(ctx, request.Id)
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if app == nil {
+		return portalv1.RotateOAuthApplicationSecret404JSONResponse(connector.NewPortal404Error(fmt.Sprintf("Application '%s' not found", request.Id))), nil
+	}
+
+	if !isOwned(app) {
+		return portalv1.RotateOAuthApplicationSecret403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	secretsAPI := s.oktaClient.GetApplicationSecretsAPI()
+
+	previous, resp, err := secretsAPI.ListOAuth2ClientSecrets(ctx, app.GetId()).Execute()
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	var active []okta.OAuth2ClientSecret
+	for _, old := range previous {
+		if old.GetStatus() == "ACTIVE" {
+			active = append(active, old)
+			continue
+		}
+
+		resp, err = secretsAPI.DeleteOAuth2ClientSecret(ctx, app.GetId(), old.GetId()).Execute()
+		if err != nil {
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+	}
+
+	secret, resp, err := secretsAPI.CreateOAuth2ClientSecret(ctx, app.GetId()).Execute()
+	if err != nil {
+		return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	for _, old := range active {
+		resp, err = s.deleteClientSecret(ctx, app.GetId(), old.GetId())
+		if err != nil {
+			if _, rollbackErr := s.deleteClientSecret(ctx, app.GetId(), secret.GetId()); rollbackErr != nil {
+				connector.Logger(ctx).Error("Could not delete the new client secret of a failed rotation",
+					"application", app.GetId(), "secret", secret.GetId(), "error", rollbackErr)
 			}
-			if label, ok := app.GetLabelOk(); ok && label != nil {
-				appLabel = *label
+			return portalv1.RotateOAuthApplicationSecret500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+	}
+
+	return portalv1.RotateOAuthApplicationSecret200JSONResponse{
+		ClientId:     applicationClientId(app),
+		ClientSecret: secret.GetClientSecret(),
+		ClientName:   okta.PtrString(app.GetLabel()),
+	}, nil
+}
+
+// deleteClientSecret deactivates and deletes a client secret of the application, as Okta only deletes inactive secrets.
+func (s *StrictServerHandler) deleteClientSecret(ctx context.Context, appId, secretId string) (*okta.APIResponse, error) {
+	secretsAPI := s.oktaClient.GetApplicationSecretsAPI()
+
+	_, resp, err := secretsAPI.DeactivateOAuth2ClientSecret(ctx, appId, secretId).Execute()
+	if err != nil {
+		return resp, err
+	}
+
+	return secretsAPI.DeleteOAuth2ClientSecret(ctx, appId, secretId).Execute()
+}
+
+// GrantAPIProductAccess allows the application to request the scopes of the given API products. Each application gets
+// its own access policy on the authorization server, with a single client credentials rule listing its scopes. The
+// policy is created without a priority, so that Okta adds it after the existing policies, and policies that an
+// administrator created for the application still take precedence.
+func (s *StrictServerHandler) GrantAPIProductAccess(
+	ctx context.Context,
+	request portalv1.GrantAPIProductAccessRequestObject,
+) (portalv1.GrantAPIProductAccessResponseObject, error) {
+	if request.Body == nil || len(request.Body.ApiProducts) == 0 {
+		return portalv1.GrantAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	app, resp, err := s.findApplication(ctx, request.Id)
+	if err != nil {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if app == nil {
+		return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error(fmt.Sprintf("Application '%s' not found", request.Id))), nil
+	}
+
+	if !isOwned(app) {
+		return portalv1.GrantAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	for _, apiProduct := range request.Body.ApiProducts {
+		scope, resp, err := s.findScope(ctx, apiProduct)
+		if err != nil {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+
+		if scope == nil {
+			return portalv1.GrantAPIProductAccess404JSONResponse(connector.NewPortal404Error("API product " + apiProduct + " not found")), nil
+		}
+	}
+
+	clientId := applicationClientId(app)
+	policy, rule, resp, err := s.findAccessPolicy(ctx, clientId)
+	if err != nil {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if policy == nil {
+		newPolicy := okta.NewAuthorizationServerPolicy()
+		newPolicy.SetName(accessPolicyName(clientId))
+		newPolicy.SetDescription("Grants application " + request.Id + " access to its Gloo Portal API products")
+		newPolicy.SetType("OAUTH_AUTHORIZATION_POLICY")
+		newPolicy.SetStatus("ACTIVE")
+		newPolicy.SetConditions(okta.AuthorizationServerPolicyConditions{
+			Clients: &okta.ClientPolicyCondition{Include: []string{clientId}},
+		})
+
+		policy, resp, err = s.oktaClient.GetAuthorizationServerPoliciesAPI().
+			CreateAuthorizationServerPolicy(ctx, s.authorizationServer).
+			Policy(*newPolicy).
+			Execute()
+
+		if err != nil {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+	}
+
+	if rule == nil {
+		_, resp, err = s.oktaClient.GetAuthorizationServerRulesAPI().
+			CreateAuthorizationServerPolicyRule(ctx, s.authorizationServer, policy.GetId()).
+			PolicyRule(newAccessRule(request.Body.ApiProducts)).
+			Execute()
+
+		if err != nil {
+			return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+		}
+
+		return portalv1.GrantAPIProductAccess204Response{}, nil
+	}
+
+	scopes := ruleScopes(rule)
+	for _, apiProduct := range request.Body.ApiProducts {
+		if !slices.Contains(scopes, apiProduct) {
+			scopes = append(scopes, apiProduct)
+		}
+	}
+
+	_, resp, err = s.oktaClient.GetAuthorizationServerRulesAPI().
+		ReplaceAuthorizationServerPolicyRule(ctx, s.authorizationServer, policy.GetId(), rule.GetId()).
+		PolicyRule(newAccessRule(scopes)).
+		Execute()
+
+	if err != nil {
+		return portalv1.GrantAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	return portalv1.GrantAPIProductAccess204Response{}, nil
+}
+
+// RevokeAPIProductAccess removes the scopes of the given API products from the application's access policy, deleting
+// the policy once no scopes remain.
+func (s *StrictServerHandler) RevokeAPIProductAccess(
+	ctx context.Context,
+	request portalv1.RevokeAPIProductAccessRequestObject,
+) (portalv1.RevokeAPIProductAccessResponseObject, error) {
+	if len(request.Params.ApiProducts) == 0 {
+		return portalv1.RevokeAPIProductAccess400JSONResponse(connector.NewPortal400Error("at least one API product is required")), nil
+	}
+
+	app, resp, err := s.findApplication(ctx, request.Id)
+	if err != nil {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if app == nil {
+		return portalv1.RevokeAPIProductAccess404JSONResponse(connector.NewPortal404Error(fmt.Sprintf("Application '%s' not found", request.Id))), nil
+	}
+
+	if !isOwned(app) {
+		return portalv1.RevokeAPIProductAccess403JSONResponse(connector.NewNotOwnedChangeError(request.Id)), nil
+	}
+
+	policy, rule, resp, err := s.findAccessPolicy(ctx, applicationClientId(app))
+	if err != nil {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if policy == nil || rule == nil {
+		return portalv1.RevokeAPIProductAccess204Response{}, nil
+	}
+
+	var scopes []string
+	for _, scope := range ruleScopes(rule) {
+		if !slices.Contains(request.Params.ApiProducts, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		resp, err = s.oktaClient.GetAuthorizationServerPoliciesAPI().
+			DeleteAuthorizationServerPolicy(ctx, s.authorizationServer, policy.GetId()).
+			Execute()
+	} else {
+		_, resp, err = s.oktaClient.GetAuthorizationServerRulesAPI().
+			ReplaceAuthorizationServerPolicyRule(ctx, s.authorizationServer, policy.GetId(), rule.GetId()).
+			PolicyRule(newAccessRule(scopes)).
+			Execute()
+	}
+
+	if err != nil {
+		return portalv1.RevokeAPIProductAccess500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	return portalv1.RevokeAPIProductAccess204Response{}, nil
+}
+
+// CreateAPIProduct creates a custom scope for the API product on the Okta authorization server.
+func (s *StrictServerHandler) CreateAPIProduct(
+	ctx context.Context,
+	request portalv1.CreateAPIProductRequestObject,
+) (portalv1.CreateAPIProductResponseObject, error) {
+	if request.Body == nil || len(request.Body.Id) == 0 {
+		return portalv1.CreateAPIProduct400JSONResponse(connector.NewPortal400Error("API product id is required")), nil
+	}
+
+	existing, resp, err := s.findScope(ctx, request.Body.Id)
+	if err != nil {
+		return portalv1.CreateAPIProduct500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if existing != nil {
+		return portalv1.CreateAPIProduct409JSONResponse(connector.NewPortal409Error("API product " + request.Body.Id + " already exists")), nil
+	}
+
+	scope := okta.NewOAuth2Scope(request.Body.Id)
+	scope.SetConsent("IMPLICIT")
+	scope.SetMetadataPublish("NO_CLIENTS")
+	if request.Body.Description != nil {
+		scope.SetDescription(*request.Body.Description)
+	}
+
+	_, resp, err = s.oktaClient.GetAuthorizationServerScopesAPI().
+		CreateOAuth2Scope(ctx, s.authorizationServer).
+		OAuth2Scope(*scope).
+		Execute()
+
+	if err != nil {
+		return portalv1.CreateAPIProduct500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	return portalv1.CreateAPIProduct201JSONResponse(*request.Body), nil
+}
+
+// DeleteAPIProduct deletes the custom scope for the API product from the Okta authorization server.
+func (s *StrictServerHandler) DeleteAPIProduct(
+	ctx context.Context,
+	request portalv1.DeleteAPIProductRequestObject,
+) (portalv1.DeleteAPIProductResponseObject, error) {
+	existing, resp, err := s.findScope(ctx, request.Id)
+	if err != nil {
+		return portalv1.DeleteAPIProduct500JSONResponse(unwrapSDKError(resp, err)), nil
+	}
+
+	if existing == nil {
+		return portalv1.DeleteAPIProduct404JSONResponse(connector.NewPortal404Error("API product " + request.Id + " not found")), nil
+	}
+
+	resp, err = s.oktaClient.GetAuthorizationServerScopesAPI().
+		DeleteOAuth2Scope(ctx, s.authorizationServer, existing.GetId()).
+		Execute()
+
+	if err != nil {
+		switch portalErr := unwrapSDKError(resp, err); portalErr.Code {
+		case 404:
+			return portalv1.DeleteAPIProduct404JSONResponse(portalErr), nil
+		default:
+			return portalv1.DeleteAPIProduct500JSONResponse(portalErr), nil
+		}
+	}
+
+	return portalv1.DeleteAPIProduct204Response{}, nil
+}
+
+// findApplication returns the OIDC application whose label, ID or client ID matches the given ID, or nil if there is
+// none. Okta uses the ID of an OIDC application as its client ID, so an ID that looks like one is fetched directly.
+// Otherwise, or if there is no such application, the applications whose label starts with the ID are searched for one
+// that matches exactly.
+func (s *StrictServerHandler) findApplication(ctx context.Context, id string) (*okta.OpenIdConnectApplication, *okta.APIResponse, error) {
+	if applicationIdPattern.MatchString(id) {
+		appUnion, resp, err := s.oktaClient.GetApplicationAPI().
+			GetApplication(ctx, id).
+			Execute()
+
+		switch {
+		case err == nil:
+			if appUnion != nil && appUnion.OpenIdConnectApplication != nil {
+				return appUnion.OpenIdConnectApplication, resp, nil
 			}
-			if name, ok := app.GetNameOk(); ok && name != nil {
-				appName = *name
+		case resp == nil || resp.Response == nil || resp.StatusCode != http.StatusNotFound:
+			return nil, resp, err
+		}
+	}
+
+	return s.searchApplication(ctx, id, func(app *okta.OpenIdConnectApplication) bool {
+		return app.GetLabel() == id || app.GetId() == id || applicationClientId(app) == id
+	})
+}
+
+// searchApplication returns the first OIDC application that match accepts among the applications whose name or label
+// starts with q, or nil if there is none.
+func (s *StrictServerHandler) searchApplication(
+	ctx context.Context,
+	q string,
+	match func(app *okta.OpenIdConnectApplication) bool,
+) (*okta.OpenIdConnectApplication, *okta.APIResponse, error) {
+	var after *string
+	for {
+		listReq := s.oktaClient.GetApplicationAPI().
+			ListApplications(ctx).
+			Q(q).
+			Filter(oidcApplicationFilter).
+			Limit(searchPageLimit)
+		if after != nil {
+			listReq = listReq.After(*after)
+		}
+
+		apps, resp, err := listReq.Execute()
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, appUnion := range apps {
+			app := appUnion.OpenIdConnectApplication
+			if app == nil {
+				continue
 			}
-			creds := app.GetCredentials()
-			if oauthCreds, ok := creds.GetOauthClientOk(); ok && oauthCreds != nil {
-				if id, ok := oauthCreds.GetClientIdOk(); ok && id != nil {
-					clientId = *id
-				}
+
+			if match(app) {
+				return app, resp, nil
 			}
 		}
 
-		// Try matching by label, name, internal ID, or OAuth client ID
-		if appLabel == request.Id ||
-			appName == request.Id ||
-			appId == request.Id ||
-			clientId == request.Id {
-			targetAppId = appId
-			break
+		if after = nextPageCursor(resp); after == nil {
+			return nil, resp, nil
 		}
 	}
+}
 
-	if targetAppId == "" {
-		reason := fmt.Sprintf("Application '%s' not found. Found %d applications",
-			request.Id, len(apps))
-		return portalv1.DeleteOAuthApplication404JSONResponse(portalv1.Error{
-			Code:    404,
-			Message: "Not Found",
-			Reason:  reason,
-		}), nil
+// findAccessPolicy lists the policies on the authorization server and returns the client's access policy and rule, as
+// accessPolicy does.
+func (s *StrictServerHandler) findAccessPolicy(
+	ctx context.Context,
+	clientId string,
+) (*okta.AuthorizationServerPolicy, *okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	policies, resp, err := s.oktaClient.GetAuthorizationServerPoliciesAPI().
+		ListAuthorizationServerPolicies(ctx, s.authorizationServer).
+		Execute()
+
+	if err != nil {
+		return nil, nil, resp, err
 	}
 
-	// Step 1: Deactivate the application first (Okta requires this before deletion)
-	_, err = s.oktaClient.GetApplicationAPI().
-		DeactivateApplication(ctx, targetAppId).
+	return s.accessPolicy(ctx, policies, clientId)
+}
+
+// accessPolicy returns the access policy for the client from the given policies on the authorization server and its
+// client credentials rule. Either may be nil if it does not exist yet.
+func (s *StrictServerHandler) accessPolicy(
+	ctx context.Context,
+	policies []okta.AuthorizationServerPolicy,
+	clientId string,
+) (*okta.AuthorizationServerPolicy, *okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	idx := slices.IndexFunc(policies, func(policy okta.AuthorizationServerPolicy) bool {
+		return policy.GetName() == accessPolicyName(clientId)
+	})
+	if idx < 0 {
+		return nil, nil, nil, nil
+	}
+	policy := &policies[idx]
+
+	rules, resp, err := s.oktaClient.GetAuthorizationServerRulesAPI().
+		ListAuthorizationServerPolicyRules(ctx, s.authorizationServer, policy.GetId()).
 		Execute()
 
 	if err != nil {
-		return portalv1.DeleteOAuthApplication500JSONResponse(portalv1.Error{
-			Code:    500,
-			Message: "Failed to deactivate application",
-			Reason:  fmt.Sprintf("Error deactivating app before delete: %v", err),
-		}), nil
+		return nil, nil, resp, err
 	}
 
-	// Step 2: Now delete the deactivated application
-	deleteResp, err := s.oktaClient.GetApplicationAPI().
-		DeleteApplication(ctx, targetAppId).
+	for i := range rules {
+		if rules[i].GetName() == accessRuleName {
+			return policy, &rules[i], resp, nil
+		}
+	}
+
+	return policy, nil, resp, nil
+}
+
+// findScope returns the scope on the authorization server with the given name, or nil if there is none.
+func (s *StrictServerHandler) findScope(ctx context.Context, name string) (*okta.OAuth2Scope, *okta.APIResponse, error) {
+	// q matches scopes by name prefix, so the results still need to be checked for an exact match.
+	scopes, resp, err := s.oktaClient.GetAuthorizationServerScopesAPI().
+		ListOAuth2Scopes(ctx, s.authorizationServer).
+		Q(name).
 		Execute()
 
 	if err != nil {
-		return portalv1.DeleteOAuthApplication500JSONResponse(unwrapSDKError(deleteResp.Response, err)), nil
+		return nil, resp, err
 	}
 
-	return portalv1.DeleteOAuthApplication204Response{}, nil
+	for i := range scopes {
+		if scopes[i].Name == name {
+			return &scopes[i], resp, nil
+		}
+	}
+
+	return nil, resp, nil
+}
+
+// oidcApplicationFilter limits application listings to the OIDC applications that the connector creates.
+const oidcApplicationFilter = `name eq "oidc_client"`
+
+// searchPageLimit is the number of applications fetched per page when searching for one, which is the most Okta allows.
+const searchPageLimit = 200
+
+// applicationIdPattern matches the IDs that Okta gives applications, which are also the client IDs of OIDC
+// applications.
+var applicationIdPattern = regexp.MustCompile(`^0oa[0-9A-Za-z]+$`)
+
+// accessRuleName is the name of the rule in each application's access policy that lists its API product scopes.
+const accessRuleName = "Gloo Portal API products"
+
+// accessPolicyName returns the name of the access policy that grants a client access to its API products.
+func accessPolicyName(clientId string) string {
+	return "Gloo Portal " + clientId
 }
 
-func unwrapSDKError(resp *http.Response, err error) portalv1.Error {
+// newAccessRule returns a client credentials rule that allows requesting the given scopes.
+func newAccessRule(scopes []string) okta.AuthorizationServerPolicyRuleRequest {
+	conditions := okta.NewAuthorizationServerPolicyRuleConditions()
+	conditions.SetGrantTypes(okta.GrantTypePolicyRuleCondition{Include: []string{"client_credentials"}})
+	conditions.SetPeople(okta.AuthorizationServerPolicyPeopleCondition{
+		Groups: &okta.AuthorizationServerPolicyRuleGroupCondition{Include: []string{"EVERYONE"}},
+	})
+	conditions.SetScopes(okta.OAuth2ScopesMediationPolicyRuleCondition{Include: scopes})
+
+	rule := okta.NewAuthorizationServerPolicyRuleRequest(*conditions, accessRuleName, "RESOURCE_ACCESS")
+	rule.SetStatus("ACTIVE")
+	return *rule
+}
+
+// applicationDetails returns the application without its secret, along with the scopes listed in its access rule.
+func applicationDetails(app *okta.OpenIdConnectApplication, rule *okta.AuthorizationServerPolicyRule) portalv1.OAuthApplicationDetails {
+	scopes := []string{}
+	if rule != nil {
+		scopes = append(scopes, ruleScopes(rule)...)
+	}
+
+	return portalv1.OAuthApplicationDetails{
+		ClientId:   applicationClientId(app),
+		ClientName: okta.PtrString(app.GetLabel()),
+		Scopes:     scopes,
+	}
+}
+
+// nextPageCursor returns the cursor of the next page from the Link header of a list response, or nil if there is no
+// next page.
+func nextPageCursor(apiResp *okta.APIResponse) *string {
+	if apiResp == nil || apiResp.Response == nil {
+		return nil
+	}
+
+	return connector.NextPageCursor(apiResp.Header, "after")
+}
+
+func ruleScopes(rule *okta.AuthorizationServerPolicyRule) []string {
+	conditions := rule.GetConditions()
+	scopes := conditions.GetScopes()
+	return slices.Clone(scopes.Include)
+}
+
+// applicationClientId returns the OAuth client ID of the application.
+func applicationClientId(app *okta.OpenIdConnectApplication) string {
+	creds := app.GetCredentials()
+	oauthClient := creds.GetOauthClient()
+	return oauthClient.GetClientId()
+}
+
+// isOwned reports whether the profile of the application marks it as created by IdP Connect.
+func isOwned(app *okta.OpenIdConnectApplication) bool {
+	managedBy, _ := app.GetProfile()[connector.ManagedByKey].(string)
+	return managedBy == connector.ManagedBy
+}
+
+func unwrapSDKError(apiResp *okta.APIResponse, err error) portalv1.Error {
+	var resp *http.Response
+	var rateLimited *RateLimitedError
+	switch {
+	case apiResp != nil && apiResp.Response != nil:
+		resp = apiResp.Response
+	case errors.As(err, &rateLimited):
+		// The SDK drops the response of a call that is still rate limited once it has been retried.
+		resp = rateLimited.Response
+	}
+
 	if err != nil {
 		errorMsg := err.Error()
 
@@ -228,8 +916,8 @@ func unwrapSDKError(resp *http.Response, err error) portalv1.Error {
 				Reason:  errorMsg,
 			}
 		}
-		return newPortal500Error(err.Error())
+		return connector.NewPortal500Error(err.Error())
 	}
 
-	return newPortal500Error("unknown error occurred")
+	return connector.NewPortal500Error("unknown error occurred")
 }
@@ -2,6 +2,8 @@ package server_test
 
 import (
 	"context"
+	"errors"
+	"net/http"
 
 	"github.com/okta/okta-sdk-golang/v6/okta"
 	. "github.com/onsi/ginkgo/v2"
@@ -19,6 +21,7 @@ var _ = Describe("Server", func() {
 		applicationClientId     = "test-client-id"
 		applicationClientSecret = "test-client-secret"
 		applicationId           = "0oa1234567890abcdef"
+		authorizationServer     = "default"
 	)
 
 	var (
@@ -26,6 +29,10 @@ var _ = Describe("Server", func() {
 		mockCtrl       *gomock.Controller
 		mockOktaClient *mock_server.MockOktaClient
 		mockAppAPI     *mock_server.MockApplicationAPI
+		mockSecretsAPI *mock_server.MockApplicationSecretsAPI
+		mockScopesAPI  *mock_server.MockAuthorizationServerScopesAPI
+		mockPolicyAPI  *mock_server.MockAuthorizationServerPoliciesAPI
+		mockRulesAPI   *mock_server.MockAuthorizationServerRulesAPI
 		ctx            context.Context
 		testToken      = "test"
 	)
@@ -34,17 +41,44 @@ var _ = Describe("Server", func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockOktaClient = mock_server.NewMockOktaClient(mockCtrl)
 		mockAppAPI = mock_server.NewMockApplicationAPI(mockCtrl)
+		mockSecretsAPI = mock_server.NewMockApplicationSecretsAPI(mockCtrl)
+		mockScopesAPI = mock_server.NewMockAuthorizationServerScopesAPI(mockCtrl)
+		mockPolicyAPI = mock_server.NewMockAuthorizationServerPoliciesAPI(mockCtrl)
+		mockRulesAPI = mock_server.NewMockAuthorizationServerRulesAPI(mockCtrl)
 		ctx = context.Background()
 
 		mockOktaClient.EXPECT().GetApplicationAPI().Return(mockAppAPI).AnyTimes()
-
-		s = server.NewStrictServerHandler(mockOktaClient)
+		mockOktaClient.EXPECT().GetApplicationSecretsAPI().Return(mockSecretsAPI).AnyTimes()
+		mockOktaClient.EXPECT().GetAuthorizationServerScopesAPI().Return(mockScopesAPI).AnyTimes()
+		mockOktaClient.EXPECT().GetAuthorizationServerPoliciesAPI().Return(mockPolicyAPI).AnyTimes()
+		mockOktaClient.EXPECT().GetAuthorizationServerRulesAPI().Return(mockRulesAPI).AnyTimes()
+
+		s = server.NewStrictServerHandler(&server.Options{
+			AuthorizationServer: authorizationServer,
+		}, mockOktaClient)
 	})
 
 	AfterEach(func() {
 		mockCtrl.Finish()
 	})
 
+	// expectSearchApplications expects a search for the OIDC applications labelled with the query, which finds the
+	// given applications on a single page.
+	expectSearchApplications := func(q string, apps ...*okta.OpenIdConnectApplication) {
+		var appUnions []okta.ListApplications200ResponseInner
+		for _, app := range apps {
+			appUnions = append(appUnions, okta.OpenIdConnectApplicationAsListApplications200ResponseInner(app))
+		}
+
+		mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+		mockListReq.EXPECT().Q(q).Return(mockListReq)
+		mockListReq.EXPECT().Filter(`name eq "oidc_client"`).Return(mockListReq)
+		mockListReq.EXPECT().Limit(int32(200)).Return(mockListReq)
+		mockListReq.EXPECT().Execute().Return(appUnions, &okta.APIResponse{}, nil)
+
+		mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+	}
+
 	Context("Application", func() {
 
 		When("no client exists", func() {
@@ -68,8 +102,17 @@ var _ = Describe("Server", func() {
 
 				appUnion := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(app)
 
+				expectSearchApplications(applicationClientId)
+
 				mockCreateReq := mock_server.NewMockApiCreateApplicationRequest(mockCtrl)
-				mockCreateReq.EXPECT().Application(gomock.Any()).Return(mockCreateReq)
+				mockCreateReq.EXPECT().Application(gomock.Any()).DoAndReturn(
+					func(app okta.ListApplications200ResponseInner) *mock_server.MockApiCreateApplicationRequest {
+						profile := app.OpenIdConnectApplication.GetProfile()
+						Expect(profile).To(HaveKeyWithValue("managed_by", "gloo-portal-idp-connect"))
+						Expect(profile).To(HaveKeyWithValue("portal_id", applicationClientId))
+						Expect(profile).To(HaveKey("created_at"))
+						return mockCreateReq
+					})
 				mockCreateReq.EXPECT().Execute().Return(&appUnion, &okta.APIResponse{}, nil)
 
 				mockAppAPI.EXPECT().CreateApplication(ctx).Return(mockCreateReq)
@@ -110,10 +153,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("returns not found code on deletion", func() {
-				mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
-				mockListReq.EXPECT().Execute().Return([]okta.ListApplications200ResponseInner{}, &okta.APIResponse{}, nil)
-
-				mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+				expectSearchApplications("non-existing-client")
 
 				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
 					Id: "non-existing-client",
@@ -126,6 +166,27 @@ var _ = Describe("Server", func() {
 				resp404 := resp.(portalv1.DeleteOAuthApplication404JSONResponse)
 				Expect(resp404.Code).To(Equal(404))
 			})
+
+			It("returns too many requests code when Okta is still rate limiting calls", func() {
+				// The SDK returns no response once it gives up on a rate limited call, so the error keeps it.
+				mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+				mockListReq.EXPECT().Q("rate-limited-client").Return(mockListReq)
+				mockListReq.EXPECT().Filter(gomock.Any()).Return(mockListReq)
+				mockListReq.EXPECT().Limit(gomock.Any()).Return(mockListReq)
+				mockListReq.EXPECT().Execute().Return(nil, nil, &server.RateLimitedError{Response: &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Status:     "429 Too Many Requests",
+					Body:       http.NoBody,
+				}})
+				mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+
+				resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+					Id: "rate-limited-client",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication500JSONResponse{}))
+				Expect(resp.(portalv1.GetOAuthApplication500JSONResponse).Code).To(Equal(429))
+			})
 		})
 
 		When("client exists", func() {
@@ -146,13 +207,61 @@ var _ = Describe("Server", func() {
 					"OPENID_CONNECT",
 				)
 				dummyApp.SetId(applicationId)
+				dummyApp.SetProfile(map[string]interface{}{"managed_by": "gloo-portal-idp-connect", "portal_id": applicationClientId})
 			})
 
-			It("can delete the client", func() {
-				appUnion := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(dummyApp)
+			// expectDeleteApplication expects the application to be deactivated and then deleted.
+			expectDeleteApplication := func() {
+				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
+				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
 
-				mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
-				mockListReq.EXPECT().Execute().Return([]okta.ListApplications200ResponseInner{appUnion}, &okta.APIResponse{}, nil)
+				mockDeleteReq := mock_server.NewMockApiDeleteApplicationRequest(mockCtrl)
+				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+
+				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
+				mockAppAPI.EXPECT().DeleteApplication(ctx, applicationId).Return(mockDeleteReq)
+			}
+
+			It("refuses to delete an application that it did not create", func() {
+				dummyApp.SetProfile(map[string]interface{}{})
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication403JSONResponse{}))
+			})
+
+			It("deletes an application that it did not create when forced to", func() {
+				opts := &server.Options{AuthorizationServer: authorizationServer}
+				opts.ForceDelete = true
+				s = server.NewStrictServerHandler(opts, mockOktaClient)
+
+				dummyApp.SetProfile(map[string]interface{}{})
+				expectSearchApplications(applicationClientId, dummyApp)
+				expectDeleteApplication()
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+
+			It("refuses to rotate the secret of an application that it did not create", func() {
+				dummyApp.SetProfile(map[string]interface{}{})
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret403JSONResponse{}))
+			})
+
+			It("can delete the client", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
 
 				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
 				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
@@ -160,7 +269,6 @@ var _ = Describe("Server", func() {
 				mockDeleteReq := mock_server.NewMockApiDeleteApplicationRequest(mockCtrl)
 				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
 
-				mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
 				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
 				mockAppAPI.EXPECT().DeleteApplication(ctx, applicationId).Return(mockDeleteReq)
 
@@ -173,6 +281,671 @@ var _ = Describe("Server", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
 			})
+
+			It("returns the error of Okta if the application cannot be deactivated", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
+				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{Response: &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Status:     "503 Service Unavailable",
+					Body:       http.NoBody,
+				}}, errors.New("503 Service Unavailable"))
+				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication500JSONResponse{}))
+				Expect(resp.(portalv1.DeleteOAuthApplication500JSONResponse).Code).To(Equal(http.StatusServiceUnavailable))
+			})
+
+			It("returns conflict code when creating a client with the same label", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				resp, err := s.CreateOAuthApplication(ctx, portalv1.CreateOAuthApplicationRequestObject{
+					Body: &portalv1.CreateOAuthApplicationJSONRequestBody{
+						Id: applicationClientId,
+					},
+					Params: portalv1.CreateOAuthApplicationParams{
+						Token: &testToken,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateOAuthApplication409JSONResponse{}))
+			})
+
+			It("can rotate the client secret", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				oldSecret := okta.NewOAuth2ClientSecret()
+				oldSecret.SetId("ocs1")
+				oldSecret.SetStatus("ACTIVE")
+
+				mockListSecretsReq := mock_server.NewMockApiListOAuth2ClientSecretsRequest(mockCtrl)
+				mockListSecretsReq.EXPECT().Execute().Return([]okta.OAuth2ClientSecret{*oldSecret}, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().ListOAuth2ClientSecrets(ctx, applicationId).Return(mockListSecretsReq)
+
+				newSecret := okta.NewOAuth2ClientSecret()
+				newSecret.SetId("ocs2")
+				newSecret.SetClientSecret("r0t4t3d")
+
+				mockCreateSecretReq := mock_server.NewMockApiCreateOAuth2ClientSecretRequest(mockCtrl)
+				mockCreateSecretReq.EXPECT().Execute().Return(newSecret, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().CreateOAuth2ClientSecret(ctx, applicationId).Return(mockCreateSecretReq)
+
+				mockDeactivateSecretReq := mock_server.NewMockApiDeactivateOAuth2ClientSecretRequest(mockCtrl)
+				mockDeactivateSecretReq.EXPECT().Execute().Return(oldSecret, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().DeactivateOAuth2ClientSecret(ctx, applicationId, "ocs1").Return(mockDeactivateSecretReq)
+
+				mockDeleteSecretReq := mock_server.NewMockApiDeleteOAuth2ClientSecretRequest(mockCtrl)
+				mockDeleteSecretReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().DeleteOAuth2ClientSecret(ctx, applicationId, "ocs1").Return(mockDeleteSecretReq)
+
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+				rotated := resp.(portalv1.RotateOAuthApplicationSecret200JSONResponse)
+				Expect(rotated.ClientId).To(Equal(applicationClientId))
+				Expect(rotated.ClientSecret).To(Equal("r0t4t3d"))
+			})
+
+			It("deletes inactive secrets before adding the new secret", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				inactiveSecret := okta.NewOAuth2ClientSecret()
+				inactiveSecret.SetId("ocs0")
+				inactiveSecret.SetStatus("INACTIVE")
+
+				mockListSecretsReq := mock_server.NewMockApiListOAuth2ClientSecretsRequest(mockCtrl)
+				mockListSecretsReq.EXPECT().Execute().Return([]okta.OAuth2ClientSecret{*inactiveSecret}, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().ListOAuth2ClientSecrets(ctx, applicationId).Return(mockListSecretsReq)
+
+				newSecret := okta.NewOAuth2ClientSecret()
+				newSecret.SetId("ocs2")
+				newSecret.SetClientSecret("r0t4t3d")
+
+				mockDeleteSecretReq := mock_server.NewMockApiDeleteOAuth2ClientSecretRequest(mockCtrl)
+				mockDeleteSecretReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockCreateSecretReq := mock_server.NewMockApiCreateOAuth2ClientSecretRequest(mockCtrl)
+				mockCreateSecretReq.EXPECT().Execute().Return(newSecret, &okta.APIResponse{}, nil)
+				gomock.InOrder(
+					mockSecretsAPI.EXPECT().DeleteOAuth2ClientSecret(ctx, applicationId, "ocs0").Return(mockDeleteSecretReq),
+					mockSecretsAPI.EXPECT().CreateOAuth2ClientSecret(ctx, applicationId).Return(mockCreateSecretReq),
+				)
+
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret200JSONResponse{}))
+			})
+
+			It("deletes the new secret if the previous secret cannot be deleted", func() {
+				expectSearchApplications(applicationClientId, dummyApp)
+
+				oldSecret := okta.NewOAuth2ClientSecret()
+				oldSecret.SetId("ocs1")
+				oldSecret.SetStatus("ACTIVE")
+
+				mockListSecretsReq := mock_server.NewMockApiListOAuth2ClientSecretsRequest(mockCtrl)
+				mockListSecretsReq.EXPECT().Execute().Return([]okta.OAuth2ClientSecret{*oldSecret}, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().ListOAuth2ClientSecrets(ctx, applicationId).Return(mockListSecretsReq)
+
+				newSecret := okta.NewOAuth2ClientSecret()
+				newSecret.SetId("ocs2")
+				newSecret.SetClientSecret("r0t4t3d")
+
+				mockCreateSecretReq := mock_server.NewMockApiCreateOAuth2ClientSecretRequest(mockCtrl)
+				mockCreateSecretReq.EXPECT().Execute().Return(newSecret, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().CreateOAuth2ClientSecret(ctx, applicationId).Return(mockCreateSecretReq)
+
+				mockDeactivateOldReq := mock_server.NewMockApiDeactivateOAuth2ClientSecretRequest(mockCtrl)
+				mockDeactivateOldReq.EXPECT().Execute().Return(nil, &okta.APIResponse{}, errors.New("deactivation failed"))
+				mockSecretsAPI.EXPECT().DeactivateOAuth2ClientSecret(ctx, applicationId, "ocs1").Return(mockDeactivateOldReq)
+
+				mockDeactivateNewReq := mock_server.NewMockApiDeactivateOAuth2ClientSecretRequest(mockCtrl)
+				mockDeactivateNewReq.EXPECT().Execute().Return(newSecret, &okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().DeactivateOAuth2ClientSecret(ctx, applicationId, "ocs2").Return(mockDeactivateNewReq)
+
+				mockDeleteNewReq := mock_server.NewMockApiDeleteOAuth2ClientSecretRequest(mockCtrl)
+				mockDeleteNewReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockSecretsAPI.EXPECT().DeleteOAuth2ClientSecret(ctx, applicationId, "ocs2").Return(mockDeleteNewReq)
+
+				resp, err := s.RotateOAuthApplicationSecret(ctx, portalv1.RotateOAuthApplicationSecretRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.RotateOAuthApplicationSecret500JSONResponse{}))
+			})
+
+			It("fetches the client directly by client ID", func() {
+				appUnion := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(dummyApp)
+
+				mockGetReq := mock_server.NewMockApiGetApplicationRequest(mockCtrl)
+				mockGetReq.EXPECT().Execute().Return(&appUnion, &okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().GetApplication(ctx, applicationId).Return(mockGetReq)
+
+				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
+				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
+
+				mockDeleteReq := mock_server.NewMockApiDeleteApplicationRequest(mockCtrl)
+				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeleteApplication(ctx, applicationId).Return(mockDeleteReq)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+
+			It("searches by label when no client has the ID", func() {
+				dummyApp.SetLabel(applicationId)
+
+				mockGetReq := mock_server.NewMockApiGetApplicationRequest(mockCtrl)
+				mockGetReq.EXPECT().Execute().Return(nil, &okta.APIResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}, errors.New("404 Not Found"))
+				mockAppAPI.EXPECT().GetApplication(ctx, applicationId).Return(mockGetReq)
+
+				expectSearchApplications(applicationId, dummyApp)
+
+				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
+				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
+
+				mockDeleteReq := mock_server.NewMockApiDeleteApplicationRequest(mockCtrl)
+				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeleteApplication(ctx, applicationId).Return(mockDeleteReq)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+
+			It("follows the search to later pages", func() {
+				// Labels that only start with the ID are matched by q, and must be skipped.
+				other := okta.NewOpenIdConnectApplication(
+					*okta.NewOAuthApplicationCredentials(),
+					"oidc_client",
+					*okta.NewOpenIdConnectApplicationSettings(),
+					applicationClientId+"-v2",
+					"OPENID_CONNECT",
+				)
+				other.SetId("0oa2")
+
+				header := http.Header{}
+				header.Add("Link", `<https://example.okta.com/api/v1/apps?after=0oa2&limit=200>; rel="next"`)
+
+				firstPage := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+				firstPage.EXPECT().Q(applicationClientId).Return(firstPage)
+				firstPage.EXPECT().Filter(gomock.Any()).Return(firstPage)
+				firstPage.EXPECT().Limit(int32(200)).Return(firstPage)
+				firstPage.EXPECT().Execute().Return(
+					[]okta.ListApplications200ResponseInner{okta.OpenIdConnectApplicationAsListApplications200ResponseInner(other)},
+					&okta.APIResponse{Response: &http.Response{Header: header}},
+					nil,
+				)
+
+				secondPage := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+				secondPage.EXPECT().Q(applicationClientId).Return(secondPage)
+				secondPage.EXPECT().Filter(gomock.Any()).Return(secondPage)
+				secondPage.EXPECT().Limit(int32(200)).Return(secondPage)
+				secondPage.EXPECT().After("0oa2").Return(secondPage)
+				secondPage.EXPECT().Execute().Return(
+					[]okta.ListApplications200ResponseInner{okta.OpenIdConnectApplicationAsListApplications200ResponseInner(dummyApp)},
+					&okta.APIResponse{},
+					nil,
+				)
+
+				gomock.InOrder(
+					mockAppAPI.EXPECT().ListApplications(ctx).Return(firstPage),
+					mockAppAPI.EXPECT().ListApplications(ctx).Return(secondPage),
+				)
+
+				mockDeactivateReq := mock_server.NewMockApiDeactivateApplicationRequest(mockCtrl)
+				mockDeactivateReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeactivateApplication(ctx, applicationId).Return(mockDeactivateReq)
+
+				mockDeleteReq := mock_server.NewMockApiDeleteApplicationRequest(mockCtrl)
+				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+				mockAppAPI.EXPECT().DeleteApplication(ctx, applicationId).Return(mockDeleteReq)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationClientId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication204Response{}))
+			})
+
+			It("returns error code when the client cannot be fetched", func() {
+				mockGetReq := mock_server.NewMockApiGetApplicationRequest(mockCtrl)
+				mockGetReq.EXPECT().Execute().Return(nil, &okta.APIResponse{Response: &http.Response{StatusCode: http.StatusForbidden, Status: "403 Forbidden"}}, errors.New("403 Forbidden"))
+				mockAppAPI.EXPECT().GetApplication(ctx, applicationId).Return(mockGetReq)
+
+				resp, err := s.DeleteOAuthApplication(ctx, portalv1.DeleteOAuthApplicationRequestObject{
+					Id: applicationId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteOAuthApplication500JSONResponse{}))
+			})
+		})
+	})
+
+	Context("Application details", func() {
+		const policyId = "00p1234567890abcdef"
+
+		var dummyApp *okta.OpenIdConnectApplication
+
+		BeforeEach(func() {
+			credentials := okta.NewOAuthApplicationCredentials()
+			oauthClient := okta.NewApplicationCredentialsOAuthClient()
+			oauthClient.SetClientId(applicationClientId)
+			oauthClient.SetClientSecret(applicationClientSecret)
+			credentials.SetOauthClient(*oauthClient)
+
+			dummyApp = okta.NewOpenIdConnectApplication(
+				*credentials,
+				"oidc_client",
+				*okta.NewOpenIdConnectApplicationSettings(),
+				applicationClientId,
+				"OPENID_CONNECT",
+			)
+			dummyApp.SetId(applicationId)
+
+			policy := okta.NewAuthorizationServerPolicy()
+			policy.SetId(policyId)
+			policy.SetName("Gloo Portal " + applicationClientId)
+
+			mockListPoliciesReq := mock_server.NewMockApiListAuthorizationServerPoliciesRequest(mockCtrl)
+			mockListPoliciesReq.EXPECT().Execute().Return([]okta.AuthorizationServerPolicy{*policy}, &okta.APIResponse{}, nil)
+			mockPolicyAPI.EXPECT().ListAuthorizationServerPolicies(ctx, authorizationServer).Return(mockListPoliciesReq)
+
+			conditions := okta.NewAuthorizationServerPolicyRuleConditions()
+			conditions.SetScopes(okta.OAuth2ScopesMediationPolicyRuleCondition{Include: []string{"tracks-rest-api"}})
+			rule := okta.NewAuthorizationServerPolicyRule()
+			rule.SetName("Gloo Portal API products")
+			rule.SetConditions(*conditions)
+
+			mockListRulesReq := mock_server.NewMockApiListAuthorizationServerPolicyRulesRequest(mockCtrl)
+			mockListRulesReq.EXPECT().Execute().Return([]okta.AuthorizationServerPolicyRule{*rule}, &okta.APIResponse{}, nil)
+			mockRulesAPI.EXPECT().ListAuthorizationServerPolicyRules(ctx, authorizationServer, policyId).Return(mockListRulesReq)
+		})
+
+		It("lists the applications that it created with their scopes", func() {
+			dummyApp.SetProfile(map[string]interface{}{"managed_by": "gloo-portal-idp-connect", "portal_id": applicationClientId})
+			appUnion := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(dummyApp)
+			otherApp := okta.OpenIdConnectApplicationAsListApplications200ResponseInner(okta.NewOpenIdConnectApplication(
+				*okta.NewOAuthApplicationCredentials(),
+				"oidc_client",
+				*okta.NewOpenIdConnectApplicationSettings(),
+				"other-app",
+				"OPENID_CONNECT",
+			))
+
+			header := http.Header{}
+			header.Add("Link", `<https://example.okta.com/api/v1/apps?limit=10>; rel="self"`)
+			header.Add("Link", `<https://example.okta.com/api/v1/apps?after=0oa2&limit=10>; rel="next"`)
+
+			mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+			mockListReq.EXPECT().Filter(gomock.Any()).Return(mockListReq)
+			mockListReq.EXPECT().Limit(int32(10)).Return(mockListReq)
+			mockListReq.EXPECT().After("0oa1").Return(mockListReq)
+			mockListReq.EXPECT().Execute().Return(
+				[]okta.ListApplications200ResponseInner{otherApp, appUnion},
+				&okta.APIResponse{Response: &http.Response{Header: header}},
+				nil,
+			)
+			mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+
+			limit, cursor := 10, "0oa1"
+			resp, err := s.ListOAuthApplications(ctx, portalv1.ListOAuthApplicationsRequestObject{
+				Params: portalv1.ListOAuthApplicationsParams{
+					Limit:  &limit,
+					Cursor: &cursor,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.ListOAuthApplications200JSONResponse{}))
+			page := resp.(portalv1.ListOAuthApplications200JSONResponse)
+			Expect(page.Applications).To(HaveLen(1))
+			Expect(page.Applications[0].ClientId).To(Equal(applicationClientId))
+			Expect(page.Applications[0].Scopes).To(ConsistOf("tracks-rest-api"))
+			Expect(*page.NextCursor).To(Equal("0oa2"))
+		})
+
+		It("gets the application without its secret", func() {
+			expectSearchApplications(applicationClientId, dummyApp)
+
+			resp, err := s.GetOAuthApplication(ctx, portalv1.GetOAuthApplicationRequestObject{
+				Id: applicationClientId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GetOAuthApplication200JSONResponse{}))
+			Expect(resp.(portalv1.GetOAuthApplication200JSONResponse).Scopes).To(ConsistOf("tracks-rest-api"))
+		})
+	})
+
+	Context("API Product access", func() {
+		const (
+			apiProductId = "tracks-rest-api"
+			policyId     = "00p1234567890abcdef"
+			ruleId       = "0pr1234567890abcdef"
+		)
+
+		var dummyApp *okta.OpenIdConnectApplication
+
+		BeforeEach(func() {
+			credentials := okta.NewOAuthApplicationCredentials()
+			oauthClient := okta.NewApplicationCredentialsOAuthClient()
+			oauthClient.SetClientId(applicationClientId)
+			credentials.SetOauthClient(*oauthClient)
+
+			dummyApp = okta.NewOpenIdConnectApplication(
+				*credentials,
+				"oidc_client",
+				*okta.NewOpenIdConnectApplicationSettings(),
+				applicationClientId,
+				"OPENID_CONNECT",
+			)
+			dummyApp.SetId(applicationId)
+			dummyApp.SetProfile(map[string]interface{}{"managed_by": "gloo-portal-idp-connect", "portal_id": applicationClientId})
+		})
+
+		expectListScopes := func(name string) {
+			mockListReq := mock_server.NewMockApiListOAuth2ScopesRequest(mockCtrl)
+			mockListReq.EXPECT().Q(name).Return(mockListReq)
+			mockListReq.EXPECT().Execute().Return([]okta.OAuth2Scope{*okta.NewOAuth2Scope(name)}, &okta.APIResponse{}, nil)
+
+			mockScopesAPI.EXPECT().ListOAuth2Scopes(ctx, authorizationServer).Return(mockListReq)
+		}
+
+		expectListPolicies := func(policies ...okta.AuthorizationServerPolicy) {
+			mockListReq := mock_server.NewMockApiListAuthorizationServerPoliciesRequest(mockCtrl)
+			mockListReq.EXPECT().Execute().Return(policies, &okta.APIResponse{}, nil)
+
+			mockPolicyAPI.EXPECT().ListAuthorizationServerPolicies(ctx, authorizationServer).Return(mockListReq)
+		}
+
+		expectListRules := func(scopes ...string) {
+			conditions := okta.NewAuthorizationServerPolicyRuleConditions()
+			conditions.SetScopes(okta.OAuth2ScopesMediationPolicyRuleCondition{Include: scopes})
+			rule := okta.NewAuthorizationServerPolicyRule()
+			rule.SetId(ruleId)
+			rule.SetName("Gloo Portal API products")
+			rule.SetConditions(*conditions)
+
+			mockListReq := mock_server.NewMockApiListAuthorizationServerPolicyRulesRequest(mockCtrl)
+			mockListReq.EXPECT().Execute().Return([]okta.AuthorizationServerPolicyRule{*rule}, &okta.APIResponse{}, nil)
+
+			mockRulesAPI.EXPECT().ListAuthorizationServerPolicyRules(ctx, authorizationServer, policyId).Return(mockListReq)
+		}
+
+		existingPolicy := func() okta.AuthorizationServerPolicy {
+			policy := okta.NewAuthorizationServerPolicy()
+			policy.SetId(policyId)
+			policy.SetName("Gloo Portal " + applicationClientId)
+			return *policy
+		}
+
+		ruleScopes := func(rule okta.AuthorizationServerPolicyRuleRequest) []string {
+			conditions := rule.GetConditions()
+			scopes := conditions.GetScopes()
+			return scopes.Include
+		}
+
+		It("refuses to change the API products of an application that it did not create", func() {
+			dummyApp.SetProfile(map[string]interface{}{})
+			expectSearchApplications(applicationClientId, dummyApp)
+			expectSearchApplications(applicationClientId, dummyApp)
+
+			grant, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grant).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess403JSONResponse{}))
+
+			revoke, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoke).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess403JSONResponse{}))
+		})
+
+		It("creates an access policy and rule for the client", func() {
+			expectSearchApplications(applicationClientId, dummyApp)
+			expectListScopes(apiProductId)
+			expectListPolicies()
+
+			created := okta.NewAuthorizationServerPolicy()
+			created.SetId(policyId)
+
+			mockCreatePolicyReq := mock_server.NewMockApiCreateAuthorizationServerPolicyRequest(mockCtrl)
+			mockCreatePolicyReq.EXPECT().Policy(gomock.Any()).DoAndReturn(func(policy okta.AuthorizationServerPolicy) server.ApiCreateAuthorizationServerPolicyRequest {
+				conditions := policy.GetConditions()
+				clients := conditions.GetClients()
+				Expect(clients.Include).To(ConsistOf(applicationClientId))
+				Expect(policy.HasPriority()).To(BeFalse())
+				return mockCreatePolicyReq
+			})
+			mockCreatePolicyReq.EXPECT().Execute().Return(created, &okta.APIResponse{}, nil)
+			mockPolicyAPI.EXPECT().CreateAuthorizationServerPolicy(ctx, authorizationServer).Return(mockCreatePolicyReq)
+
+			mockCreateRuleReq := mock_server.NewMockApiCreateAuthorizationServerPolicyRuleRequest(mockCtrl)
+			mockCreateRuleReq.EXPECT().PolicyRule(gomock.Any()).DoAndReturn(func(rule okta.AuthorizationServerPolicyRuleRequest) server.ApiCreateAuthorizationServerPolicyRuleRequest {
+				Expect(ruleScopes(rule)).To(ConsistOf(apiProductId))
+				return mockCreateRuleReq
+			})
+			mockCreateRuleReq.EXPECT().Execute().Return(okta.NewAuthorizationServerPolicyRule(), &okta.APIResponse{}, nil)
+			mockRulesAPI.EXPECT().CreateAuthorizationServerPolicyRule(ctx, authorizationServer, policyId).Return(mockCreateRuleReq)
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+		})
+
+		It("adds the API product to an existing rule", func() {
+			expectSearchApplications(applicationClientId, dummyApp)
+			expectListScopes(apiProductId)
+			expectListPolicies(existingPolicy())
+			expectListRules("other-api")
+
+			mockReplaceReq := mock_server.NewMockApiReplaceAuthorizationServerPolicyRuleRequest(mockCtrl)
+			mockReplaceReq.EXPECT().PolicyRule(gomock.Any()).DoAndReturn(func(rule okta.AuthorizationServerPolicyRuleRequest) server.ApiReplaceAuthorizationServerPolicyRuleRequest {
+				Expect(ruleScopes(rule)).To(ConsistOf("other-api", apiProductId))
+				return mockReplaceReq
+			})
+			mockReplaceReq.EXPECT().Execute().Return(okta.NewAuthorizationServerPolicyRule(), &okta.APIResponse{}, nil)
+			mockRulesAPI.EXPECT().ReplaceAuthorizationServerPolicyRule(ctx, authorizationServer, policyId, ruleId).Return(mockReplaceReq)
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess204Response{}))
+		})
+
+		It("returns not found code when the client does not exist", func() {
+			expectSearchApplications("non-existing-client")
+
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id: "non-existing-client",
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess404JSONResponse{}))
+		})
+
+		It("returns error code on empty API products", func() {
+			resp, err := s.GrantAPIProductAccess(ctx, portalv1.GrantAPIProductAccessRequestObject{
+				Id:   applicationClientId,
+				Body: &portalv1.GrantAPIProductAccessJSONRequestBody{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.GrantAPIProductAccess400JSONResponse{}))
+		})
+
+		It("deletes the access policy when the last API product is revoked", func() {
+			expectSearchApplications(applicationClientId, dummyApp)
+			expectListPolicies(existingPolicy())
+			expectListRules(apiProductId)
+
+			mockDeleteReq := mock_server.NewMockApiDeleteAuthorizationServerPolicyRequest(mockCtrl)
+			mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+			mockPolicyAPI.EXPECT().DeleteAuthorizationServerPolicy(ctx, authorizationServer, policyId).Return(mockDeleteReq)
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+		})
+
+		It("removes the API product from the rule when others remain", func() {
+			expectSearchApplications(applicationClientId, dummyApp)
+			expectListPolicies(existingPolicy())
+			expectListRules("other-api", apiProductId)
+
+			mockReplaceReq := mock_server.NewMockApiReplaceAuthorizationServerPolicyRuleRequest(mockCtrl)
+			mockReplaceReq.EXPECT().PolicyRule(gomock.Any()).DoAndReturn(func(rule okta.AuthorizationServerPolicyRuleRequest) server.ApiReplaceAuthorizationServerPolicyRuleRequest {
+				Expect(ruleScopes(rule)).To(ConsistOf("other-api"))
+				return mockReplaceReq
+			})
+			mockReplaceReq.EXPECT().Execute().Return(okta.NewAuthorizationServerPolicyRule(), &okta.APIResponse{}, nil)
+			mockRulesAPI.EXPECT().ReplaceAuthorizationServerPolicyRule(ctx, authorizationServer, policyId, ruleId).Return(mockReplaceReq)
+
+			resp, err := s.RevokeAPIProductAccess(ctx, portalv1.RevokeAPIProductAccessRequestObject{
+				Id: applicationClientId,
+				Params: portalv1.RevokeAPIProductAccessParams{
+					ApiProducts: []string{apiProductId},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(BeAssignableToTypeOf(portalv1.RevokeAPIProductAccess204Response{}))
+		})
+	})
+
+	Context("API Product", func() {
+		const (
+			apiProductId = "tracks-rest-api"
+			scopeId      = "scpCmCCV1DpxVkCaye2X"
+		)
+
+		expectListScopes := func(scopes ...okta.OAuth2Scope) {
+			mockListReq := mock_server.NewMockApiListOAuth2ScopesRequest(mockCtrl)
+			mockListReq.EXPECT().Q(apiProductId).Return(mockListReq)
+			mockListReq.EXPECT().Execute().Return(scopes, &okta.APIResponse{}, nil)
+
+			mockScopesAPI.EXPECT().ListOAuth2Scopes(ctx, authorizationServer).Return(mockListReq)
+		}
+
+		When("no scope exists", func() {
+			BeforeEach(func() {
+				// Scopes sharing the API product id as a prefix must not be matched.
+				expectListScopes(*okta.NewOAuth2Scope(apiProductId + "-v2"))
+			})
+
+			It("can create an API product", func() {
+				mockCreateReq := mock_server.NewMockApiCreateOAuth2ScopeRequest(mockCtrl)
+				mockCreateReq.EXPECT().OAuth2Scope(gomock.Any()).DoAndReturn(func(scope okta.OAuth2Scope) server.ApiCreateOAuth2ScopeRequest {
+					Expect(scope.Name).To(Equal(apiProductId))
+					return mockCreateReq
+				})
+				mockCreateReq.EXPECT().Execute().Return(okta.NewOAuth2Scope(apiProductId), &okta.APIResponse{}, nil)
+
+				mockScopesAPI.EXPECT().CreateOAuth2Scope(ctx, authorizationServer).Return(mockCreateReq)
+
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct201JSONResponse{}))
+			})
+
+			It("returns not found code on deletion", func() {
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct404JSONResponse{}))
+			})
+		})
+
+		When("scope exists", func() {
+			BeforeEach(func() {
+				scope := okta.NewOAuth2Scope(apiProductId)
+				scope.SetId(scopeId)
+				expectListScopes(*scope)
+			})
+
+			It("returns conflict code on creation", func() {
+				resp, err := s.CreateAPIProduct(ctx, portalv1.CreateAPIProductRequestObject{
+					Body: &portalv1.CreateAPIProductJSONRequestBody{
+						Id: apiProductId,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.CreateAPIProduct409JSONResponse{}))
+			})
+
+			It("can delete the API product", func() {
+				mockDeleteReq := mock_server.NewMockApiDeleteOAuth2ScopeRequest(mockCtrl)
+				mockDeleteReq.EXPECT().Execute().Return(&okta.APIResponse{}, nil)
+
+				mockScopesAPI.EXPECT().DeleteOAuth2Scope(ctx, authorizationServer, scopeId).Return(mockDeleteReq)
+
+				resp, err := s.DeleteAPIProduct(ctx, portalv1.DeleteAPIProductRequestObject{
+					Id: apiProductId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(BeAssignableToTypeOf(portalv1.DeleteAPIProduct204Response{}))
+			})
+		})
+	})
+
+	Context("Readiness", func() {
+		It("is ready when applications can be listed with the API token", func() {
+			mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+			mockListReq.EXPECT().Limit(int32(1)).Return(mockListReq)
+			mockListReq.EXPECT().Execute().Return([]okta.ListApplications200ResponseInner{}, &okta.APIResponse{}, nil)
+			mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+
+			Expect(s.Ready(ctx)).To(Succeed())
+		})
+
+		It("is not ready when the API token is rejected", func() {
+			mockListReq := mock_server.NewMockApiListApplicationsRequest(mockCtrl)
+			mockListReq.EXPECT().Limit(int32(1)).Return(mockListReq)
+			mockListReq.EXPECT().Execute().Return(nil, &okta.APIResponse{}, errors.New("401 Unauthorized"))
+			mockAppAPI.EXPECT().ListApplications(ctx).Return(mockListReq)
+
+			Expect(s.Ready(ctx)).NotTo(Succeed())
 		})
 	})
 })
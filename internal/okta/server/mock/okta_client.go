@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/solo-io/gloo-portal-idp-connect/internal/okta/server (interfaces: OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest)
+// Source: github.com/solo-io/gloo-portal-idp-connect/internal/okta/server (interfaces: OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiGetApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest,ApplicationSecretsAPI,ApiCreateOAuth2ClientSecretRequest,ApiListOAuth2ClientSecretsRequest,ApiDeactivateOAuth2ClientSecretRequest,ApiDeleteOAuth2ClientSecretRequest,AuthorizationServerScopesAPI,ApiCreateOAuth2ScopeRequest,ApiListOAuth2ScopesRequest,ApiDeleteOAuth2ScopeRequest,AuthorizationServerPoliciesAPI,ApiCreateAuthorizationServerPolicyRequest,ApiListAuthorizationServerPoliciesRequest,ApiDeleteAuthorizationServerPolicyRequest,AuthorizationServerRulesAPI,ApiCreateAuthorizationServerPolicyRuleRequest,ApiListAuthorizationServerPolicyRulesRequest,ApiReplaceAuthorizationServerPolicyRuleRequest)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mock/okta_client.go . OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest
+//	mockgen -destination=mock/okta_client.go . OktaClient,ApplicationAPI,ApiCreateApplicationRequest,ApiGetApplicationRequest,ApiListApplicationsRequest,ApiDeactivateApplicationRequest,ApiDeleteApplicationRequest,ApplicationSecretsAPI,ApiCreateOAuth2ClientSecretRequest,ApiListOAuth2ClientSecretsRequest,ApiDeactivateOAuth2ClientSecretRequest,ApiDeleteOAuth2ClientSecretRequest,AuthorizationServerScopesAPI,ApiCreateOAuth2ScopeRequest,ApiListOAuth2ScopesRequest,ApiDeleteOAuth2ScopeRequest,AuthorizationServerPoliciesAPI,ApiCreateAuthorizationServerPolicyRequest,ApiListAuthorizationServerPoliciesRequest,ApiDeleteAuthorizationServerPolicyRequest,AuthorizationServerRulesAPI,ApiCreateAuthorizationServerPolicyRuleRequest,ApiListAuthorizationServerPolicyRulesRequest,ApiReplaceAuthorizationServerPolicyRuleRequest
 //
 
 // Package mock_server is a generated GoMock package.
@@ -56,6 +56,62 @@ func (mr *MockOktaClientMockRecorder) GetApplicationAPI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationAPI", reflect.TypeOf((*MockOktaClient)(nil).GetApplicationAPI))
 }
 
+// GetApplicationSecretsAPI mocks base method.
+func (m *MockOktaClient) GetApplicationSecretsAPI() server.ApplicationSecretsAPI {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationSecretsAPI")
+	ret0, _ := ret[0].(server.ApplicationSecretsAPI)
+	return ret0
+}
+
+// GetApplicationSecretsAPI indicates an expected call of GetApplicationSecretsAPI.
+func (mr *MockOktaClientMockRecorder) GetApplicationSecretsAPI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationSecretsAPI", reflect.TypeOf((*MockOktaClient)(nil).GetApplicationSecretsAPI))
+}
+
+// GetAuthorizationServerPoliciesAPI mocks base method.
+func (m *MockOktaClient) GetAuthorizationServerPoliciesAPI() server.AuthorizationServerPoliciesAPI {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationServerPoliciesAPI")
+	ret0, _ := ret[0].(server.AuthorizationServerPoliciesAPI)
+	return ret0
+}
+
+// GetAuthorizationServerPoliciesAPI indicates an expected call of GetAuthorizationServerPoliciesAPI.
+func (mr *MockOktaClientMockRecorder) GetAuthorizationServerPoliciesAPI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationServerPoliciesAPI", reflect.TypeOf((*MockOktaClient)(nil).GetAuthorizationServerPoliciesAPI))
+}
+
+// GetAuthorizationServerRulesAPI mocks base method.
+func (m *MockOktaClient) GetAuthorizationServerRulesAPI() server.AuthorizationServerRulesAPI {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationServerRulesAPI")
+	ret0, _ := ret[0].(server.AuthorizationServerRulesAPI)
+	return ret0
+}
+
+// GetAuthorizationServerRulesAPI indicates an expected call of GetAuthorizationServerRulesAPI.
+func (mr *MockOktaClientMockRecorder) GetAuthorizationServerRulesAPI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationServerRulesAPI", reflect.TypeOf((*MockOktaClient)(nil).GetAuthorizationServerRulesAPI))
+}
+
+// GetAuthorizationServerScopesAPI mocks base method.
+func (m *MockOktaClient) GetAuthorizationServerScopesAPI() server.AuthorizationServerScopesAPI {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationServerScopesAPI")
+	ret0, _ := ret[0].(server.AuthorizationServerScopesAPI)
+	return ret0
+}
+
+// GetAuthorizationServerScopesAPI indicates an expected call of GetAuthorizationServerScopesAPI.
+func (mr *MockOktaClientMockRecorder) GetAuthorizationServerScopesAPI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationServerScopesAPI", reflect.TypeOf((*MockOktaClient)(nil).GetAuthorizationServerScopesAPI))
+}
+
 // MockApplicationAPI is a mock of ApplicationAPI interface.
 type MockApplicationAPI struct {
 	ctrl     *gomock.Controller
@@ -122,6 +178,20 @@ func (mr *MockApplicationAPIMockRecorder) DeleteApplication(ctx, appId any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplication", reflect.TypeOf((*MockApplicationAPI)(nil).DeleteApplication), ctx, appId)
 }
 
+// GetApplication mocks base method.
+func (m *MockApplicationAPI) GetApplication(ctx context.Context, appId string) server.ApiGetApplicationRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplication", ctx, appId)
+	ret0, _ := ret[0].(server.ApiGetApplicationRequest)
+	return ret0
+}
+
+// GetApplication indicates an expected call of GetApplication.
+func (mr *MockApplicationAPIMockRecorder) GetApplication(ctx, appId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockApplicationAPI)(nil).GetApplication), ctx, appId)
+}
+
 // ListApplications mocks base method.
 func (m *MockApplicationAPI) ListApplications(ctx context.Context) server.ApiListApplicationsRequest {
 	m.ctrl.T.Helper()
@@ -190,6 +260,46 @@ func (mr *MockApiCreateApplicationRequestMockRecorder) Execute() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiCreateApplicationRequest)(nil).Execute))
 }
 
+// MockApiGetApplicationRequest is a mock of ApiGetApplicationRequest interface.
+type MockApiGetApplicationRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiGetApplicationRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiGetApplicationRequestMockRecorder is the mock recorder for MockApiGetApplicationRequest.
+type MockApiGetApplicationRequestMockRecorder struct {
+	mock *MockApiGetApplicationRequest
+}
+
+// NewMockApiGetApplicationRequest creates a new mock instance.
+func NewMockApiGetApplicationRequest(ctrl *gomock.Controller) *MockApiGetApplicationRequest {
+	mock := &MockApiGetApplicationRequest{ctrl: ctrl}
+	mock.recorder = &MockApiGetApplicationRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiGetApplicationRequest) EXPECT() *MockApiGetApplicationRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiGetApplicationRequest) Execute() (*okta.ListApplications200ResponseInner, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.ListApplications200ResponseInner)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiGetApplicationRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiGetApplicationRequest)(nil).Execute))
+}
+
 // MockApiListApplicationsRequest is a mock of ApiListApplicationsRequest interface.
 type MockApiListApplicationsRequest struct {
 	ctrl     *gomock.Controller
@@ -214,6 +324,20 @@ func (m *MockApiListApplicationsRequest) EXPECT() *MockApiListApplicationsReques
 	return m.recorder
 }
 
+// After mocks base method.
+func (m *MockApiListApplicationsRequest) After(after string) server.ApiListApplicationsRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "After", after)
+	ret0, _ := ret[0].(server.ApiListApplicationsRequest)
+	return ret0
+}
+
+// After indicates an expected call of After.
+func (mr *MockApiListApplicationsRequestMockRecorder) After(after any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "After", reflect.TypeOf((*MockApiListApplicationsRequest)(nil).After), after)
+}
+
 // Execute mocks base method.
 func (m *MockApiListApplicationsRequest) Execute() ([]okta.ListApplications200ResponseInner, *okta.APIResponse, error) {
 	m.ctrl.T.Helper()
@@ -230,6 +354,48 @@ func (mr *MockApiListApplicationsRequestMockRecorder) Execute() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiListApplicationsRequest)(nil).Execute))
 }
 
+// Filter mocks base method.
+func (m *MockApiListApplicationsRequest) Filter(filter string) server.ApiListApplicationsRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Filter", filter)
+	ret0, _ := ret[0].(server.ApiListApplicationsRequest)
+	return ret0
+}
+
+// Filter indicates an expected call of Filter.
+func (mr *MockApiListApplicationsRequestMockRecorder) Filter(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockApiListApplicationsRequest)(nil).Filter), filter)
+}
+
+// Limit mocks base method.
+func (m *MockApiListApplicationsRequest) Limit(limit int32) server.ApiListApplicationsRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit", limit)
+	ret0, _ := ret[0].(server.ApiListApplicationsRequest)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockApiListApplicationsRequestMockRecorder) Limit(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockApiListApplicationsRequest)(nil).Limit), limit)
+}
+
+// Q mocks base method.
+func (m *MockApiListApplicationsRequest) Q(q string) server.ApiListApplicationsRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Q", q)
+	ret0, _ := ret[0].(server.ApiListApplicationsRequest)
+	return ret0
+}
+
+// Q indicates an expected call of Q.
+func (mr *MockApiListApplicationsRequestMockRecorder) Q(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Q", reflect.TypeOf((*MockApiListApplicationsRequest)(nil).Q), q)
+}
+
 // MockApiDeactivateApplicationRequest is a mock of ApiDeactivateApplicationRequest interface.
 type MockApiDeactivateApplicationRequest struct {
 	ctrl     *gomock.Controller
@@ -307,3 +473,868 @@ func (mr *MockApiDeleteApplicationRequestMockRecorder) Execute() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiDeleteApplicationRequest)(nil).Execute))
 }
+
+// MockApplicationSecretsAPI is a mock of ApplicationSecretsAPI interface.
+type MockApplicationSecretsAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationSecretsAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockApplicationSecretsAPIMockRecorder is the mock recorder for MockApplicationSecretsAPI.
+type MockApplicationSecretsAPIMockRecorder struct {
+	mock *MockApplicationSecretsAPI
+}
+
+// NewMockApplicationSecretsAPI creates a new mock instance.
+func NewMockApplicationSecretsAPI(ctrl *gomock.Controller) *MockApplicationSecretsAPI {
+	mock := &MockApplicationSecretsAPI{ctrl: ctrl}
+	mock.recorder = &MockApplicationSecretsAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationSecretsAPI) EXPECT() *MockApplicationSecretsAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuth2ClientSecret mocks base method.
+func (m *MockApplicationSecretsAPI) CreateOAuth2ClientSecret(ctx context.Context, appId string) server.ApiCreateOAuth2ClientSecretRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuth2ClientSecret", ctx, appId)
+	ret0, _ := ret[0].(server.ApiCreateOAuth2ClientSecretRequest)
+	return ret0
+}
+
+// CreateOAuth2ClientSecret indicates an expected call of CreateOAuth2ClientSecret.
+func (mr *MockApplicationSecretsAPIMockRecorder) CreateOAuth2ClientSecret(ctx, appId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuth2ClientSecret", reflect.TypeOf((*MockApplicationSecretsAPI)(nil).CreateOAuth2ClientSecret), ctx, appId)
+}
+
+// DeactivateOAuth2ClientSecret mocks base method.
+func (m *MockApplicationSecretsAPI) DeactivateOAuth2ClientSecret(ctx context.Context, appId, secretId string) server.ApiDeactivateOAuth2ClientSecretRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateOAuth2ClientSecret", ctx, appId, secretId)
+	ret0, _ := ret[0].(server.ApiDeactivateOAuth2ClientSecretRequest)
+	return ret0
+}
+
+// DeactivateOAuth2ClientSecret indicates an expected call of DeactivateOAuth2ClientSecret.
+func (mr *MockApplicationSecretsAPIMockRecorder) DeactivateOAuth2ClientSecret(ctx, appId, secretId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2ClientSecret", reflect.TypeOf((*MockApplicationSecretsAPI)(nil).DeactivateOAuth2ClientSecret), ctx, appId, secretId)
+}
+
+// DeleteOAuth2ClientSecret mocks base method.
+func (m *MockApplicationSecretsAPI) DeleteOAuth2ClientSecret(ctx context.Context, appId, secretId string) server.ApiDeleteOAuth2ClientSecretRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ClientSecret", ctx, appId, secretId)
+	ret0, _ := ret[0].(server.ApiDeleteOAuth2ClientSecretRequest)
+	return ret0
+}
+
+// DeleteOAuth2ClientSecret indicates an expected call of DeleteOAuth2ClientSecret.
+func (mr *MockApplicationSecretsAPIMockRecorder) DeleteOAuth2ClientSecret(ctx, appId, secretId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ClientSecret", reflect.TypeOf((*MockApplicationSecretsAPI)(nil).DeleteOAuth2ClientSecret), ctx, appId, secretId)
+}
+
+// ListOAuth2ClientSecrets mocks base method.
+func (m *MockApplicationSecretsAPI) ListOAuth2ClientSecrets(ctx context.Context, appId string) server.ApiListOAuth2ClientSecretsRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOAuth2ClientSecrets", ctx, appId)
+	ret0, _ := ret[0].(server.ApiListOAuth2ClientSecretsRequest)
+	return ret0
+}
+
+// ListOAuth2ClientSecrets indicates an expected call of ListOAuth2ClientSecrets.
+func (mr *MockApplicationSecretsAPIMockRecorder) ListOAuth2ClientSecrets(ctx, appId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuth2ClientSecrets", reflect.TypeOf((*MockApplicationSecretsAPI)(nil).ListOAuth2ClientSecrets), ctx, appId)
+}
+
+// MockApiCreateOAuth2ClientSecretRequest is a mock of ApiCreateOAuth2ClientSecretRequest interface.
+type MockApiCreateOAuth2ClientSecretRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiCreateOAuth2ClientSecretRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiCreateOAuth2ClientSecretRequestMockRecorder is the mock recorder for MockApiCreateOAuth2ClientSecretRequest.
+type MockApiCreateOAuth2ClientSecretRequestMockRecorder struct {
+	mock *MockApiCreateOAuth2ClientSecretRequest
+}
+
+// NewMockApiCreateOAuth2ClientSecretRequest creates a new mock instance.
+func NewMockApiCreateOAuth2ClientSecretRequest(ctrl *gomock.Controller) *MockApiCreateOAuth2ClientSecretRequest {
+	mock := &MockApiCreateOAuth2ClientSecretRequest{ctrl: ctrl}
+	mock.recorder = &MockApiCreateOAuth2ClientSecretRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiCreateOAuth2ClientSecretRequest) EXPECT() *MockApiCreateOAuth2ClientSecretRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiCreateOAuth2ClientSecretRequest) Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.OAuth2ClientSecret)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiCreateOAuth2ClientSecretRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiCreateOAuth2ClientSecretRequest)(nil).Execute))
+}
+
+// MockApiListOAuth2ClientSecretsRequest is a mock of ApiListOAuth2ClientSecretsRequest interface.
+type MockApiListOAuth2ClientSecretsRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiListOAuth2ClientSecretsRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiListOAuth2ClientSecretsRequestMockRecorder is the mock recorder for MockApiListOAuth2ClientSecretsRequest.
+type MockApiListOAuth2ClientSecretsRequestMockRecorder struct {
+	mock *MockApiListOAuth2ClientSecretsRequest
+}
+
+// NewMockApiListOAuth2ClientSecretsRequest creates a new mock instance.
+func NewMockApiListOAuth2ClientSecretsRequest(ctrl *gomock.Controller) *MockApiListOAuth2ClientSecretsRequest {
+	mock := &MockApiListOAuth2ClientSecretsRequest{ctrl: ctrl}
+	mock.recorder = &MockApiListOAuth2ClientSecretsRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiListOAuth2ClientSecretsRequest) EXPECT() *MockApiListOAuth2ClientSecretsRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiListOAuth2ClientSecretsRequest) Execute() ([]okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].([]okta.OAuth2ClientSecret)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiListOAuth2ClientSecretsRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiListOAuth2ClientSecretsRequest)(nil).Execute))
+}
+
+// MockApiDeactivateOAuth2ClientSecretRequest is a mock of ApiDeactivateOAuth2ClientSecretRequest interface.
+type MockApiDeactivateOAuth2ClientSecretRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiDeactivateOAuth2ClientSecretRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiDeactivateOAuth2ClientSecretRequestMockRecorder is the mock recorder for MockApiDeactivateOAuth2ClientSecretRequest.
+type MockApiDeactivateOAuth2ClientSecretRequestMockRecorder struct {
+	mock *MockApiDeactivateOAuth2ClientSecretRequest
+}
+
+// NewMockApiDeactivateOAuth2ClientSecretRequest creates a new mock instance.
+func NewMockApiDeactivateOAuth2ClientSecretRequest(ctrl *gomock.Controller) *MockApiDeactivateOAuth2ClientSecretRequest {
+	mock := &MockApiDeactivateOAuth2ClientSecretRequest{ctrl: ctrl}
+	mock.recorder = &MockApiDeactivateOAuth2ClientSecretRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiDeactivateOAuth2ClientSecretRequest) EXPECT() *MockApiDeactivateOAuth2ClientSecretRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiDeactivateOAuth2ClientSecretRequest) Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.OAuth2ClientSecret)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiDeactivateOAuth2ClientSecretRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiDeactivateOAuth2ClientSecretRequest)(nil).Execute))
+}
+
+// MockApiDeleteOAuth2ClientSecretRequest is a mock of ApiDeleteOAuth2ClientSecretRequest interface.
+type MockApiDeleteOAuth2ClientSecretRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiDeleteOAuth2ClientSecretRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiDeleteOAuth2ClientSecretRequestMockRecorder is the mock recorder for MockApiDeleteOAuth2ClientSecretRequest.
+type MockApiDeleteOAuth2ClientSecretRequestMockRecorder struct {
+	mock *MockApiDeleteOAuth2ClientSecretRequest
+}
+
+// NewMockApiDeleteOAuth2ClientSecretRequest creates a new mock instance.
+func NewMockApiDeleteOAuth2ClientSecretRequest(ctrl *gomock.Controller) *MockApiDeleteOAuth2ClientSecretRequest {
+	mock := &MockApiDeleteOAuth2ClientSecretRequest{ctrl: ctrl}
+	mock.recorder = &MockApiDeleteOAuth2ClientSecretRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiDeleteOAuth2ClientSecretRequest) EXPECT() *MockApiDeleteOAuth2ClientSecretRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiDeleteOAuth2ClientSecretRequest) Execute() (*okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiDeleteOAuth2ClientSecretRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiDeleteOAuth2ClientSecretRequest)(nil).Execute))
+}
+
+// MockAuthorizationServerScopesAPI is a mock of AuthorizationServerScopesAPI interface.
+type MockAuthorizationServerScopesAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationServerScopesAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthorizationServerScopesAPIMockRecorder is the mock recorder for MockAuthorizationServerScopesAPI.
+type MockAuthorizationServerScopesAPIMockRecorder struct {
+	mock *MockAuthorizationServerScopesAPI
+}
+
+// NewMockAuthorizationServerScopesAPI creates a new mock instance.
+func NewMockAuthorizationServerScopesAPI(ctrl *gomock.Controller) *MockAuthorizationServerScopesAPI {
+	mock := &MockAuthorizationServerScopesAPI{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationServerScopesAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationServerScopesAPI) EXPECT() *MockAuthorizationServerScopesAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuth2Scope mocks base method.
+func (m *MockAuthorizationServerScopesAPI) CreateOAuth2Scope(ctx context.Context, authServerId string) server.ApiCreateOAuth2ScopeRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuth2Scope", ctx, authServerId)
+	ret0, _ := ret[0].(server.ApiCreateOAuth2ScopeRequest)
+	return ret0
+}
+
+// CreateOAuth2Scope indicates an expected call of CreateOAuth2Scope.
+func (mr *MockAuthorizationServerScopesAPIMockRecorder) CreateOAuth2Scope(ctx, authServerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuth2Scope", reflect.TypeOf((*MockAuthorizationServerScopesAPI)(nil).CreateOAuth2Scope), ctx, authServerId)
+}
+
+// DeleteOAuth2Scope mocks base method.
+func (m *MockAuthorizationServerScopesAPI) DeleteOAuth2Scope(ctx context.Context, authServerId, scopeId string) server.ApiDeleteOAuth2ScopeRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2Scope", ctx, authServerId, scopeId)
+	ret0, _ := ret[0].(server.ApiDeleteOAuth2ScopeRequest)
+	return ret0
+}
+
+// DeleteOAuth2Scope indicates an expected call of DeleteOAuth2Scope.
+func (mr *MockAuthorizationServerScopesAPIMockRecorder) DeleteOAuth2Scope(ctx, authServerId, scopeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2Scope", reflect.TypeOf((*MockAuthorizationServerScopesAPI)(nil).DeleteOAuth2Scope), ctx, authServerId, scopeId)
+}
+
+// ListOAuth2Scopes mocks base method.
+func (m *MockAuthorizationServerScopesAPI) ListOAuth2Scopes(ctx context.Context, authServerId string) server.ApiListOAuth2ScopesRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOAuth2Scopes", ctx, authServerId)
+	ret0, _ := ret[0].(server.ApiListOAuth2ScopesRequest)
+	return ret0
+}
+
+// ListOAuth2Scopes indicates an expected call of ListOAuth2Scopes.
+func (mr *MockAuthorizationServerScopesAPIMockRecorder) ListOAuth2Scopes(ctx, authServerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuth2Scopes", reflect.TypeOf((*MockAuthorizationServerScopesAPI)(nil).ListOAuth2Scopes), ctx, authServerId)
+}
+
+// MockApiCreateOAuth2ScopeRequest is a mock of ApiCreateOAuth2ScopeRequest interface.
+type MockApiCreateOAuth2ScopeRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiCreateOAuth2ScopeRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiCreateOAuth2ScopeRequestMockRecorder is the mock recorder for MockApiCreateOAuth2ScopeRequest.
+type MockApiCreateOAuth2ScopeRequestMockRecorder struct {
+	mock *MockApiCreateOAuth2ScopeRequest
+}
+
+// NewMockApiCreateOAuth2ScopeRequest creates a new mock instance.
+func NewMockApiCreateOAuth2ScopeRequest(ctrl *gomock.Controller) *MockApiCreateOAuth2ScopeRequest {
+	mock := &MockApiCreateOAuth2ScopeRequest{ctrl: ctrl}
+	mock.recorder = &MockApiCreateOAuth2ScopeRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiCreateOAuth2ScopeRequest) EXPECT() *MockApiCreateOAuth2ScopeRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiCreateOAuth2ScopeRequest) Execute() (*okta.OAuth2Scope, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.OAuth2Scope)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiCreateOAuth2ScopeRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiCreateOAuth2ScopeRequest)(nil).Execute))
+}
+
+// OAuth2Scope mocks base method.
+func (m *MockApiCreateOAuth2ScopeRequest) OAuth2Scope(oAuth2Scope okta.OAuth2Scope) server.ApiCreateOAuth2ScopeRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OAuth2Scope", oAuth2Scope)
+	ret0, _ := ret[0].(server.ApiCreateOAuth2ScopeRequest)
+	return ret0
+}
+
+// OAuth2Scope indicates an expected call of OAuth2Scope.
+func (mr *MockApiCreateOAuth2ScopeRequestMockRecorder) OAuth2Scope(oAuth2Scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OAuth2Scope", reflect.TypeOf((*MockApiCreateOAuth2ScopeRequest)(nil).OAuth2Scope), oAuth2Scope)
+}
+
+// MockApiListOAuth2ScopesRequest is a mock of ApiListOAuth2ScopesRequest interface.
+type MockApiListOAuth2ScopesRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiListOAuth2ScopesRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiListOAuth2ScopesRequestMockRecorder is the mock recorder for MockApiListOAuth2ScopesRequest.
+type MockApiListOAuth2ScopesRequestMockRecorder struct {
+	mock *MockApiListOAuth2ScopesRequest
+}
+
+// NewMockApiListOAuth2ScopesRequest creates a new mock instance.
+func NewMockApiListOAuth2ScopesRequest(ctrl *gomock.Controller) *MockApiListOAuth2ScopesRequest {
+	mock := &MockApiListOAuth2ScopesRequest{ctrl: ctrl}
+	mock.recorder = &MockApiListOAuth2ScopesRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiListOAuth2ScopesRequest) EXPECT() *MockApiListOAuth2ScopesRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiListOAuth2ScopesRequest) Execute() ([]okta.OAuth2Scope, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].([]okta.OAuth2Scope)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiListOAuth2ScopesRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiListOAuth2ScopesRequest)(nil).Execute))
+}
+
+// Q mocks base method.
+func (m *MockApiListOAuth2ScopesRequest) Q(q string) server.ApiListOAuth2ScopesRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Q", q)
+	ret0, _ := ret[0].(server.ApiListOAuth2ScopesRequest)
+	return ret0
+}
+
+// Q indicates an expected call of Q.
+func (mr *MockApiListOAuth2ScopesRequestMockRecorder) Q(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Q", reflect.TypeOf((*MockApiListOAuth2ScopesRequest)(nil).Q), q)
+}
+
+// MockApiDeleteOAuth2ScopeRequest is a mock of ApiDeleteOAuth2ScopeRequest interface.
+type MockApiDeleteOAuth2ScopeRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiDeleteOAuth2ScopeRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiDeleteOAuth2ScopeRequestMockRecorder is the mock recorder for MockApiDeleteOAuth2ScopeRequest.
+type MockApiDeleteOAuth2ScopeRequestMockRecorder struct {
+	mock *MockApiDeleteOAuth2ScopeRequest
+}
+
+// NewMockApiDeleteOAuth2ScopeRequest creates a new mock instance.
+func NewMockApiDeleteOAuth2ScopeRequest(ctrl *gomock.Controller) *MockApiDeleteOAuth2ScopeRequest {
+	mock := &MockApiDeleteOAuth2ScopeRequest{ctrl: ctrl}
+	mock.recorder = &MockApiDeleteOAuth2ScopeRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiDeleteOAuth2ScopeRequest) EXPECT() *MockApiDeleteOAuth2ScopeRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiDeleteOAuth2ScopeRequest) Execute() (*okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiDeleteOAuth2ScopeRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiDeleteOAuth2ScopeRequest)(nil).Execute))
+}
+
+// MockAuthorizationServerPoliciesAPI is a mock of AuthorizationServerPoliciesAPI interface.
+type MockAuthorizationServerPoliciesAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationServerPoliciesAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthorizationServerPoliciesAPIMockRecorder is the mock recorder for MockAuthorizationServerPoliciesAPI.
+type MockAuthorizationServerPoliciesAPIMockRecorder struct {
+	mock *MockAuthorizationServerPoliciesAPI
+}
+
+// NewMockAuthorizationServerPoliciesAPI creates a new mock instance.
+func NewMockAuthorizationServerPoliciesAPI(ctrl *gomock.Controller) *MockAuthorizationServerPoliciesAPI {
+	mock := &MockAuthorizationServerPoliciesAPI{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationServerPoliciesAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationServerPoliciesAPI) EXPECT() *MockAuthorizationServerPoliciesAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthorizationServerPolicy mocks base method.
+func (m *MockAuthorizationServerPoliciesAPI) CreateAuthorizationServerPolicy(ctx context.Context, authServerId string) server.ApiCreateAuthorizationServerPolicyRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorizationServerPolicy", ctx, authServerId)
+	ret0, _ := ret[0].(server.ApiCreateAuthorizationServerPolicyRequest)
+	return ret0
+}
+
+// CreateAuthorizationServerPolicy indicates an expected call of CreateAuthorizationServerPolicy.
+func (mr *MockAuthorizationServerPoliciesAPIMockRecorder) CreateAuthorizationServerPolicy(ctx, authServerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorizationServerPolicy", reflect.TypeOf((*MockAuthorizationServerPoliciesAPI)(nil).CreateAuthorizationServerPolicy), ctx, authServerId)
+}
+
+// DeleteAuthorizationServerPolicy mocks base method.
+func (m *MockAuthorizationServerPoliciesAPI) DeleteAuthorizationServerPolicy(ctx context.Context, authServerId, policyId string) server.ApiDeleteAuthorizationServerPolicyRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthorizationServerPolicy", ctx, authServerId, policyId)
+	ret0, _ := ret[0].(server.ApiDeleteAuthorizationServerPolicyRequest)
+	return ret0
+}
+
+// DeleteAuthorizationServerPolicy indicates an expected call of DeleteAuthorizationServerPolicy.
+func (mr *MockAuthorizationServerPoliciesAPIMockRecorder) DeleteAuthorizationServerPolicy(ctx, authServerId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthorizationServerPolicy", reflect.TypeOf((*MockAuthorizationServerPoliciesAPI)(nil).DeleteAuthorizationServerPolicy), ctx, authServerId, policyId)
+}
+
+// ListAuthorizationServerPolicies mocks base method.
+func (m *MockAuthorizationServerPoliciesAPI) ListAuthorizationServerPolicies(ctx context.Context, authServerId string) server.ApiListAuthorizationServerPoliciesRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorizationServerPolicies", ctx, authServerId)
+	ret0, _ := ret[0].(server.ApiListAuthorizationServerPoliciesRequest)
+	return ret0
+}
+
+// ListAuthorizationServerPolicies indicates an expected call of ListAuthorizationServerPolicies.
+func (mr *MockAuthorizationServerPoliciesAPIMockRecorder) ListAuthorizationServerPolicies(ctx, authServerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorizationServerPolicies", reflect.TypeOf((*MockAuthorizationServerPoliciesAPI)(nil).ListAuthorizationServerPolicies), ctx, authServerId)
+}
+
+// MockApiCreateAuthorizationServerPolicyRequest is a mock of ApiCreateAuthorizationServerPolicyRequest interface.
+type MockApiCreateAuthorizationServerPolicyRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiCreateAuthorizationServerPolicyRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiCreateAuthorizationServerPolicyRequestMockRecorder is the mock recorder for MockApiCreateAuthorizationServerPolicyRequest.
+type MockApiCreateAuthorizationServerPolicyRequestMockRecorder struct {
+	mock *MockApiCreateAuthorizationServerPolicyRequest
+}
+
+// NewMockApiCreateAuthorizationServerPolicyRequest creates a new mock instance.
+func NewMockApiCreateAuthorizationServerPolicyRequest(ctrl *gomock.Controller) *MockApiCreateAuthorizationServerPolicyRequest {
+	mock := &MockApiCreateAuthorizationServerPolicyRequest{ctrl: ctrl}
+	mock.recorder = &MockApiCreateAuthorizationServerPolicyRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiCreateAuthorizationServerPolicyRequest) EXPECT() *MockApiCreateAuthorizationServerPolicyRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiCreateAuthorizationServerPolicyRequest) Execute() (*okta.AuthorizationServerPolicy, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.AuthorizationServerPolicy)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiCreateAuthorizationServerPolicyRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiCreateAuthorizationServerPolicyRequest)(nil).Execute))
+}
+
+// Policy mocks base method.
+func (m *MockApiCreateAuthorizationServerPolicyRequest) Policy(policy okta.AuthorizationServerPolicy) server.ApiCreateAuthorizationServerPolicyRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Policy", policy)
+	ret0, _ := ret[0].(server.ApiCreateAuthorizationServerPolicyRequest)
+	return ret0
+}
+
+// Policy indicates an expected call of Policy.
+func (mr *MockApiCreateAuthorizationServerPolicyRequestMockRecorder) Policy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Policy", reflect.TypeOf((*MockApiCreateAuthorizationServerPolicyRequest)(nil).Policy), policy)
+}
+
+// MockApiListAuthorizationServerPoliciesRequest is a mock of ApiListAuthorizationServerPoliciesRequest interface.
+type MockApiListAuthorizationServerPoliciesRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiListAuthorizationServerPoliciesRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiListAuthorizationServerPoliciesRequestMockRecorder is the mock recorder for MockApiListAuthorizationServerPoliciesRequest.
+type MockApiListAuthorizationServerPoliciesRequestMockRecorder struct {
+	mock *MockApiListAuthorizationServerPoliciesRequest
+}
+
+// NewMockApiListAuthorizationServerPoliciesRequest creates a new mock instance.
+func NewMockApiListAuthorizationServerPoliciesRequest(ctrl *gomock.Controller) *MockApiListAuthorizationServerPoliciesRequest {
+	mock := &MockApiListAuthorizationServerPoliciesRequest{ctrl: ctrl}
+	mock.recorder = &MockApiListAuthorizationServerPoliciesRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiListAuthorizationServerPoliciesRequest) EXPECT() *MockApiListAuthorizationServerPoliciesRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiListAuthorizationServerPoliciesRequest) Execute() ([]okta.AuthorizationServerPolicy, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].([]okta.AuthorizationServerPolicy)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiListAuthorizationServerPoliciesRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiListAuthorizationServerPoliciesRequest)(nil).Execute))
+}
+
+// MockApiDeleteAuthorizationServerPolicyRequest is a mock of ApiDeleteAuthorizationServerPolicyRequest interface.
+type MockApiDeleteAuthorizationServerPolicyRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiDeleteAuthorizationServerPolicyRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiDeleteAuthorizationServerPolicyRequestMockRecorder is the mock recorder for MockApiDeleteAuthorizationServerPolicyRequest.
+type MockApiDeleteAuthorizationServerPolicyRequestMockRecorder struct {
+	mock *MockApiDeleteAuthorizationServerPolicyRequest
+}
+
+// NewMockApiDeleteAuthorizationServerPolicyRequest creates a new mock instance.
+func NewMockApiDeleteAuthorizationServerPolicyRequest(ctrl *gomock.Controller) *MockApiDeleteAuthorizationServerPolicyRequest {
+	mock := &MockApiDeleteAuthorizationServerPolicyRequest{ctrl: ctrl}
+	mock.recorder = &MockApiDeleteAuthorizationServerPolicyRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiDeleteAuthorizationServerPolicyRequest) EXPECT() *MockApiDeleteAuthorizationServerPolicyRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiDeleteAuthorizationServerPolicyRequest) Execute() (*okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiDeleteAuthorizationServerPolicyRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiDeleteAuthorizationServerPolicyRequest)(nil).Execute))
+}
+
+// MockAuthorizationServerRulesAPI is a mock of AuthorizationServerRulesAPI interface.
+type MockAuthorizationServerRulesAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationServerRulesAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthorizationServerRulesAPIMockRecorder is the mock recorder for MockAuthorizationServerRulesAPI.
+type MockAuthorizationServerRulesAPIMockRecorder struct {
+	mock *MockAuthorizationServerRulesAPI
+}
+
+// NewMockAuthorizationServerRulesAPI creates a new mock instance.
+func NewMockAuthorizationServerRulesAPI(ctrl *gomock.Controller) *MockAuthorizationServerRulesAPI {
+	mock := &MockAuthorizationServerRulesAPI{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationServerRulesAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationServerRulesAPI) EXPECT() *MockAuthorizationServerRulesAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthorizationServerPolicyRule mocks base method.
+func (m *MockAuthorizationServerRulesAPI) CreateAuthorizationServerPolicyRule(ctx context.Context, authServerId, policyId string) server.ApiCreateAuthorizationServerPolicyRuleRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorizationServerPolicyRule", ctx, authServerId, policyId)
+	ret0, _ := ret[0].(server.ApiCreateAuthorizationServerPolicyRuleRequest)
+	return ret0
+}
+
+// CreateAuthorizationServerPolicyRule indicates an expected call of CreateAuthorizationServerPolicyRule.
+func (mr *MockAuthorizationServerRulesAPIMockRecorder) CreateAuthorizationServerPolicyRule(ctx, authServerId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorizationServerPolicyRule", reflect.TypeOf((*MockAuthorizationServerRulesAPI)(nil).CreateAuthorizationServerPolicyRule), ctx, authServerId, policyId)
+}
+
+// ListAuthorizationServerPolicyRules mocks base method.
+func (m *MockAuthorizationServerRulesAPI) ListAuthorizationServerPolicyRules(ctx context.Context, authServerId, policyId string) server.ApiListAuthorizationServerPolicyRulesRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorizationServerPolicyRules", ctx, authServerId, policyId)
+	ret0, _ := ret[0].(server.ApiListAuthorizationServerPolicyRulesRequest)
+	return ret0
+}
+
+// ListAuthorizationServerPolicyRules indicates an expected call of ListAuthorizationServerPolicyRules.
+func (mr *MockAuthorizationServerRulesAPIMockRecorder) ListAuthorizationServerPolicyRules(ctx, authServerId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorizationServerPolicyRules", reflect.TypeOf((*MockAuthorizationServerRulesAPI)(nil).ListAuthorizationServerPolicyRules), ctx, authServerId, policyId)
+}
+
+// ReplaceAuthorizationServerPolicyRule mocks base method.
+func (m *MockAuthorizationServerRulesAPI) ReplaceAuthorizationServerPolicyRule(ctx context.Context, authServerId, policyId, ruleId string) server.ApiReplaceAuthorizationServerPolicyRuleRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceAuthorizationServerPolicyRule", ctx, authServerId, policyId, ruleId)
+	ret0, _ := ret[0].(server.ApiReplaceAuthorizationServerPolicyRuleRequest)
+	return ret0
+}
+
+// ReplaceAuthorizationServerPolicyRule indicates an expected call of ReplaceAuthorizationServerPolicyRule.
+func (mr *MockAuthorizationServerRulesAPIMockRecorder) ReplaceAuthorizationServerPolicyRule(ctx, authServerId, policyId, ruleId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAuthorizationServerPolicyRule", reflect.TypeOf((*MockAuthorizationServerRulesAPI)(nil).ReplaceAuthorizationServerPolicyRule), ctx, authServerId, policyId, ruleId)
+}
+
+// MockApiCreateAuthorizationServerPolicyRuleRequest is a mock of ApiCreateAuthorizationServerPolicyRuleRequest interface.
+type MockApiCreateAuthorizationServerPolicyRuleRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder is the mock recorder for MockApiCreateAuthorizationServerPolicyRuleRequest.
+type MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder struct {
+	mock *MockApiCreateAuthorizationServerPolicyRuleRequest
+}
+
+// NewMockApiCreateAuthorizationServerPolicyRuleRequest creates a new mock instance.
+func NewMockApiCreateAuthorizationServerPolicyRuleRequest(ctrl *gomock.Controller) *MockApiCreateAuthorizationServerPolicyRuleRequest {
+	mock := &MockApiCreateAuthorizationServerPolicyRuleRequest{ctrl: ctrl}
+	mock.recorder = &MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiCreateAuthorizationServerPolicyRuleRequest) EXPECT() *MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiCreateAuthorizationServerPolicyRuleRequest) Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.AuthorizationServerPolicyRule)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiCreateAuthorizationServerPolicyRuleRequest)(nil).Execute))
+}
+
+// PolicyRule mocks base method.
+func (m *MockApiCreateAuthorizationServerPolicyRuleRequest) PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) server.ApiCreateAuthorizationServerPolicyRuleRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PolicyRule", policyRule)
+	ret0, _ := ret[0].(server.ApiCreateAuthorizationServerPolicyRuleRequest)
+	return ret0
+}
+
+// PolicyRule indicates an expected call of PolicyRule.
+func (mr *MockApiCreateAuthorizationServerPolicyRuleRequestMockRecorder) PolicyRule(policyRule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PolicyRule", reflect.TypeOf((*MockApiCreateAuthorizationServerPolicyRuleRequest)(nil).PolicyRule), policyRule)
+}
+
+// MockApiListAuthorizationServerPolicyRulesRequest is a mock of ApiListAuthorizationServerPolicyRulesRequest interface.
+type MockApiListAuthorizationServerPolicyRulesRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiListAuthorizationServerPolicyRulesRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiListAuthorizationServerPolicyRulesRequestMockRecorder is the mock recorder for MockApiListAuthorizationServerPolicyRulesRequest.
+type MockApiListAuthorizationServerPolicyRulesRequestMockRecorder struct {
+	mock *MockApiListAuthorizationServerPolicyRulesRequest
+}
+
+// NewMockApiListAuthorizationServerPolicyRulesRequest creates a new mock instance.
+func NewMockApiListAuthorizationServerPolicyRulesRequest(ctrl *gomock.Controller) *MockApiListAuthorizationServerPolicyRulesRequest {
+	mock := &MockApiListAuthorizationServerPolicyRulesRequest{ctrl: ctrl}
+	mock.recorder = &MockApiListAuthorizationServerPolicyRulesRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiListAuthorizationServerPolicyRulesRequest) EXPECT() *MockApiListAuthorizationServerPolicyRulesRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiListAuthorizationServerPolicyRulesRequest) Execute() ([]okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].([]okta.AuthorizationServerPolicyRule)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiListAuthorizationServerPolicyRulesRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiListAuthorizationServerPolicyRulesRequest)(nil).Execute))
+}
+
+// MockApiReplaceAuthorizationServerPolicyRuleRequest is a mock of ApiReplaceAuthorizationServerPolicyRuleRequest interface.
+type MockApiReplaceAuthorizationServerPolicyRuleRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder
+	isgomock struct{}
+}
+
+// MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder is the mock recorder for MockApiReplaceAuthorizationServerPolicyRuleRequest.
+type MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder struct {
+	mock *MockApiReplaceAuthorizationServerPolicyRuleRequest
+}
+
+// NewMockApiReplaceAuthorizationServerPolicyRuleRequest creates a new mock instance.
+func NewMockApiReplaceAuthorizationServerPolicyRuleRequest(ctrl *gomock.Controller) *MockApiReplaceAuthorizationServerPolicyRuleRequest {
+	mock := &MockApiReplaceAuthorizationServerPolicyRuleRequest{ctrl: ctrl}
+	mock.recorder = &MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiReplaceAuthorizationServerPolicyRuleRequest) EXPECT() *MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockApiReplaceAuthorizationServerPolicyRuleRequest) Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*okta.AuthorizationServerPolicyRule)
+	ret1, _ := ret[1].(*okta.APIResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockApiReplaceAuthorizationServerPolicyRuleRequest)(nil).Execute))
+}
+
+// PolicyRule mocks base method.
+func (m *MockApiReplaceAuthorizationServerPolicyRuleRequest) PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) server.ApiReplaceAuthorizationServerPolicyRuleRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PolicyRule", policyRule)
+	ret0, _ := ret[0].(server.ApiReplaceAuthorizationServerPolicyRuleRequest)
+	return ret0
+}
+
+// PolicyRule indicates an expected call of PolicyRule.
+func (mr *MockApiReplaceAuthorizationServerPolicyRuleRequestMockRecorder) PolicyRule(policyRule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PolicyRule", reflect.TypeOf((*MockApiReplaceAuthorizationServerPolicyRuleRequest)(nil).PolicyRule), policyRule)
+}
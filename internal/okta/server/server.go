@@ -1,33 +1,31 @@
 package server
 
 import (
+	"bytes"
 	"context"
-	"log"
-	"net"
+	"io"
 	"net/http"
 	"os"
 
-	"github.com/getkin/kin-openapi/openapi3filter"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
-	middleware "github.com/oapi-codegen/echo-middleware"
 	"github.com/okta/okta-sdk-golang/v6/okta"
 	"github.com/rotisserie/eris"
 	"github.com/spf13/pflag"
 
-	portalv1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
+	"github.com/solo-io/gloo-portal-idp-connect/internal/connector"
 )
 
 type Options struct {
-	Port       string
-	OktaDomain string
-	APIToken   string
+	connector.Options
+	OktaDomain          string
+	APIToken            string
+	AuthorizationServer string
 }
 
 func (o *Options) AddToFlags(flag *pflag.FlagSet) {
-	flag.StringVar(&o.Port, "port", "8080", "Port for HTTP server")
+	o.Options.AddToFlags(flag)
 	flag.StringVar(&o.OktaDomain, "okta-domain", "", "Okta domain (e.g. https://dev-123456.okta.com)")
 	flag.StringVar(&o.APIToken, "api-token", "", "Okta API token for application management")
+	flag.StringVar(&o.AuthorizationServer, "authorization-server", "default", "ID of the Okta authorization server to configure API Product scopes")
 }
 
 func (o *Options) Validate() error {
@@ -56,25 +54,45 @@ func (w *oktaClientWrapper) GetApplicationAPI() ApplicationAPI {
 	return &applicationAPIWrapper{api: w.apiClient.ApplicationAPI}
 }
 
+func (w *oktaClientWrapper) GetApplicationSecretsAPI() ApplicationSecretsAPI {
+	return &applicationSecretsAPIWrapper{api: w.apiClient.ApplicationSSOPublicKeysAPI}
+}
+
+func (w *oktaClientWrapper) GetAuthorizationServerScopesAPI() AuthorizationServerScopesAPI {
+	return &authorizationServerScopesAPIWrapper{api: w.apiClient.AuthorizationServerScopesAPI}
+}
+
+func (w *oktaClientWrapper) GetAuthorizationServerPoliciesAPI() AuthorizationServerPoliciesAPI {
+	return &authorizationServerPoliciesAPIWrapper{api: w.apiClient.AuthorizationServerPoliciesAPI}
+}
+
+func (w *oktaClientWrapper) GetAuthorizationServerRulesAPI() AuthorizationServerRulesAPI {
+	return &authorizationServerRulesAPIWrapper{api: w.apiClient.AuthorizationServerRulesAPI}
+}
+
 // applicationAPIWrapper wraps the SDK ApplicationAPI to match our interface
 type applicationAPIWrapper struct {
 	api okta.ApplicationAPI
 }
 
 func (w *applicationAPIWrapper) CreateApplication(ctx context.Context) ApiCreateApplicationRequest {
-	return &createApplicationRequestWrapper{req: w.api.CreateApplication(ctx)}
+	return &createApplicationRequestWrapper{req: w.api.CreateApplication(connector.WithUpstreamOperation(ctx, "CreateApplication"))}
+}
+
+func (w *applicationAPIWrapper) GetApplication(ctx context.Context, appId string) ApiGetApplicationRequest {
+	return &getApplicationRequestWrapper{req: w.api.GetApplication(connector.WithUpstreamOperation(ctx, "GetApplication"), appId)}
 }
 
 func (w *applicationAPIWrapper) ListApplications(ctx context.Context) ApiListApplicationsRequest {
-	return &listApplicationsRequestWrapper{req: w.api.ListApplications(ctx)}
+	return &listApplicationsRequestWrapper{req: w.api.ListApplications(connector.WithUpstreamOperation(ctx, "ListApplications"))}
 }
 
 func (w *applicationAPIWrapper) DeactivateApplication(ctx context.Context, appId string) ApiDeactivateApplicationRequest {
-	return &deactivateApplicationRequestWrapper{req: w.api.DeactivateApplication(ctx, appId)}
+	return &deactivateApplicationRequestWrapper{req: w.api.DeactivateApplication(connector.WithUpstreamOperation(ctx, "DeactivateApplication"), appId)}
 }
 
 func (w *applicationAPIWrapper) DeleteApplication(ctx context.Context, appId string) ApiDeleteApplicationRequest {
-	return &deleteApplicationRequestWrapper{req: w.api.DeleteApplication(ctx, appId)}
+	return &deleteApplicationRequestWrapper{req: w.api.DeleteApplication(connector.WithUpstreamOperation(ctx, "DeleteApplication"), appId)}
 }
 
 // Request wrappers
@@ -91,10 +109,38 @@ func (w *createApplicationRequestWrapper) Execute() (*okta.ListApplications200Re
 	return w.req.Execute()
 }
 
+type getApplicationRequestWrapper struct {
+	req okta.ApiGetApplicationRequest
+}
+
+func (w *getApplicationRequestWrapper) Execute() (*okta.ListApplications200ResponseInner, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
 type listApplicationsRequestWrapper struct {
 	req okta.ApiListApplicationsRequest
 }
 
+func (w *listApplicationsRequestWrapper) After(after string) ApiListApplicationsRequest {
+	w.req = w.req.After(after)
+	return w
+}
+
+func (w *listApplicationsRequestWrapper) Limit(limit int32) ApiListApplicationsRequest {
+	w.req = w.req.Limit(limit)
+	return w
+}
+
+func (w *listApplicationsRequestWrapper) Filter(filter string) ApiListApplicationsRequest {
+	w.req = w.req.Filter(filter)
+	return w
+}
+
+func (w *listApplicationsRequestWrapper) Q(q string) ApiListApplicationsRequest {
+	w.req = w.req.Q(q)
+	return w
+}
+
 func (w *listApplicationsRequestWrapper) Execute() ([]okta.ListApplications200ResponseInner, *okta.APIResponse, error) {
 	return w.req.Execute()
 }
@@ -115,24 +161,220 @@ func (w *deleteApplicationRequestWrapper) Execute() (*okta.APIResponse, error) {
 	return w.req.Execute()
 }
 
+// applicationSecretsAPIWrapper wraps the client secret operations of the SDK ApplicationSSOPublicKeysAPI to match our
+// interface
+type applicationSecretsAPIWrapper struct {
+	api okta.ApplicationSSOPublicKeysAPI
+}
+
+func (w *applicationSecretsAPIWrapper) CreateOAuth2ClientSecret(ctx context.Context, appId string) ApiCreateOAuth2ClientSecretRequest {
+	return &createOAuth2ClientSecretRequestWrapper{req: w.api.CreateOAuth2ClientSecret(connector.WithUpstreamOperation(ctx, "CreateOAuth2ClientSecret"), appId)}
+}
+
+func (w *applicationSecretsAPIWrapper) ListOAuth2ClientSecrets(ctx context.Context, appId string) ApiListOAuth2ClientSecretsRequest {
+	return &listOAuth2ClientSecretsRequestWrapper{req: w.api.ListOAuth2ClientSecrets(connector.WithUpstreamOperation(ctx, "ListOAuth2ClientSecrets"), appId)}
+}
+
+func (w *applicationSecretsAPIWrapper) DeactivateOAuth2ClientSecret(ctx context.Context, appId string, secretId string) ApiDeactivateOAuth2ClientSecretRequest {
+	return &deactivateOAuth2ClientSecretRequestWrapper{req: w.api.DeactivateOAuth2ClientSecret(connector.WithUpstreamOperation(ctx, "DeactivateOAuth2ClientSecret"), appId, secretId)}
+}
+
+func (w *applicationSecretsAPIWrapper) DeleteOAuth2ClientSecret(ctx context.Context, appId string, secretId string) ApiDeleteOAuth2ClientSecretRequest {
+	return &deleteOAuth2ClientSecretRequestWrapper{req: w.api.DeleteOAuth2ClientSecret(connector.WithUpstreamOperation(ctx, "DeleteOAuth2ClientSecret"), appId, secretId)}
+}
+
+type createOAuth2ClientSecretRequestWrapper struct {
+	req okta.ApiCreateOAuth2ClientSecretRequest
+}
+
+func (w *createOAuth2ClientSecretRequestWrapper) Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type listOAuth2ClientSecretsRequestWrapper struct {
+	req okta.ApiListOAuth2ClientSecretsRequest
+}
+
+func (w *listOAuth2ClientSecretsRequestWrapper) Execute() ([]okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type deactivateOAuth2ClientSecretRequestWrapper struct {
+	req okta.ApiDeactivateOAuth2ClientSecretRequest
+}
+
+func (w *deactivateOAuth2ClientSecretRequestWrapper) Execute() (*okta.OAuth2ClientSecret, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type deleteOAuth2ClientSecretRequestWrapper struct {
+	req okta.ApiDeleteOAuth2ClientSecretRequest
+}
+
+func (w *deleteOAuth2ClientSecretRequestWrapper) Execute() (*okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+// authorizationServerScopesAPIWrapper wraps the SDK AuthorizationServerScopesAPI to match our interface
+type authorizationServerScopesAPIWrapper struct {
+	api okta.AuthorizationServerScopesAPI
+}
+
+func (w *authorizationServerScopesAPIWrapper) CreateOAuth2Scope(ctx context.Context, authServerId string) ApiCreateOAuth2ScopeRequest {
+	return &createOAuth2ScopeRequestWrapper{req: w.api.CreateOAuth2Scope(connector.WithUpstreamOperation(ctx, "CreateOAuth2Scope"), authServerId)}
+}
+
+func (w *authorizationServerScopesAPIWrapper) ListOAuth2Scopes(ctx context.Context, authServerId string) ApiListOAuth2ScopesRequest {
+	return &listOAuth2ScopesRequestWrapper{req: w.api.ListOAuth2Scopes(connector.WithUpstreamOperation(ctx, "ListOAuth2Scopes"), authServerId)}
+}
+
+func (w *authorizationServerScopesAPIWrapper) DeleteOAuth2Scope(ctx context.Context, authServerId string, scopeId string) ApiDeleteOAuth2ScopeRequest {
+	return &deleteOAuth2ScopeRequestWrapper{req: w.api.DeleteOAuth2Scope(connector.WithUpstreamOperation(ctx, "DeleteOAuth2Scope"), authServerId, scopeId)}
+}
+
+type createOAuth2ScopeRequestWrapper struct {
+	req okta.ApiCreateOAuth2ScopeRequest
+}
+
+func (w *createOAuth2ScopeRequestWrapper) OAuth2Scope(oAuth2Scope okta.OAuth2Scope) ApiCreateOAuth2ScopeRequest {
+	w.req = w.req.OAuth2Scope(oAuth2Scope)
+	return w
+}
+
+func (w *createOAuth2ScopeRequestWrapper) Execute() (*okta.OAuth2Scope, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type listOAuth2ScopesRequestWrapper struct {
+	req okta.ApiListOAuth2ScopesRequest
+}
+
+func (w *listOAuth2ScopesRequestWrapper) Q(q string) ApiListOAuth2ScopesRequest {
+	w.req = w.req.Q(q)
+	return w
+}
+
+func (w *listOAuth2ScopesRequestWrapper) Execute() ([]okta.OAuth2Scope, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type deleteOAuth2ScopeRequestWrapper struct {
+	req okta.ApiDeleteOAuth2ScopeRequest
+}
+
+func (w *deleteOAuth2ScopeRequestWrapper) Execute() (*okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+// authorizationServerPoliciesAPIWrapper wraps the SDK AuthorizationServerPoliciesAPI to match our interface
+type authorizationServerPoliciesAPIWrapper struct {
+	api okta.AuthorizationServerPoliciesAPI
+}
+
+func (w *authorizationServerPoliciesAPIWrapper) CreateAuthorizationServerPolicy(ctx context.Context, authServerId string) ApiCreateAuthorizationServerPolicyRequest {
+	return &createAuthorizationServerPolicyRequestWrapper{req: w.api.CreateAuthorizationServerPolicy(connector.WithUpstreamOperation(ctx, "CreateAuthorizationServerPolicy"), authServerId)}
+}
+
+func (w *authorizationServerPoliciesAPIWrapper) ListAuthorizationServerPolicies(ctx context.Context, authServerId string) ApiListAuthorizationServerPoliciesRequest {
+	return &listAuthorizationServerPoliciesRequestWrapper{req: w.api.ListAuthorizationServerPolicies(connector.WithUpstreamOperation(ctx, "ListAuthorizationServerPolicies"), authServerId)}
+}
+
+func (w *authorizationServerPoliciesAPIWrapper) DeleteAuthorizationServerPolicy(ctx context.Context, authServerId string, policyId string) ApiDeleteAuthorizationServerPolicyRequest {
+	return &deleteAuthorizationServerPolicyRequestWrapper{req: w.api.DeleteAuthorizationServerPolicy(connector.WithUpstreamOperation(ctx, "DeleteAuthorizationServerPolicy"), authServerId, policyId)}
+}
+
+type createAuthorizationServerPolicyRequestWrapper struct {
+	req okta.ApiCreateAuthorizationServerPolicyRequest
+}
+
+func (w *createAuthorizationServerPolicyRequestWrapper) Policy(policy okta.AuthorizationServerPolicy) ApiCreateAuthorizationServerPolicyRequest {
+	w.req = w.req.Policy(policy)
+	return w
+}
+
+func (w *createAuthorizationServerPolicyRequestWrapper) Execute() (*okta.AuthorizationServerPolicy, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type listAuthorizationServerPoliciesRequestWrapper struct {
+	req okta.ApiListAuthorizationServerPoliciesRequest
+}
+
+func (w *listAuthorizationServerPoliciesRequestWrapper) Execute() ([]okta.AuthorizationServerPolicy, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type deleteAuthorizationServerPolicyRequestWrapper struct {
+	req okta.ApiDeleteAuthorizationServerPolicyRequest
+}
+
+func (w *deleteAuthorizationServerPolicyRequestWrapper) Execute() (*okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+// authorizationServerRulesAPIWrapper wraps the SDK AuthorizationServerRulesAPI to match our interface
+type authorizationServerRulesAPIWrapper struct {
+	api okta.AuthorizationServerRulesAPI
+}
+
+func (w *authorizationServerRulesAPIWrapper) CreateAuthorizationServerPolicyRule(ctx context.Context, authServerId string, policyId string) ApiCreateAuthorizationServerPolicyRuleRequest {
+	return &createAuthorizationServerPolicyRuleRequestWrapper{req: w.api.CreateAuthorizationServerPolicyRule(connector.WithUpstreamOperation(ctx, "CreateAuthorizationServerPolicyRule"), authServerId, policyId)}
+}
+
+func (w *authorizationServerRulesAPIWrapper) ListAuthorizationServerPolicyRules(ctx context.Context, authServerId string, policyId string) ApiListAuthorizationServerPolicyRulesRequest {
+	return &listAuthorizationServerPolicyRulesRequestWrapper{req: w.api.ListAuthorizationServerPolicyRules(connector.WithUpstreamOperation(ctx, "ListAuthorizationServerPolicyRules"), authServerId, policyId)}
+}
+
+func (w *authorizationServerRulesAPIWrapper) ReplaceAuthorizationServerPolicyRule(ctx context.Context, authServerId string, policyId string, ruleId string) ApiReplaceAuthorizationServerPolicyRuleRequest {
+	return &replaceAuthorizationServerPolicyRuleRequestWrapper{req: w.api.ReplaceAuthorizationServerPolicyRule(connector.WithUpstreamOperation(ctx, "ReplaceAuthorizationServerPolicyRule"), authServerId, policyId, ruleId)}
+}
+
+type createAuthorizationServerPolicyRuleRequestWrapper struct {
+	req okta.ApiCreateAuthorizationServerPolicyRuleRequest
+}
+
+func (w *createAuthorizationServerPolicyRuleRequestWrapper) PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) ApiCreateAuthorizationServerPolicyRuleRequest {
+	w.req = w.req.PolicyRule(policyRule)
+	return w
+}
+
+func (w *createAuthorizationServerPolicyRuleRequestWrapper) Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type listAuthorizationServerPolicyRulesRequestWrapper struct {
+	req okta.ApiListAuthorizationServerPolicyRulesRequest
+}
+
+func (w *listAuthorizationServerPolicyRulesRequestWrapper) Execute() ([]okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
+type replaceAuthorizationServerPolicyRuleRequestWrapper struct {
+	req okta.ApiReplaceAuthorizationServerPolicyRuleRequest
+}
+
+func (w *replaceAuthorizationServerPolicyRuleRequestWrapper) PolicyRule(policyRule okta.AuthorizationServerPolicyRuleRequest) ApiReplaceAuthorizationServerPolicyRuleRequest {
+	w.req = w.req.PolicyRule(policyRule)
+	return w
+}
+
+func (w *replaceAuthorizationServerPolicyRuleRequestWrapper) Execute() (*okta.AuthorizationServerPolicyRule, *okta.APIResponse, error) {
+	return w.req.Execute()
+}
+
 func ListenAndServe(ctx context.Context, opts *Options) error {
 	if err := opts.Validate(); err != nil {
 		return err
 	}
 
-	swagger, err := portalv1.GetSwagger()
-	if err != nil {
-		return eris.Wrap(err, "could not load swagger spec")
-	}
-
-	// Clear out the servers array in the swagger spec, that skips validating
-	// that server names match. We don't know how this thing will be run.
-	swagger.Servers = nil
-
 	// Initialize Okta SDK client
 	config, err := okta.NewConfiguration(
 		okta.WithOrgUrl(opts.OktaDomain),
 		okta.WithToken(opts.APIToken),
+		okta.WithHttpClientPtr(&http.Client{Transport: rateLimitedTransport{base: opts.UpstreamTransport("okta", nil)}}),
+		// Calls are retried by the transport, which honors the rate limit headers the same way
+		okta.WithRateLimitMaxRetries(0),
 	)
 	if err != nil {
 		return eris.Wrap(err, "failed to create Okta configuration")
@@ -141,32 +383,37 @@ func ListenAndServe(ctx context.Context, opts *Options) error {
 	oktaAPIClient := okta.NewAPIClient(config)
 	oktaClient := &oktaClientWrapper{apiClient: oktaAPIClient}
 
-	// Create an instance of our handler which satisfies the generated interface
-	oktaHandler := NewStrictServerHandler(oktaClient)
-	portalHandler := portalv1.NewStrictHandler(oktaHandler, nil)
+	return connector.ListenAndServe(ctx, &opts.Options, NewStrictServerHandler(opts, oktaClient))
+}
 
-	e := echo.New()
+// RateLimitedError is the error of a call that Okta was still rate limiting once it had been retried. The SDK drops the
+// response of such a call, so it is kept here instead.
+type RateLimitedError struct {
+	Response *http.Response
+}
 
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	e.Use(echomiddleware.Logger())
+func (e *RateLimitedError) Error() string {
+	return "too many requests"
+}
 
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	e.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
-		Options: openapi3filter.Options{
-			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
-		}},
-	))
+// rateLimitedTransport fails calls that Okta responds to with 429 Too Many Requests with a *RateLimitedError, so that
+// the SDK does not drop their response.
+type rateLimitedTransport struct {
+	base http.RoundTripper
+}
 
-	// We now register our portal handler above as the handler for the interface
-	portalv1.RegisterHandlers(e, portalHandler)
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
 
-	s := &http.Server{
-		Handler: e,
-		Addr:    net.JoinHostPort("0.0.0.0", opts.Port),
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
 
-	log.Printf("Starting server on port %v\n", opts.Port)
-	return s.ListenAndServe()
+	return nil, &RateLimitedError{Response: resp}
 }
@@ -16,12 +16,33 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Creates an application of type oauth2.
+	// Creates an API product.
+	// (POST /api-products)
+	CreateAPIProduct(ctx echo.Context, params CreateAPIProductParams) error
+	// Delete an API product in the OIDC provider.
+	// (DELETE /api-products/{id})
+	DeleteAPIProduct(ctx echo.Context, id string, params DeleteAPIProductParams) error
+	// List clients in the OIDC provider.
+	// (GET /applications)
+	ListOAuthApplications(ctx echo.Context, params ListOAuthApplicationsParams) error
+	// Creates an OAuth2 client.
 	// (POST /applications)
 	CreateOAuthApplication(ctx echo.Context, params CreateOAuthApplicationParams) error
-	// Deletes an application in the OpenID Connect Provider.
+	// Delete a client in the OIDC provider.
 	// (DELETE /applications/{id})
 	DeleteOAuthApplication(ctx echo.Context, id string, params DeleteOAuthApplicationParams) error
+	// Get a client in the OIDC provider.
+	// (GET /applications/{id})
+	GetOAuthApplication(ctx echo.Context, id string, params GetOAuthApplicationParams) error
+	// Revoke a client's access to API products.
+	// (DELETE /applications/{id}/api-products)
+	RevokeAPIProductAccess(ctx echo.Context, id string, params RevokeAPIProductAccessParams) error
+	// Grant a client access to API products.
+	// (PUT /applications/{id}/api-products)
+	GrantAPIProductAccess(ctx echo.Context, id string, params GrantAPIProductAccessParams) error
+	// Rotate the secret of a client in the OIDC provider.
+	// (POST /applications/{id}/secret)
+	RotateOAuthApplicationSecret(ctx echo.Context, id string, params RotateOAuthApplicationSecretParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -29,6 +50,113 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// CreateAPIProduct converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAPIProduct(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateAPIProductParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateAPIProduct(ctx, params)
+	return err
+}
+
+// DeleteAPIProduct converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAPIProduct(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteAPIProductParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAPIProduct(ctx, id, params)
+	return err
+}
+
+// ListOAuthApplications converts echo context to params.
+func (w *ServerInterfaceWrapper) ListOAuthApplications(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListOAuthApplicationsParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListOAuthApplications(ctx, params)
+	return err
+}
+
 // CreateOAuthApplication converts echo context to params.
 func (w *ServerInterfaceWrapper) CreateOAuthApplication(ctx echo.Context) error {
 	var err error
@@ -94,6 +222,156 @@ func (w *ServerInterfaceWrapper) DeleteOAuthApplication(ctx echo.Context) error
 	return err
 }
 
+// GetOAuthApplication converts echo context to params.
+func (w *ServerInterfaceWrapper) GetOAuthApplication(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetOAuthApplicationParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetOAuthApplication(ctx, id, params)
+	return err
+}
+
+// RevokeAPIProductAccess converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeAPIProductAccess(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RevokeAPIProductAccessParams
+	// ------------- Required query parameter "apiProducts" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "apiProducts", ctx.QueryParams(), &params.ApiProducts)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiProducts: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokeAPIProductAccess(ctx, id, params)
+	return err
+}
+
+// GrantAPIProductAccess converts echo context to params.
+func (w *ServerInterfaceWrapper) GrantAPIProductAccess(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GrantAPIProductAccessParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GrantAPIProductAccess(ctx, id, params)
+	return err
+}
+
+// RotateOAuthApplicationSecret converts echo context to params.
+func (w *ServerInterfaceWrapper) RotateOAuthApplicationSecret(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RotateOAuthApplicationSecretParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+		}
+
+		params.Token = &Token
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RotateOAuthApplicationSecret(ctx, id, params)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -122,90 +400,727 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/api-products", wrapper.CreateAPIProduct)
+	router.DELETE(baseURL+"/api-products/:id", wrapper.DeleteAPIProduct)
+	router.GET(baseURL+"/applications", wrapper.ListOAuthApplications)
 	router.POST(baseURL+"/applications", wrapper.CreateOAuthApplication)
 	router.DELETE(baseURL+"/applications/:id", wrapper.DeleteOAuthApplication)
+	router.GET(baseURL+"/applications/:id", wrapper.GetOAuthApplication)
+	router.DELETE(baseURL+"/applications/:id/api-products", wrapper.RevokeAPIProductAccess)
+	router.PUT(baseURL+"/applications/:id/api-products", wrapper.GrantAPIProductAccess)
+	router.POST(baseURL+"/applications/:id/secret", wrapper.RotateOAuthApplicationSecret)
 
 }
 
-type CreateOAuthApplicationRequestObject struct {
-	Params CreateOAuthApplicationParams
-	Body   *CreateOAuthApplicationJSONRequestBody
+type CreateAPIProductRequestObject struct {
+	Params CreateAPIProductParams
+	Body   *CreateAPIProductJSONRequestBody
 }
 
-type CreateOAuthApplicationResponseObject interface {
-	VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error
+type CreateAPIProductResponseObject interface {
+	VisitCreateAPIProductResponse(w http.ResponseWriter) error
 }
 
-type CreateOAuthApplication201JSONResponse OAuthApplication
+type CreateAPIProduct201JSONResponse APIProduct
 
-func (response CreateOAuthApplication201JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+func (response CreateAPIProduct201JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(201)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateOAuthApplication400JSONResponse Error
+type CreateAPIProduct400JSONResponse Error
 
-func (response CreateOAuthApplication400JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+func (response CreateAPIProduct400JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateOAuthApplication500JSONResponse Error
+type CreateAPIProduct401JSONResponse Error
 
-func (response CreateOAuthApplication500JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+func (response CreateAPIProduct401JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteOAuthApplicationRequestObject struct {
-	Id     string `json:"id"`
-	Params DeleteOAuthApplicationParams
-}
+type CreateAPIProduct403JSONResponse Error
 
-type DeleteOAuthApplicationResponseObject interface {
-	VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error
-}
+func (response CreateAPIProduct403JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
 
-type DeleteOAuthApplication204Response struct {
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (response DeleteOAuthApplication204Response) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
+type CreateAPIProduct409JSONResponse Error
+
+func (response CreateAPIProduct409JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteOAuthApplication404JSONResponse Error
+type CreateAPIProduct429JSONResponse Error
 
-func (response DeleteOAuthApplication404JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+func (response CreateAPIProduct429JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	w.WriteHeader(429)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteOAuthApplication500JSONResponse Error
+type CreateAPIProduct500JSONResponse Error
 
-func (response DeleteOAuthApplication500JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+func (response CreateAPIProduct500JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(500)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Creates an application of type oauth2.
+type CreateAPIProduct503JSONResponse Error
+
+func (response CreateAPIProduct503JSONResponse) VisitCreateAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProductRequestObject struct {
+	Id     string `json:"id"`
+	Params DeleteAPIProductParams
+}
+
+type DeleteAPIProductResponseObject interface {
+	VisitDeleteAPIProductResponse(w http.ResponseWriter) error
+}
+
+type DeleteAPIProduct204Response struct {
+}
+
+func (response DeleteAPIProduct204Response) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAPIProduct401JSONResponse Error
+
+func (response DeleteAPIProduct401JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProduct403JSONResponse Error
+
+func (response DeleteAPIProduct403JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProduct404JSONResponse Error
+
+func (response DeleteAPIProduct404JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProduct429JSONResponse Error
+
+func (response DeleteAPIProduct429JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProduct500JSONResponse Error
+
+func (response DeleteAPIProduct500JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIProduct503JSONResponse Error
+
+func (response DeleteAPIProduct503JSONResponse) VisitDeleteAPIProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplicationsRequestObject struct {
+	Params ListOAuthApplicationsParams
+}
+
+type ListOAuthApplicationsResponseObject interface {
+	VisitListOAuthApplicationsResponse(w http.ResponseWriter) error
+}
+
+type ListOAuthApplications200JSONResponse OAuthApplicationPage
+
+func (response ListOAuthApplications200JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications400JSONResponse Error
+
+func (response ListOAuthApplications400JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications401JSONResponse Error
+
+func (response ListOAuthApplications401JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications403JSONResponse Error
+
+func (response ListOAuthApplications403JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications429JSONResponse Error
+
+func (response ListOAuthApplications429JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications500JSONResponse Error
+
+func (response ListOAuthApplications500JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListOAuthApplications503JSONResponse Error
+
+func (response ListOAuthApplications503JSONResponse) VisitListOAuthApplicationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplicationRequestObject struct {
+	Params CreateOAuthApplicationParams
+	Body   *CreateOAuthApplicationJSONRequestBody
+}
+
+type CreateOAuthApplicationResponseObject interface {
+	VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error
+}
+
+type CreateOAuthApplication201JSONResponse OAuthApplication
+
+func (response CreateOAuthApplication201JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication400JSONResponse Error
+
+func (response CreateOAuthApplication400JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication401JSONResponse Error
+
+func (response CreateOAuthApplication401JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication403JSONResponse Error
+
+func (response CreateOAuthApplication403JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication409JSONResponse Error
+
+func (response CreateOAuthApplication409JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication429JSONResponse Error
+
+func (response CreateOAuthApplication429JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication500JSONResponse Error
+
+func (response CreateOAuthApplication500JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateOAuthApplication503JSONResponse Error
+
+func (response CreateOAuthApplication503JSONResponse) VisitCreateOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplicationRequestObject struct {
+	Id     string `json:"id"`
+	Params DeleteOAuthApplicationParams
+}
+
+type DeleteOAuthApplicationResponseObject interface {
+	VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error
+}
+
+type DeleteOAuthApplication204Response struct {
+}
+
+func (response DeleteOAuthApplication204Response) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteOAuthApplication401JSONResponse Error
+
+func (response DeleteOAuthApplication401JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplication403JSONResponse Error
+
+func (response DeleteOAuthApplication403JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplication404JSONResponse Error
+
+func (response DeleteOAuthApplication404JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplication429JSONResponse Error
+
+func (response DeleteOAuthApplication429JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplication500JSONResponse Error
+
+func (response DeleteOAuthApplication500JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteOAuthApplication503JSONResponse Error
+
+func (response DeleteOAuthApplication503JSONResponse) VisitDeleteOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplicationRequestObject struct {
+	Id     string `json:"id"`
+	Params GetOAuthApplicationParams
+}
+
+type GetOAuthApplicationResponseObject interface {
+	VisitGetOAuthApplicationResponse(w http.ResponseWriter) error
+}
+
+type GetOAuthApplication200JSONResponse OAuthApplicationDetails
+
+func (response GetOAuthApplication200JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication401JSONResponse Error
+
+func (response GetOAuthApplication401JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication403JSONResponse Error
+
+func (response GetOAuthApplication403JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication404JSONResponse Error
+
+func (response GetOAuthApplication404JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication429JSONResponse Error
+
+func (response GetOAuthApplication429JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication500JSONResponse Error
+
+func (response GetOAuthApplication500JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOAuthApplication503JSONResponse Error
+
+func (response GetOAuthApplication503JSONResponse) VisitGetOAuthApplicationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccessRequestObject struct {
+	Id     string `json:"id"`
+	Params RevokeAPIProductAccessParams
+}
+
+type RevokeAPIProductAccessResponseObject interface {
+	VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error
+}
+
+type RevokeAPIProductAccess204Response struct {
+}
+
+func (response RevokeAPIProductAccess204Response) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeAPIProductAccess400JSONResponse Error
+
+func (response RevokeAPIProductAccess400JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess401JSONResponse Error
+
+func (response RevokeAPIProductAccess401JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess403JSONResponse Error
+
+func (response RevokeAPIProductAccess403JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess404JSONResponse Error
+
+func (response RevokeAPIProductAccess404JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess429JSONResponse Error
+
+func (response RevokeAPIProductAccess429JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess500JSONResponse Error
+
+func (response RevokeAPIProductAccess500JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIProductAccess503JSONResponse Error
+
+func (response RevokeAPIProductAccess503JSONResponse) VisitRevokeAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccessRequestObject struct {
+	Id     string `json:"id"`
+	Params GrantAPIProductAccessParams
+	Body   *GrantAPIProductAccessJSONRequestBody
+}
+
+type GrantAPIProductAccessResponseObject interface {
+	VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error
+}
+
+type GrantAPIProductAccess204Response struct {
+}
+
+func (response GrantAPIProductAccess204Response) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type GrantAPIProductAccess400JSONResponse Error
+
+func (response GrantAPIProductAccess400JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess401JSONResponse Error
+
+func (response GrantAPIProductAccess401JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess403JSONResponse Error
+
+func (response GrantAPIProductAccess403JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess404JSONResponse Error
+
+func (response GrantAPIProductAccess404JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess429JSONResponse Error
+
+func (response GrantAPIProductAccess429JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess500JSONResponse Error
+
+func (response GrantAPIProductAccess500JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GrantAPIProductAccess503JSONResponse Error
+
+func (response GrantAPIProductAccess503JSONResponse) VisitGrantAPIProductAccessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecretRequestObject struct {
+	Id     string `json:"id"`
+	Params RotateOAuthApplicationSecretParams
+}
+
+type RotateOAuthApplicationSecretResponseObject interface {
+	VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error
+}
+
+type RotateOAuthApplicationSecret200JSONResponse OAuthApplication
+
+func (response RotateOAuthApplicationSecret200JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret401JSONResponse Error
+
+func (response RotateOAuthApplicationSecret401JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret403JSONResponse Error
+
+func (response RotateOAuthApplicationSecret403JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret404JSONResponse Error
+
+func (response RotateOAuthApplicationSecret404JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret429JSONResponse Error
+
+func (response RotateOAuthApplicationSecret429JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret500JSONResponse Error
+
+func (response RotateOAuthApplicationSecret500JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateOAuthApplicationSecret503JSONResponse Error
+
+func (response RotateOAuthApplicationSecret503JSONResponse) VisitRotateOAuthApplicationSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Creates an API product.
+	// (POST /api-products)
+	CreateAPIProduct(ctx context.Context, request CreateAPIProductRequestObject) (CreateAPIProductResponseObject, error)
+	// Delete an API product in the OIDC provider.
+	// (DELETE /api-products/{id})
+	DeleteAPIProduct(ctx context.Context, request DeleteAPIProductRequestObject) (DeleteAPIProductResponseObject, error)
+	// List clients in the OIDC provider.
+	// (GET /applications)
+	ListOAuthApplications(ctx context.Context, request ListOAuthApplicationsRequestObject) (ListOAuthApplicationsResponseObject, error)
+	// Creates an OAuth2 client.
 	// (POST /applications)
 	CreateOAuthApplication(ctx context.Context, request CreateOAuthApplicationRequestObject) (CreateOAuthApplicationResponseObject, error)
-	// Deletes an application in the OpenID Connect Provider.
+	// Delete a client in the OIDC provider.
 	// (DELETE /applications/{id})
 	DeleteOAuthApplication(ctx context.Context, request DeleteOAuthApplicationRequestObject) (DeleteOAuthApplicationResponseObject, error)
+	// Get a client in the OIDC provider.
+	// (GET /applications/{id})
+	GetOAuthApplication(ctx context.Context, request GetOAuthApplicationRequestObject) (GetOAuthApplicationResponseObject, error)
+	// Revoke a client's access to API products.
+	// (DELETE /applications/{id}/api-products)
+	RevokeAPIProductAccess(ctx context.Context, request RevokeAPIProductAccessRequestObject) (RevokeAPIProductAccessResponseObject, error)
+	// Grant a client access to API products.
+	// (PUT /applications/{id}/api-products)
+	GrantAPIProductAccess(ctx context.Context, request GrantAPIProductAccessRequestObject) (GrantAPIProductAccessResponseObject, error)
+	// Rotate the secret of a client in the OIDC provider.
+	// (POST /applications/{id}/secret)
+	RotateOAuthApplicationSecret(ctx context.Context, request RotateOAuthApplicationSecretRequestObject) (RotateOAuthApplicationSecretResponseObject, error)
 }
 
 type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
@@ -220,6 +1135,88 @@ type strictHandler struct {
 	middlewares []StrictMiddlewareFunc
 }
 
+// CreateAPIProduct operation middleware
+func (sh *strictHandler) CreateAPIProduct(ctx echo.Context, params CreateAPIProductParams) error {
+	var request CreateAPIProductRequestObject
+
+	request.Params = params
+
+	var body CreateAPIProductJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateAPIProduct(ctx.Request().Context(), request.(CreateAPIProductRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateAPIProduct")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(CreateAPIProductResponseObject); ok {
+		return validResponse.VisitCreateAPIProductResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// DeleteAPIProduct operation middleware
+func (sh *strictHandler) DeleteAPIProduct(ctx echo.Context, id string, params DeleteAPIProductParams) error {
+	var request DeleteAPIProductRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAPIProduct(ctx.Request().Context(), request.(DeleteAPIProductRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAPIProduct")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(DeleteAPIProductResponseObject); ok {
+		return validResponse.VisitDeleteAPIProductResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ListOAuthApplications operation middleware
+func (sh *strictHandler) ListOAuthApplications(ctx echo.Context, params ListOAuthApplicationsParams) error {
+	var request ListOAuthApplicationsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListOAuthApplications(ctx.Request().Context(), request.(ListOAuthApplicationsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListOAuthApplications")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListOAuthApplicationsResponseObject); ok {
+		return validResponse.VisitListOAuthApplicationsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // CreateOAuthApplication operation middleware
 func (sh *strictHandler) CreateOAuthApplication(ctx echo.Context, params CreateOAuthApplicationParams) error {
 	var request CreateOAuthApplicationRequestObject
@@ -276,3 +1273,113 @@ func (sh *strictHandler) DeleteOAuthApplication(ctx echo.Context, id string, par
 	}
 	return nil
 }
+
+// GetOAuthApplication operation middleware
+func (sh *strictHandler) GetOAuthApplication(ctx echo.Context, id string, params GetOAuthApplicationParams) error {
+	var request GetOAuthApplicationRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetOAuthApplication(ctx.Request().Context(), request.(GetOAuthApplicationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetOAuthApplication")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetOAuthApplicationResponseObject); ok {
+		return validResponse.VisitGetOAuthApplicationResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// RevokeAPIProductAccess operation middleware
+func (sh *strictHandler) RevokeAPIProductAccess(ctx echo.Context, id string, params RevokeAPIProductAccessParams) error {
+	var request RevokeAPIProductAccessRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeAPIProductAccess(ctx.Request().Context(), request.(RevokeAPIProductAccessRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeAPIProductAccess")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(RevokeAPIProductAccessResponseObject); ok {
+		return validResponse.VisitRevokeAPIProductAccessResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GrantAPIProductAccess operation middleware
+func (sh *strictHandler) GrantAPIProductAccess(ctx echo.Context, id string, params GrantAPIProductAccessParams) error {
+	var request GrantAPIProductAccessRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body GrantAPIProductAccessJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GrantAPIProductAccess(ctx.Request().Context(), request.(GrantAPIProductAccessRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GrantAPIProductAccess")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GrantAPIProductAccessResponseObject); ok {
+		return validResponse.VisitGrantAPIProductAccessResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// RotateOAuthApplicationSecret operation middleware
+func (sh *strictHandler) RotateOAuthApplicationSecret(ctx echo.Context, id string, params RotateOAuthApplicationSecretParams) error {
+	var request RotateOAuthApplicationSecretRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.RotateOAuthApplicationSecret(ctx.Request().Context(), request.(RotateOAuthApplicationSecretRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RotateOAuthApplicationSecret")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(RotateOAuthApplicationSecretResponseObject); ok {
+		return validResponse.VisitRotateOAuthApplicationSecretResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
@@ -18,24 +18,55 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RWbW/bNhD+KwduH1rAkRzHWW1/S+OtMIa1wdJ+CgyUFk8WG4lUyZNTI/B/H45UbMnO",
-	"uhV7KRAgsnTvz3MP+SgyW9XWoCEvZo/CZwVWMjz+7Jx1/FA7W6MjjeF1ZhXyf9rWKGZCG8I1OrEbiAq9",
-	"l+vwEb/Iqi75+y1JajxcW4XwW2sweHL25LRZs69D6a3pu4YCoP1y4hOcPjfaoRKzu1jWoYZ9xOVuIN5d",
-	"NVRc1XWpM0k6pjlqqtRoaKH6BcjhZPoKf1JDlNNxfjmZZheT0avJKpNjnF6MR881EiO9ldXRHNqns8aj",
-	"O6utLc8UbrDkIs7O/zzQLWYOqR8qm47VSl1ORupyOsaJlMPx9GKcT7NXmA/zy9Ffz+qp26Msyx1bapNb",
-	"TqjQZ07XcWDiNebWIWxtAytcazMAjwRNDW9Ka+GNJHyQW7ixjmQJD5oKNnXwrkazmMO1NQYzghfvFvPr",
-	"l1A7u9EKXQLvCzTRnArtYTG/2dte3SwGIV8mDVTSyDUCFQixZA/SKLaBG2dVk5EHbdqci/n1PgVQIQnW",
-	"SCC9t5mWhKpTX1uwPJDDJ/CLdVBxtzwKV4XX3G/Mf/dcx8pmfvmiIKr9LE35V+JtaRNt03Vp7VkdzNJS",
-	"EnpKXyaMkaYA53PhjuYgBmKDzkckzpNhMmSG2BqNrLWYiYvwaiBqSUXgc9ptKNDdejoF9dohFwTSdCcA",
-	"NgcmEFjZUDFikLQH/jOERqECsrDC8HPtDgOVJsANC7Wv/aaHAk+v25lmQldoKKbdx/MhXgJvLeHB82OX",
-	"qx+5HoMbdODJOlSMPlspSXIlPQZ6aA++sA+GC+aPvHxgTbkFazJM4FfEOhLPh6hsV8l7hLyhxiHw0qAn",
-	"/+TPdKs7dON3ETEGlHc5dMI60s72RHoYJCcrJHRezO6OEXlv7zEAYJ1eaxMr1mZj77VZh3xtTZxQs0eB",
-	"UqETA2GC5gjiCGLQKnlHqfdisIxqgJ5eW7WNms7IBoJ0eJB+aiX5EKqvmvof6+WRMGkllnsbu/qEGYmg",
-	"Sf0hvfi99XkJH4z+3CBohYZ0rtFBbh1kPHseWKRMIrppyDUY8vraGh87GQ3Pv2kOPzrMxUz8kB5Oz7Q9",
-	"OtMTyJ/p4LbJMvQ+b8pyG6tFtS92NxDj4fBfqyee488UsTAbWWrenLqJaS//j7QfDH6pMeOOMZzwJ2ix",
-	"j2+qSrrt3xYp5pZc80qJq672LTlYTw7TR612UQtLJDxVxXl4z/li7G7a0z2P1t+65x0KL+aBs93eyEIs",
-	"br/lrOyHHdfqhNFfW/jBd1OZ3oqNT0fd24PYsuqPOyzD+L9nZQc7MJYgt41R33EpwjAYjf40epsRqXey",
-	"Ge3BdHT1ejqKv7YpHB3d5omyjSsZ+vZSI2udxLtMKYnvRe2lJslslW7OxW65+yMAAP//yLBov04MAAA=",
+	"H4sIAAAAAAAC/+xcbXPbNvL/Kjv8/2cumaEl23Ha2PfKtXs53V0bT+JOX2QyE5hYSahJgAVAybqMv/vN",
+	"AuAzLeXBdROXr2yReFgsdvf34y7ID1GislxJlNZEJx8ikywxY+7f04vZhVa8SCz9yrXKUVuB7h5Hk2iR",
+	"W6Ek/cQbluUpRifRpWbJtYHXP765hNOLWRRHdpPTDWO1kIvoNo4Eb3exrsueRmP3WC76XW7jSOPvhdDI",
+	"o5O31P/dbRz9qLXSfckSxZH+hjGEtLhATfNmaAxbYHvyN5bZwsCZ4gg/hQYDMmtkprtUJwCEO7uEdmLV",
+	"MlQj0kpenRZ2eZrnqUhYqdHOolKB0s46imP7L46/x+/4PrLjo/nzF8fJsxeH37+4StgRHj87OhxaiB/p",
+	"Z5Z19BD+2ysM6r1cqXSP4wpTEmLv4O6B3mCi0baHSo6P+BV//uKQPz8+wheM7R8dPzuaHyff43x//vxw",
+	"t67K1XZmGdLVOVomUvNNqMwkKh9wn+iNuw52ySzYJYKfEISBhWbSIo/BFMkSmIG50q7J6cUMcu+coaOw",
+	"kDAJLEnQmEkU14K+7XnYuzgSFjPT8JNaynCBac0227YmrGZoUy6Cm7V3hNUN3O9KhP/XOI9Oov+b1qFo",
+	"GuLQ9K797gkaRxJv7FmhjdJ9FfvrlfqoKeRsgaDmQd1mAq8yYS1yUNI1SpnxjSY7Lba1tHe3dFvIuerL",
+	"8QPOlUbYqAKucCFkDAYtFDm8TJWCC6UtS2Et7JKaaHiVo5ydw5mSEhMLT17Nzs+ewoyjtMJu4EKrleCo",
+	"4cmMXzyNgUkOQhrL0hRm/KLqZxW4OKiZRTKUegI37Utmcc02ZVe3jAlcLlHGTlKyq8Ig2KUwrXHJCq2C",
+	"jElSZW26BoQMC5idn0FeiukMlWkEZoxKBCNl17KE1bM8NzS7MLBmm7h1j1zNOHncmEb4rRIa1FqCsxVI",
+	"NDr9sNSQcN4hnHCtCf6hNGS0F7RROnOLpt3w63gb2vohF4Xg+O7J0trcnEynXCVmYlSqJkJNF15505RZ",
+	"NHaau35T18NM51pJi5Lvhcu1bHteaRlKO1WssMvpU1Iazd3aEzcX2Z+wLug07aSzFVEcrVAbb2gHk/3J",
+	"PnmFylGSz59Ez9ylOMqZXTq/m7Jc7JVRhC7kyti+zb7GhTAWNTDZjDuluK0tjsEov81Oc4eVQdCekYo0",
+	"XGEZ1qq9USDsBP6p1t3YBsKAxlyjQdeBY46Sm9JDG7OG+MggKYxVGbjoRO0YnKmFFFaBRqMKnSAY1Cvq",
+	"xOpLQsK/cZOkil3HoPTQOBJeXVsGtFdKi/86gwlD0QZRpHPXCHKiM43MYoNBkdo1y9CiNtHJ266OL9U1",
+	"SgpGSouFcO6mQciVuhZy4dZK4QaNncCvS/SrT/zWK01aSpSci0WhkZM6V6jFfAOWRjWxd9ysMJaUz+Bf",
+	"v16CEQuJHK42wMDqwpB2r3ETe38Mw5cjCmMKt/8cWMEFysTFREGSL5Fx1FEcSQeQkZvTwQMF8AGEuX3n",
+	"Yyca+4PiG8/ZyEtsBySmvwXKVQ+1DSkaynYBuK3gJ69DuH7asi+rKF4w69ZTR3SrC3Qh3uRKGg9fh/sH",
+	"DyTrm8L5xbxI000QjzelnpBfH+3v35s4nkoPSDKTK5YKApW8KKc9+OOn/UkYI+QiBhHmVxrwJhfeuq9R",
+	"BlGe/fGieMcUBqSykKMOFKFGvZI9eImO/3iJmubLUo2MbwBvhCllOHwAGS67cd8FamYRUpEJSzEruLjx",
+	"rMRYkaawpgg9t44J4AbWqBE0Wi2QO9mfP4RN/yLxJseEdhGpjfcwErnrYs/3n/1JqiwkWzGRsqsUh/VX",
+	"arerQ3rKKLKM6U0FQqaD245NsAWhkLOlEIgcb43bnGD6QfBbTwhStNinBufuemcCmGuV9alBHyR9748H",
+	"yUYMn50TWHbZglXgJa3QibhOjU2C96L8NqCK//Io3YK/o4En1yZSedUPIdUIGVsg4+hhIYOEmqtC8hEt",
+	"PgstnJU/RrQYjuVDD3k7EaSd51ngwEPlf4Txya7Oc+LQfHXewDuQC4iNh98JnLneYFye0LimEldu5bbQ",
+	"EvkEXqMp0nAvZwshiVX/HXIWkgPv6/TRewrxzOeHWLib1HeqBFJQKxTSihSkao8hTGP2Uz8aPQWTgTAh",
+	"YY5rvzQJ750fvK+UgCuUsCYEcRkK6kqpvzRV6z6Kkia7WTKzC0p/YjciKzKQRXaFupEFA6uC2BV2/F6g",
+	"3tTQ4WRtQQfHOStSG50c7sdR5keOTr6jH0L6HwdxLyHfh9dXOfu9QPCqrpRXbUFng3wCAFdCFaZK0w0J",
+	"7MeLRqT/aKS/v7g6mBne9cibCrf8NkyOT7tfHXUZucMncgcybJK4qcVvnTY4IN+G3i220K6TxHdknP2D",
+	"q0u5NtnBFnJAZYpQSbGK7llMbKPEETLmpxezsrbguQLpwSypeGCVG0TJdANKJgjMhsyAkmBFhi61fY2Y",
+	"g230d65xjTAvbKGxVplV7RpdEN3LMYHZ3M2WKoPN4UK1g/FMSEcWvNJXrmozqFz4WVmsS4f1qsiDjVUO",
+	"LppzA2eWXTGDEzgrEx+sUXIUHLNckS0SGL0X/L2TtmrSTjlVFTVqGINUjZHKzCWZ1tH+cZMQ1WUCWuDG",
+	"y1DSqfWStFJCkrPHVBkLXKFfVopsRbjJC29NVcX0CpdCctrgShOBKDhpa9dzurUE3FcIWlkWopy6ctRM",
+	"4rpZRrorvd+r249J/vtI8rerxuKLK/gDx0eqNurqN9xZMPhFCuKlwtnDXKCvI1dpQ29Sk0aN0pC7OiI9",
+	"R03/NKv6ZR2sDBUPW3no2ezH1h/CKkcy9lcuPewAATEmlb6wBNHwskdUfWixuC18sJs9+oT6Q3uKkBAK",
+	"R6Oa5yS44M6fkkAxNbqffgIeQyHT8rjIFuidK51gOHniugol76pvfCpLaFc5HNJ4p6urG57BClMxr5oA",
+	"NtTnNqwM34T4MayXIllSx3CKwTIrVujPDZl+xqU++VZlXEoFX7YUpNGodFXhXhvuBkjrZT36jHfGduKl",
+	"xp/cyS3yioWMxZyHL+a0YH/E3yH8dQeGGva7Zr5L6XqdZPXDVX3qIceqz31VfR4RQJfguTVUb0veDBZ2",
+	"XqLtJ25IRhrbNE46i+YJ5wAozTIO3e0UcXoI+xLtl8FrG3msggXaEV5HeP16KyjVAfgdz+1l5nCE8K/r",
+	"KMYIyvcGysHEHxksOwD9TEwefIDuHfO/62n6Na7Udf9p+m+mcVBfSYTyzYlmgWMy8EpSA3QSJsnawzhM",
+	"I4iFVHoI070U9anAU9fnC2Fdh6X5+emA4ojy94Hyw8fqTUPltUxNO/IvqOWpe1nTzzp0dIPlojpctE3O",
+	"u99my4Sc+ZsH/TfGjN24mgK9ABSNFObTMgR+f5sv8XRfTByLBSPTGZnO/TCdEHTK13sfAc8p2cYQy2hH",
+	"kW3nR4qhFIRmcigJsZPE1O8RUgjmvAzzjTZVCuMjAHoXI6rKaA1alOLcQiGTJZOLwYwHre2eydHC66vC",
+	"5pEZjfmPP+ewR5PvnXz47E8YbCF9vRf36wk/8VRIl+96L2oYWovs7jrhsYtt9V+ZHtnWyLaG2ZbSML7x",
+	"c3/ky7neIyNfgSL1qdFHMq/hRJOpvgZ0x5ck3EnL5kFVNe/xNA/fdARz1xHd+kSu+OyDuJfNt0jChMaq",
+	"3MBaaYLqCZy7j06QAajBL140Qn4mFksHyRqbzAiYW09oNDt3grN0zTYmfFgFa3ZUtRpIjTn9dSsC4etI",
+	"X5ggG9iakQiORPDrLYTtroCV57q7leWRsowJoseWICJbL+NJ39i/+VzRIHH43CIZDe2+2+RxstApBbvw",
+	"cS+Wi8kiVWovT5mlwkD4dtckUdl0dRDdvrv93wDYceFsNFMAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
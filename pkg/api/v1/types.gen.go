@@ -3,6 +3,12 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package v1
 
+// APIProduct defines model for APIProduct.
+type APIProduct struct {
+	Description *string `json:"description,omitempty"`
+	Id          string  `json:"id"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Code    int    `json:"code"`
@@ -17,6 +23,47 @@ type OAuthApplication struct {
 	ClientSecret string  `json:"clientSecret"`
 }
 
+// OAuthApplicationDetails defines model for OAuthApplicationDetails.
+type OAuthApplicationDetails struct {
+	ClientId   string  `json:"clientId"`
+	ClientName *string `json:"clientName,omitempty"`
+
+	// Scopes Scopes that the client is granted, such as for the API products that it can access.
+	Scopes []string `json:"scopes"`
+}
+
+// OAuthApplicationPage defines model for OAuthApplicationPage.
+type OAuthApplicationPage struct {
+	Applications []OAuthApplicationDetails `json:"applications"`
+
+	// NextCursor Cursor for the next page of clients. Omitted on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// CreateAPIProductParams defines parameters for CreateAPIProduct.
+type CreateAPIProductParams struct {
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// DeleteAPIProductParams defines parameters for DeleteAPIProduct.
+type DeleteAPIProductParams struct {
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// ListOAuthApplicationsParams defines parameters for ListOAuthApplications.
+type ListOAuthApplicationsParams struct {
+	// Limit Maximum number of clients to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as the `nextCursor` of the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
 // CreateOAuthApplicationJSONBody defines parameters for CreateOAuthApplication.
 type CreateOAuthApplicationJSONBody struct {
 	Id string `json:"id"`
@@ -24,15 +71,53 @@ type CreateOAuthApplicationJSONBody struct {
 
 // CreateOAuthApplicationParams defines parameters for CreateOAuthApplication.
 type CreateOAuthApplicationParams struct {
-	// Token Token of origin user invoking the request.
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
 	Token *string `json:"token,omitempty"`
 }
 
 // DeleteOAuthApplicationParams defines parameters for DeleteOAuthApplication.
 type DeleteOAuthApplicationParams struct {
-	// Token Token of origin user invoking the request.
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// GetOAuthApplicationParams defines parameters for GetOAuthApplication.
+type GetOAuthApplicationParams struct {
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// RevokeAPIProductAccessParams defines parameters for RevokeAPIProductAccess.
+type RevokeAPIProductAccessParams struct {
+	// ApiProducts (Required) API products to revoke the client's access to.
+	ApiProducts []string `form:"apiProducts" json:"apiProducts"`
+
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// GrantAPIProductAccessJSONBody defines parameters for GrantAPIProductAccess.
+type GrantAPIProductAccessJSONBody struct {
+	ApiProducts []string `json:"apiProducts"`
+}
+
+// GrantAPIProductAccessParams defines parameters for GrantAPIProductAccess.
+type GrantAPIProductAccessParams struct {
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
 	Token *string `json:"token,omitempty"`
 }
 
+// RotateOAuthApplicationSecretParams defines parameters for RotateOAuthApplicationSecret.
+type RotateOAuthApplicationSecretParams struct {
+	// Token Token of origin user invoking the request. When the connector is configured to verify tokens, this must be a JWT signed by a trusted key, with the configured issuer and audience.
+	Token *string `json:"token,omitempty"`
+}
+
+// CreateAPIProductJSONRequestBody defines body for CreateAPIProduct for application/json ContentType.
+type CreateAPIProductJSONRequestBody = APIProduct
+
 // CreateOAuthApplicationJSONRequestBody defines body for CreateOAuthApplication for application/json ContentType.
 type CreateOAuthApplicationJSONRequestBody CreateOAuthApplicationJSONBody
+
+// GrantAPIProductAccessJSONRequestBody defines body for GrantAPIProductAccess for application/json ContentType.
+type GrantAPIProductAccessJSONRequestBody GrantAPIProductAccessJSONBody
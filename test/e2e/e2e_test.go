@@ -13,11 +13,11 @@ import (
 	v1 "github.com/solo-io/gloo-portal-idp-connect/pkg/api/v1"
 )
 
-// Note: This test uses Cognito as the identity provider. Cognito's ClientId is generated by cognito, so we store it in the clientId variable and use that in subsequent tests.
-// If these were in Keycloak, because the ClientID is provided by the user, we would not need to store it in a variable and could simply use the 'internalClientId' variable instead.
+// Note: This test uses Cognito as the identity provider, which generates the ClientId of a client. Every connector
+// resolves the id that the client was created with to the client in the IdP, so later requests use that id.
 var _ = Describe("E2e", Ordered, func() {
 	var (
-		// clientId is the ID of the client we create
+		// clientId is the ID of the client we create, as generated by the IdP
 		clientId string
 		// internalClientId is the user-defined unique Id. In Portal's case, it will be the ID of the oauth credential table entry.
 		internalClientId string
@@ -48,13 +48,45 @@ var _ = Describe("E2e", Ordered, func() {
 		Expect(json.Unmarshal([]byte(out), &createObj)).To(Succeed())
 		Expect(createObj.ClientName).ToNot(BeNil())
 		Expect(*createObj.ClientName).To(Equal(internalClientId))
-		Expect(createObj.ClientId).ToNot(BeNil())
+		Expect(createObj.ClientId).ToNot(BeEmpty())
 		clientId = createObj.ClientId
 	})
 
+	It("does not create a second client for the same id", func() {
+		curlFromPod := &utils_test.CurlFromPod{
+			Url:     "idp-connect/applications",
+			Cluster: env,
+			Method:  "POST",
+			Data:    fmt.Sprintf(`{"id": "%s"}`, internalClientId),
+			Verbose: true,
+			App:     "curl",
+			Headers: []string{"Content-Type: application/json"},
+		}
+
+		out, err := curlFromPod.Execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("409 Conflict"))
+	})
+
+	It("can get client", func() {
+		curlFromPod := &utils_test.CurlFromPod{
+			Url:     "idp-connect/applications/" + internalClientId,
+			Cluster: env,
+			Method:  "GET",
+			App:     "curl",
+		}
+
+		out, err := curlFromPod.Execute()
+		Expect(err).NotTo(HaveOccurred())
+
+		var getObj v1.GetOAuthApplication200JSONResponse
+		Expect(json.Unmarshal([]byte(out), &getObj)).To(Succeed())
+		Expect(getObj.ClientId).To(Equal(clientId))
+	})
+
 	It("can delete client", func() {
 		curlFromPod := &utils_test.CurlFromPod{
-			Url:     "idp-connect/applications/" + clientId,
+			Url:     "idp-connect/applications/" + internalClientId,
 			Cluster: env,
 			Method:  "DELETE",
 			Verbose: true,